@@ -1,21 +1,84 @@
 package actor
 
+///
+/// actor
+///
+/// 请求投递到收件箱, 由Serve在同一个协程中依次执行
+///   - Call: 等待执行的结果, 受ctx和超时控制
+///   - Cast: 不等待结果, 收件箱满时按策略阻塞或者丢弃
+/// 请求panic时不会影响其他请求, Call会返回ErrActorPanic, 然后调用重启钩子, 钩子返回错误时Serve退出
+/// 高优先级的请求放在单独的收件箱, 优先处理
+/// 设置了批量处理时, 每次最多取出BatchSize个请求, 在同一次拦截器调用中执行
+///
 import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
 )
 
 type Request interface {
 	Next()
 }
 
+// 嵌入了*Actor的类型都实现了这个接口
+type Mailbox interface {
+	Inbox() chan Request
+	mailbox() *Actor
+}
+
 type Actor struct {
-	__sync_ch__ chan Request
+	__sync_ch__     chan Request
+	__priority_ch__ chan Request
+	options         ActorOptions
+	done            chan struct{}
+	doneOnce        sync.Once
+
+	processed int64
+	dropped   int64
+	panics    int64
+	restarts  int64
+	mu        sync.Mutex
+	latency   latency_stats
+}
+
+type latency_stats struct {
+	last  time.Duration
+	max   time.Duration
+	total time.Duration
+}
+
+// 收件箱和处理耗时的统计
+type ActorStats struct {
+	Depth       int           // 两个收件箱中等待的请求数
+	Capacity    int           // 两个收件箱的容量之和
+	Processed   int64         // 处理的请求数
+	Dropped     int64         // 收件箱满时丢弃的请求数
+	Panics      int64         // panic的次数
+	Restarts    int64         // 重启的次数
+	LastLatency time.Duration // 最近一次请求的处理时间
+	MaxLatency  time.Duration
+	AvgLatency  time.Duration
 }
 
-func NewActor(size int) *Actor {
-	return &Actor{
-		__sync_ch__: make(chan Request, size),
+func NewActor(size int, opt ...ActorOption) *Actor {
+	self := &Actor{
+		__sync_ch__:     make(chan Request, size),
+		__priority_ch__: make(chan Request, size),
+		done:            make(chan struct{}),
 	}
+	for _, v := range opt {
+		v.Config(&self.options)
+	}
+	if self.options.BatchSize <= 0 {
+		self.options.BatchSize = 1
+	}
+	return self
 }
 
 // 返回收件箱chan
@@ -23,10 +86,323 @@ func (self *Actor) Inbox() chan Request {
 	return self.__sync_ch__
 }
 
+// 返回高优先级的收件箱chan
+func (self *Actor) PriorityInbox() chan Request {
+	return self.__priority_ch__
+}
+
+func (self *Actor) mailbox() *Actor {
+	return self
+}
+
+func (self *Actor) inbox(priority int) chan Request {
+	if priority == PRIORITY_HIGH {
+		return self.__priority_ch__
+	}
+	return self.__sync_ch__
+}
+
+// 依次处理收件箱中的请求, ctx取消或者重启钩子返回错误时退出, 退出后Call和Cast返回ErrActorStopped
+func (self *Actor) Serve(ctx context.Context) error {
+	defer self.doneOnce.Do(func() {
+		close(self.done)
+	})
+	batch := make([]Request, 0, self.options.BatchSize)
+	for {
+		var r Request
+		select {
+		case r = <-self.__priority_ch__:
+		default:
+			select {
+			case r = <-self.__priority_ch__:
+			case r = <-self.__sync_ch__:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		batch = append(batch[:0], r)
+		for len(batch) < self.options.BatchSize {
+			if r := self.poll(); r == nil {
+				break
+			} else {
+				batch = append(batch, r)
+			}
+		}
+		if err := self.process(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// 不阻塞地取出一个请求, 优先取高优先级的
+func (self *Actor) poll() Request {
+	select {
+	case r := <-self.__priority_ch__:
+		return r
+	default:
+	}
+	select {
+	case r := <-self.__sync_ch__:
+		return r
+	default:
+		return nil
+	}
+}
+
+func (self *Actor) Stats() ActorStats {
+	self.mu.Lock()
+	latency := self.latency
+	self.mu.Unlock()
+	stats := ActorStats{
+		Depth:       len(self.__sync_ch__) + len(self.__priority_ch__),
+		Capacity:    cap(self.__sync_ch__) + cap(self.__priority_ch__),
+		Processed:   atomic.LoadInt64(&self.processed),
+		Dropped:     atomic.LoadInt64(&self.dropped),
+		Panics:      atomic.LoadInt64(&self.panics),
+		Restarts:    atomic.LoadInt64(&self.restarts),
+		LastLatency: latency.last,
+		MaxLatency:  latency.max,
+	}
+	if stats.Processed > 0 {
+		stats.AvgLatency = latency.total / time.Duration(stats.Processed)
+	}
+	return stats
+}
+
+// 不等待结果, 收件箱满时默认阻塞
+func (self *Actor) Cast(f func(), opt ...CastOption) error {
+	var opts CastOptions
+	for _, v := range opt {
+		v.Config(&opts)
+	}
+	if self.isStopped() {
+		return errors.ErrActorStopped
+	}
+	r := &cast_request{f: f}
+	inbox := self.inbox(opts.Priority)
+	select {
+	case inbox <- r:
+		return nil
+	default:
+	}
+	if opts.FullPolicy == CAST_FULL_DROP {
+		atomic.AddInt64(&self.dropped, 1)
+		return errors.ErrActorMailboxFull
+	}
+	var timeout <-chan time.Time
+	if opts.TimeOut != 0 {
+		timer := time.NewTimer(opts.TimeOut)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case inbox <- r:
+		return nil
+	case <-self.done:
+		return errors.ErrActorStopped
+	case <-timeout:
+		atomic.AddInt64(&self.dropped, 1)
+		return errors.ErrActorMailboxFull
+	}
+}
+
+// 在actor的协程中执行f并等待结果
+func Call[T any](ctx context.Context, mailbox Mailbox, f func() (T, error), opt ...CallOption) (result T, err error) {
+	self := mailbox.mailbox()
+	var opts CallOptions
+	for _, v := range opt {
+		v.Config(&opts)
+	}
+	if opts.TimeOut != 0 {
+		var cancelFunc context.CancelFunc
+		ctx, cancelFunc = context.WithTimeout(ctx, opts.TimeOut)
+		defer cancelFunc()
+	}
+	if self.isStopped() {
+		err = errors.ErrActorStopped
+		return
+	}
+	r := &call_request[T]{
+		f:      f,
+		caller: make(chan struct{}),
+	}
+	select {
+	case self.inbox(opts.Priority) <- r:
+	case <-self.done:
+		err = errors.ErrActorStopped
+		return
+	case <-ctx.Done():
+		err = callError(ctx)
+		return
+	}
+	select {
+	case <-r.caller:
+		return r.result, r.err
+	case <-self.done:
+		err = errors.ErrActorStopped
+		return
+	case <-ctx.Done():
+		err = callError(ctx)
+		return
+	}
+}
+
+func (self *Actor) isStopped() bool {
+	select {
+	case <-self.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func callError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.ErrActorCallTimeOut
+	}
+	return ctx.Err()
+}
+
+// 执行一批请求, 重启钩子返回错误时剩下的请求不再执行
+func (self *Actor) process(batch []Request) (err error) {
+	run := func() {
+		for _, r := range batch {
+			if err = self.run(r); err != nil {
+				return
+			}
+		}
+	}
+	if self.options.Interceptor != nil {
+		self.options.Interceptor(run)
+	} else {
+		run()
+	}
+	return
+}
+
+// 执行请求, 捕获panic后调用重启钩子
+func (self *Actor) run(r Request) (err error) {
+	start := time.Now()
+	defer func() {
+		d := time.Since(start)
+		atomic.AddInt64(&self.processed, 1)
+		self.mu.Lock()
+		self.latency.last = d
+		self.latency.total += d
+		if d > self.latency.max {
+			self.latency.max = d
+		}
+		self.mu.Unlock()
+		if e := recover(); e != nil {
+			atomic.AddInt64(&self.panics, 1)
+			log.Errorw("actor panic", "error", e, "stack", string(debug.Stack()))
+			err = self.restart(fmt.Errorf("%w: %v", errors.ErrActorPanic, e))
+		}
+	}()
+	r.Next()
+	return nil
+}
+
+func (self *Actor) restart(reason error) (err error) {
+	atomic.AddInt64(&self.restarts, 1)
+	if self.options.RestartHook == nil {
+		return nil
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			log.Errorw("actor restart hook panic", "error", e, "stack", string(debug.Stack()))
+			err = fmt.Errorf("%w: %v", errors.ErrActorPanic, e)
+		}
+	}()
+	return self.options.RestartHook(reason)
+}
+
+type cast_request struct {
+	f func()
+}
+
+func (r *cast_request) Next() {
+	r.f()
+}
+
+type call_request[T any] struct {
+	f      func() (T, error)
+	result T
+	err    error
+	caller chan struct{}
+}
+
+// panic时也通知调用者
+func (r *call_request[T]) Next() {
+	defer func() {
+		if e := recover(); e != nil {
+			r.err = fmt.Errorf("%w: %v", errors.ErrActorPanic, e)
+			close(r.caller)
+			panic(e)
+		}
+	}()
+	r.result, r.err = r.f()
+	close(r.caller)
+}
+
 // ======================== options =======================
+// actor选项
+type ActorOptions struct {
+	// panic之后调用, 返回错误时Serve退出
+	RestartHook func(reason error) error
+	// 包装每一批请求的执行, 例如加锁
+	Interceptor func(next func())
+	// 每批最多处理的请求数
+	BatchSize int
+}
+
+type ActorOption interface {
+	Config(opt *ActorOptions)
+}
+
+type RestartHookOption func(reason error) error
+
+// 设置重启钩子
+func WithRestartHook(f func(reason error) error) RestartHookOption {
+	return RestartHookOption(f)
+}
+
+func (self RestartHookOption) Config(opt *ActorOptions) {
+	opt.RestartHook = self
+}
+
+type InterceptorOption func(next func())
+
+// 设置请求的拦截器
+func WithInterceptor(f func(next func())) InterceptorOption {
+	return InterceptorOption(f)
+}
+
+func (self InterceptorOption) Config(opt *ActorOptions) {
+	opt.Interceptor = self
+}
+
+type BatchOption int
+
+// 设置每批最多处理的请求数
+func WithBatch(size int) BatchOption {
+	return BatchOption(size)
+}
+
+func (self BatchOption) Config(opt *ActorOptions) {
+	opt.BatchSize = int(self)
+}
+
+// 请求的优先级
+const (
+	PRIORITY_NORMAL = iota
+	PRIORITY_HIGH
+)
+
 // call选项
 type CallOptions struct {
-	TimeOut time.Duration
+	TimeOut  time.Duration
+	Priority int
 }
 
 type CallOption interface {
@@ -43,3 +419,64 @@ func WithCallTimeOut(timeout time.Duration) CallTimeOutOption {
 func (self CallTimeOutOption) Config(opt *CallOptions) {
 	opt.TimeOut = time.Duration(self)
 }
+
+type CallPriorityOption int
+
+// 设置优先级
+func WithCallPriority(priority int) CallPriorityOption {
+	return CallPriorityOption(priority)
+}
+
+func (self CallPriorityOption) Config(opt *CallOptions) {
+	opt.Priority = int(self)
+}
+
+// 收件箱满时cast的策略
+const (
+	CAST_FULL_BLOCK = iota // 阻塞, 直到有空间或者超时
+	CAST_FULL_DROP         // 丢弃并返回ErrActorMailboxFull
+)
+
+// cast选项
+type CastOptions struct {
+	FullPolicy int
+	TimeOut    time.Duration
+	Priority   int
+}
+
+type CastOption interface {
+	Config(opt *CastOptions)
+}
+
+type CastFullPolicyOption int
+
+// 设置收件箱满时的策略
+func WithCastFullPolicy(policy int) CastFullPolicyOption {
+	return CastFullPolicyOption(policy)
+}
+
+func (self CastFullPolicyOption) Config(opt *CastOptions) {
+	opt.FullPolicy = int(self)
+}
+
+type CastTimeOutOption time.Duration
+
+// 设置阻塞的超时时间, 超时后丢弃并返回ErrActorMailboxFull
+func WithCastTimeOut(timeout time.Duration) CastTimeOutOption {
+	return CastTimeOutOption(timeout)
+}
+
+func (self CastTimeOutOption) Config(opt *CastOptions) {
+	opt.TimeOut = time.Duration(self)
+}
+
+type CastPriorityOption int
+
+// 设置优先级
+func WithCastPriority(priority int) CastPriorityOption {
+	return CastPriorityOption(priority)
+}
+
+func (self CastPriorityOption) Config(opt *CastOptions) {
+	opt.Priority = int(self)
+}
@@ -0,0 +1,101 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+func TestCall(t *testing.T) {
+	var restarts []error
+	a := NewActor(1, WithRestartHook(func(reason error) error {
+		restarts = append(restarts, reason)
+		return nil
+	}))
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Serve(ctx)
+	}()
+	if v, err := Call(ctx, a, func() (int, error) {
+		return 42, nil
+	}); err != nil || v != 42 {
+		t.Fatalf("expected 42, got %v %v", v, err)
+	}
+	if _, err := Call(ctx, a, func() (string, error) {
+		panic("test panic")
+	}); !errors.Is(err, errors.ErrActorPanic) {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+	// 超时的请求一直执行到release关闭
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if _, err := Call(ctx, a, func() (int, error) {
+		close(started)
+		<-release
+		return 0, nil
+	}, WithCallTimeOut(10*time.Millisecond)); !errors.Is(err, errors.ErrActorCallTimeOut) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	<-started
+	held := time.Now()
+	// 上一个请求还在执行, 收件箱只有一个位置
+	if err := a.Cast(func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Cast(func() {}, WithCastFullPolicy(CAST_FULL_DROP)); !errors.Is(err, errors.ErrActorMailboxFull) {
+		t.Fatalf("expected mailbox full, got %v", err)
+	}
+	blocked := time.Since(held)
+	close(release)
+	if _, err := Call(ctx, a, func() (bool, error) {
+		return true, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	stats := a.Stats()
+	if stats.Processed != 5 || stats.Panics != 1 || stats.Restarts != 1 || stats.Dropped != 1 || stats.Capacity != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.MaxLatency < blocked {
+		t.Fatalf("unexpected max latency %v", stats.MaxLatency)
+	}
+	if len(restarts) != 1 {
+		t.Fatalf("expected 1 restart, got %v", restarts)
+	}
+	cancelFunc()
+	<-done
+	if err := a.Cast(func() {}); !errors.Is(err, errors.ErrActorStopped) {
+		t.Fatalf("expected stopped, got %v", err)
+	}
+}
+
+func TestBatchAndPriority(t *testing.T) {
+	var batches int
+	a := NewActor(4, WithBatch(3), WithInterceptor(func(next func()) {
+		batches++
+		next()
+	}))
+	order := make([]int, 0)
+	for i := 1; i <= 2; i++ {
+		i := i
+		a.Cast(func() { order = append(order, i) })
+	}
+	a.Cast(func() { order = append(order, 0) }, WithCastPriority(PRIORITY_HIGH))
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	go a.Serve(ctx)
+	if _, err := Call(ctx, a, func() (int, error) {
+		return len(order), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Fatalf("unexpected order %v", order)
+	}
+	if batches != 2 {
+		t.Fatalf("expected 2 batches, got %d", batches)
+	}
+}
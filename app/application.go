@@ -13,16 +13,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path"
 	"path/filepath"
 	gruntime "runtime"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/Lyndon-Zhang/gira/behaviorlog"
 	"github.com/Lyndon-Zhang/gira/codes"
+	"github.com/Lyndon-Zhang/gira/component"
 	"github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/cron"
 	"github.com/Lyndon-Zhang/gira/errors"
 	"github.com/Lyndon-Zhang/gira/gins"
 	"github.com/Lyndon-Zhang/gira/log"
@@ -31,6 +33,7 @@ import (
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/db"
 	"github.com/Lyndon-Zhang/gira/gate"
+	"github.com/Lyndon-Zhang/gira/graceful"
 	"github.com/Lyndon-Zhang/gira/grpc"
 	"github.com/Lyndon-Zhang/gira/platform"
 	"github.com/Lyndon-Zhang/gira/proj"
@@ -43,7 +46,6 @@ import (
 
 	_ "net/http/pprof"
 
-	"github.com/robfig/cron"
 	"golang.org/x/net/trace"
 	"golang.org/x/sync/errgroup"
 )
@@ -67,7 +69,7 @@ type Runtime struct {
 	errGroup           *errgroup.Group
 	resourceLoader     gira.ResourceLoader
 	resourceSource     gira.ResourceSource
-	config             *gira.Config
+	config             atomic.Value // *gira.Config, 重新加载时整体替换
 	chQuit             chan struct{}
 	status             int64
 	appVersion         string
@@ -86,6 +88,7 @@ type Runtime struct {
 	registry           *registry.Registry
 	registryClient     *registryclient.RegistryClient
 	dbClients          map[string]gira.DbClient
+	dbShardGroups      map[string]*db.ShardGroup
 	gameDbClient       gira.DbClient
 	logDbClient        gira.DbClient
 	behaviorDbClient   gira.DbClient
@@ -99,8 +102,14 @@ type Runtime struct {
 	platformSdk        *platform.PlatformSdk
 	gate               *gate.Server
 	grpcServer         *grpc.Server
+	grpcConnManager    *grpc.ConnManager
+	grpcCredentials    *grpc.Credentials
 	serviceContainer   *service.ServiceContainer
 	cron               *cron.Cron
+	reloadMu           sync.Mutex
+	restarting         int32
+	probe              *gins.Probe
+	components         *component.Manager
 }
 
 func newRuntime(args gira.ApplicationArgs) *Runtime {
@@ -120,6 +129,8 @@ func newRuntime(args gira.ApplicationArgs) *Runtime {
 		errGroup:         errGroup,
 		chQuit:           make(chan struct{}, 1),
 		serviceContainer: service.NewContainer(ctx),
+		probe:            gins.NewProbe(probe_check_timeout),
+		components:       component.NewManager(component_stop_timeout),
 	}
 	return runtime
 }
@@ -152,48 +163,52 @@ func (runtime *Runtime) init() error {
 	}
 	runtime.runConfigFilePath = filepath.Join(runtime.runDir, fmt.Sprintf("%s", runtime.appFullName))
 	runtime.logDir = proj.Dir.LogDir
+	// 由旧进程启动时, 接收旧进程的listener
+	if err := graceful.Inherit(); err != nil {
+		return err
+	}
 	// 初始化框架
 	if f, ok := application.(gira.ApplicationFramework); ok {
 		runtime.frameworks = f.OnFrameworkInit()
 	}
 	// 读应用配置文件
-	if c, err := proj.LoadApplicationConfig(runtime.appType, runtime.appId); err != nil {
+	c, err := proj.LoadApplicationConfig(runtime.appType, runtime.appId)
+	if err != nil {
 		return err
-	} else {
-		runtime.env = c.Env
-		runtime.zone = c.Zone
-		runtime.appFullName = gira.FormatAppFullName(runtime.appType, runtime.appId, runtime.zone, runtime.env)
-		runtime.config = c
 	}
+	runtime.env = c.Env
+	runtime.zone = c.Zone
+	runtime.appFullName = gira.FormatAppFullName(runtime.appType, runtime.appId, runtime.zone, runtime.env)
+	runtime.config.Store(c)
 	// 加载配置回调
 	for _, fw := range runtime.frameworks {
-		if err := fw.OnFrameworkConfigLoad(runtime.config); err != nil {
+		if err := fw.OnFrameworkConfigLoad(c); err != nil {
 			return err
 		}
 	}
-	if err := runtime.application.OnConfigLoad(runtime.config); err != nil {
+	if err := runtime.application.OnConfigLoad(c); err != nil {
 		return err
 	}
 	// 初始化日志
-	if runtime.config.CoreLog != nil {
-		if err = corelog.Config(*runtime.config.CoreLog); err != nil {
+	if c.CoreLog != nil {
+		if err = corelog.Config(*c.CoreLog); err != nil {
 			return err
 		}
-		if runtime.config.CoreLog.TraceErrorStack {
+		if c.CoreLog.TraceErrorStack {
 			codes.SetLogger(corelog.GetDefaultLogger())
 		}
 	}
-	if runtime.config.BehaviorLog != nil {
-		if err = behaviorlog.Config(*runtime.config.BehaviorLog); err != nil {
+	if c.BehaviorLog != nil {
+		if err = behaviorlog.Config(*c.BehaviorLog); err != nil {
 			return err
 		}
 	}
-	if runtime.config.Log != nil {
-		if err = log.Config(*runtime.config.Log); err != nil {
+	if c.Log != nil {
+		if err = log.Config(*c.Log); err != nil {
 			return err
 		}
 	}
-	gruntime.GOMAXPROCS(runtime.config.Thread)
+	gruntime.GOMAXPROCS(c.Thread)
 	return nil
 }
 
@@ -227,8 +242,8 @@ func (runtime *Runtime) start() (err error) {
 					runtime.stop()
 					corelog.Info("runtime interrupt end")
 					return errors.ErrInterrupt
-				case syscall.SIGUSR1:
-				case syscall.SIGUSR2:
+				case syscall.SIGUSR1, syscall.SIGUSR2:
+					runtime.onSignal(s)
 				default:
 				}
 			// 主动停止
@@ -260,6 +275,11 @@ func (runtime *Runtime) Stop() error {
 }
 
 func (runtime *Runtime) stop() {
+	runtime.probe.SetReady(false)
+	// 不再接收新的请求
+	if runtime.grpcServer != nil {
+		runtime.grpcServer.SetLifecycle(grpc.LIFECYCLE_DRAINING)
+	}
 	// runtime stop
 	runtime.application.OnStop()
 	// framework stop
@@ -271,23 +291,12 @@ func (runtime *Runtime) stop() {
 	}
 	// service stop
 	runtime.serviceContainer.Stop()
-	if runtime.registry != nil {
-		runtime.registry.Stop()
-	}
-	if runtime.grpcServer != nil {
-		runtime.grpcServer.Stop()
-	}
-	if runtime.httpServer != nil {
-		runtime.httpServer.Stop()
-	}
-	if runtime.gate != nil {
-		runtime.gate.Shutdown()
-	}
+	// component stop
+	runtime.components.Stop()
 	runtime.cancelFunc()
 }
 
 func (runtime *Runtime) onStart() (err error) {
-	application := runtime.application
 	defer func() {
 		if e := recover(); e != nil {
 			err = e.(error)
@@ -300,41 +309,9 @@ func (runtime *Runtime) onStart() (err error) {
 			runtime.errGroup.Wait()
 		}
 	}()
-	// ==== cron ================
-	runtime.cron.Start()
-
-	// ==== registryClient ================
-	if runtime.registryClient != nil {
-		if err = runtime.registryClient.StartAsClient(); err != nil {
-			return
-		}
-	}
-	// ==== registry ================
-	if runtime.registry != nil {
-		if err = runtime.registry.StartAsMember(); err != nil {
-			return
-		}
-	}
-	// ==== grpc ================
-	if runtime.grpcServer != nil {
-		if err := runtime.grpcServer.Listen(); err != nil {
-			return err
-		}
-		if runtime.registry != nil && runtime.config.Module.Grpc.Resolver {
-			if err := runtime.registry.StartReslover(); err != nil {
-				return err
-			}
-		}
-	}
-	// runtime.errGroup.Go(func() error {
-	// 	return runtime.registry.Serve(runtime.ctx)
-	// })
-	// }
-	// ==== http ================
-	if runtime.httpServer != nil {
-		runtime.errGroup.Go(func() error {
-			return runtime.httpServer.Serve()
-		})
+	// ==== component start ================
+	if err = runtime.components.Start(runtime.ctx); err != nil {
+		return
 	}
 	// ==== service ================
 	if runtime.grpcServer != nil {
@@ -352,7 +329,7 @@ func (runtime *Runtime) onStart() (err error) {
 				return
 			}
 		}
-		if runtime.config.Module.Grpc.Admin {
+		if runtime.GetConfig().Module.Grpc.Admin {
 			service := channelz_service.NewService()
 			if err = runtime.serviceContainer.StartService("channelz", service); err != nil {
 				return
@@ -369,65 +346,34 @@ func (runtime *Runtime) onStart() (err error) {
 	if err = runtime.application.OnStart(); err != nil {
 		return
 	}
-	// ==== grpc ================
+	// ==== readiness check ================
+	for _, fw := range runtime.frameworks {
+		if checker, ok := fw.(gira.ReadinessChecker); ok {
+			runtime.addReadinessChecks(checker)
+		}
+	}
+	if checker, ok := runtime.application.(gira.ReadinessChecker); ok {
+		runtime.addReadinessChecks(checker)
+	}
 	if runtime.grpcServer != nil {
-		runtime.errGroup.Go(func() error {
-			err := runtime.grpcServer.Serve(runtime.ctx)
-			return err
-		})
-	}
-	// ==== registry ================
-	if runtime.registry != nil {
-		runtime.errGroup.Go(func() error {
-			var peerWatchHandlers []gira.PeerWatchHandler
-			var localPlayerWatchHandlers []gira.LocalPlayerWatchHandler
-			var serviceWatchHandlers []gira.ServiceWatchHandler
-			for _, fw := range runtime.frameworks {
-				if handler, ok := fw.(gira.PeerWatchHandler); ok {
-					peerWatchHandlers = append(peerWatchHandlers, handler)
-				}
-				if handler, ok := fw.(gira.LocalPlayerWatchHandler); ok {
-					localPlayerWatchHandlers = append(localPlayerWatchHandlers, handler)
-				}
-				if handler, ok := fw.(gira.ServiceWatchHandler); ok {
-					serviceWatchHandlers = append(serviceWatchHandlers, handler)
-				}
-			}
-			if handler, ok := runtime.application.(gira.PeerWatchHandler); ok {
-				peerWatchHandlers = append(peerWatchHandlers, handler)
-			}
-			if handler, ok := runtime.application.(gira.LocalPlayerWatchHandler); ok {
-				localPlayerWatchHandlers = append(localPlayerWatchHandlers, handler)
-			}
-			if handler, ok := runtime.application.(gira.ServiceWatchHandler); ok {
-				serviceWatchHandlers = append(serviceWatchHandlers, handler)
-			}
-			return runtime.registry.Watch(peerWatchHandlers, localPlayerWatchHandlers, serviceWatchHandlers)
-		})
-	}
-	if runtime.gate != nil {
-		runtime.errGroup.Go(func() error {
-			var handler gira.GatewayHandler
-			if h, ok := application.(gira.GatewayHandler); ok {
-				handler = h
-			} else {
-				for _, fw := range runtime.frameworks {
-					if h, ok = fw.(gira.GatewayHandler); ok {
-						handler = h
-						break
-					}
-				}
-			}
-			if handler == nil {
-				return errors.ErrGateHandlerNotImplement
-			}
-			err := runtime.gate.Serve(handler)
-			return err
-		})
+		runtime.grpcServer.SetLifecycle(grpc.LIFECYCLE_SERVING)
+	}
+	// ==== component serve ================
+	for _, c := range runtime.components.Components() {
+		if server, ok := c.(gira.RuntimeComponentServer); ok {
+			runtime.errGroup.Go(func() error {
+				return server.Serve(runtime.ctx)
+			})
+		}
 	}
 	runtime.errGroup.Go(func() error {
 		return runtime.serviceContainer.Serve()
 	})
+	runtime.probe.SetReady(true)
+	// 由旧进程启动时, 通知旧进程退出
+	if err = graceful.Ready(); err != nil {
+		return
+	}
 	return nil
 }
 
@@ -436,130 +382,23 @@ func (runtime *Runtime) onCreate() error {
 	application := runtime.application
 
 	// ==== pprof ================
-	if runtime.config.Pprof.Port != 0 {
+	if pprof := runtime.GetConfig().Pprof; pprof.Port != 0 {
 		go func() {
-			corelog.Infof("pprof start at http://%s:%d/debug/pprof", runtime.config.Pprof.Bind, runtime.config.Pprof.Port)
-			if runtime.config.Pprof.EnabledTrace {
+			corelog.Infof("pprof start at http://%s:%d/debug/pprof", pprof.Bind, pprof.Port)
+			if pprof.EnabledTrace {
 				trace.AuthRequest = func(req *http.Request) (any, sensitive bool) {
 					return true, true
 				}
 			}
-			http.ListenAndServe(fmt.Sprintf("%s:%d", runtime.config.Pprof.Bind, runtime.config.Pprof.Port), nil)
+			http.ListenAndServe(fmt.Sprintf("%s:%d", pprof.Bind, pprof.Port), runtime.probe.Wrap(http.DefaultServeMux))
 		}()
 	}
-	// ==== cron ================
-	runtime.cron = cron.New()
-	// ==== registry ================
-	if runtime.config.Module.Etcd != nil {
-		if r, err := registry.NewConfigRegistry(runtime.ctx, runtime.config.Module.Etcd); err != nil {
-			return err
-		} else {
-			runtime.registry = r
-		}
-	}
-	// ==== registry client ================
-	if runtime.config.Module.EtcdClient != nil {
-		if r, err := registryclient.NewConfigRegistryClient(runtime.ctx, runtime.config.Module.EtcdClient, runtime.appId, runtime.appFullName); err != nil {
-			return err
-		} else {
-			runtime.registryClient = r
-		}
-	}
-
-	// ==== db ================
-	runtime.dbClients = make(map[string]gira.DbClient)
-	for name, c := range runtime.config.Db {
-		if client, err := db.NewConfigDbClient(runtime.ctx, name, *c); err != nil {
-			return err
-		} else {
-			runtime.dbClients[name] = client
-			if name == gira.GAMEDB_NAME {
-				runtime.gameDbClient = client
-			} else if name == gira.RESOURCEDB_NAME {
-				runtime.resourceDbClient = client
-			} else if name == gira.STATDB_NAME {
-				runtime.statDbClient = client
-			} else if name == gira.ACCOUNTDB_NAME {
-				runtime.accountDbClient = client
-			} else if name == gira.LOGDB_NAME {
-				runtime.logDbClient = client
-			} else if name == gira.BEHAVIORDB_NAME {
-				runtime.behaviorDbClient = client
-			} else if name == gira.ACCOUNTCACHE_NAME {
-				runtime.accountCacheClient = client
-			} else if name == gira.ADMINCACHE_NAME {
-				runtime.adminCacheClient = client
-			} else if name == gira.ADMINDB_NAME {
-				runtime.adminDbClient = client
-			} else if name == gira.GAMECACHE_NAME {
-				runtime.gameCacheClient = client
-			}
-		}
-	}
-
-	// ==== 加载resource ================
-	if resourceComponent, ok := runtime.application.(gira.ResourceSource); ok {
-		runtime.resourceSource = resourceComponent
-		resourceLoader := resourceComponent.GetResourceLoader()
-		if resourceLoader != nil {
-			runtime.resourceLoader = resourceLoader
-			if err := runtime.resourceLoader.LoadResource(runtime.ctx, runtime.resourceDbClient, path.Join("resource", "conf"), runtime.config.Resource.Compress); err != nil {
-				return err
-			} else {
-				resourceComponent.OnResourcePostLoad(false)
-			}
-		}
-	}
-
-	// ==== grpc ================
-	if runtime.config.Module.Grpc != nil {
-		if s, err := grpc.NewConfigServer(*runtime.config.Module.Grpc); err != nil {
-			return err
-		} else {
-			runtime.grpcServer = s
-		}
-	}
-
-	// ==== sdk================
-	if runtime.config.Module.Plat != nil {
-		runtime.platformSdk = platform.NewConfigSdk(*runtime.config.Module.Plat)
-	}
-
-	// ==== http ================
-	if runtime.config.Module.Http != nil {
-		if handler, ok := application.(gira.HttpHandler); !ok {
-			return errors.ErrHttpHandlerNotImplement
-		} else {
-			router := handler.HttpHandler()
-			if httpServer, err := gins.NewConfigHttpServer(runtime.ctx, *runtime.config.Module.Http, router); err != nil {
-				return err
-			} else {
-				runtime.httpServer = httpServer
-			}
-		}
+	// ==== component create ================
+	if err := runtime.registerComponents(); err != nil {
+		return err
 	}
-
-	// ==== gateway ================
-	if runtime.config.Module.Gateway != nil {
-		var handler gira.GatewayHandler
-		if h, ok := application.(gira.GatewayHandler); ok {
-			handler = h
-		} else {
-			for _, fw := range runtime.frameworks {
-				if h, ok = fw.(gira.GatewayHandler); ok {
-					handler = h
-					break
-				}
-			}
-		}
-		if handler == nil {
-			return errors.ErrGateHandlerNotImplement
-		}
-		if gate, err := gate.NewConfigServer(runtime.ctx, *runtime.config.Module.Gateway); err != nil {
-			return err
-		} else {
-			runtime.gate = gate
-		}
+	if err := runtime.components.Create(); err != nil {
+		return err
 	}
 
 	// ==== framework create ================
@@ -588,7 +427,10 @@ func (runtime *Runtime) Wait() error {
 // ================== implement gira.Application ==================
 // 返回配置
 func (runtime *Runtime) GetConfig() *gira.Config {
-	return runtime.config
+	if v := runtime.config.Load(); v != nil {
+		return v.(*gira.Config)
+	}
+	return nil
 }
 
 // 返回构建版本
@@ -718,6 +560,14 @@ func (runtime *Runtime) GetGrpcServer() gira.GrpcServer {
 	}
 }
 
+func (runtime *Runtime) GetGrpcConnManager() gira.GrpcConnManager {
+	if runtime.grpcConnManager == nil {
+		return nil
+	} else {
+		return runtime.grpcConnManager
+	}
+}
+
 // ================== implement gira.ResourceComponent ==================
 func (runtime *Runtime) GetResourceLoader() gira.ResourceLoader {
 	return runtime.resourceLoader
@@ -734,6 +584,22 @@ func (runtime *Runtime) BroadcastReloadResource(ctx context.Context, name string
 }
 
 // ================== implement gira.DbClientComponent ==================
+func (runtime *Runtime) GetDbClient(name string) gira.DbClient {
+	if client, ok := runtime.dbClients[name]; ok {
+		return client
+	} else {
+		return nil
+	}
+}
+
+func (runtime *Runtime) GetDbShardGroup(name string) gira.DbShardGroup {
+	if group, ok := runtime.dbShardGroups[name]; ok {
+		return group
+	} else {
+		return nil
+	}
+}
+
 func (runtime *Runtime) GetAccountDbClient() gira.DbClient {
 	if runtime.accountDbClient == nil {
 		return nil
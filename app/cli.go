@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Lyndon-Zhang/gira/facade"
+	"github.com/Lyndon-Zhang/gira/grpc/scatter"
 	"github.com/Lyndon-Zhang/gira/log"
 	"github.com/Lyndon-Zhang/gira/proj"
 	"github.com/Lyndon-Zhang/gira/service/admin/adminpb"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type ClientApplication struct {
@@ -59,6 +62,16 @@ func Cli(name string, appVersion string, buildTime string, application gira.Appl
 						Usage:    "service id",
 						Required: true,
 					},
+					&cli.BoolFlag{
+						Name:    "daemon",
+						Aliases: []string{"d"},
+						Usage:   "run in background",
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "wait for the daemon to start in seconds",
+						Value: 60,
+					},
 				},
 			},
 			{
@@ -89,11 +102,35 @@ func Cli(name string, appVersion string, buildTime string, application gira.Appl
 				Name:   "stop",
 				Usage:  "Stop service",
 				Action: stopAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "service id",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "wait for the service to stop in seconds",
+						Value: 30,
+					},
+				},
 			},
 			{
 				Name:   "restart",
-				Usage:  "Restart service",
+				Usage:  "Restart service without downtime",
 				Action: restartAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "service id",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "wait for the new process to be ready in seconds",
+						Value: 120,
+					},
+				},
 			},
 			{
 				Name:   "reload",
@@ -107,6 +144,61 @@ func Cli(name string, appVersion string, buildTime string, application gira.Appl
 					},
 				},
 			},
+			{
+				Name:   "services",
+				Usage:  "Display service status",
+				Action: servicesAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "service id",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "reload-config",
+				Usage:  "Reload config file",
+				Action: reloadConfigAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "service id",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:  "cluster",
+				Usage: "Cluster-wide admin operations",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "concurrency",
+						Usage: "max peers called at the same time, 0 means unlimited",
+					},
+					&cli.IntFlag{
+						Name:  "quorum",
+						Usage: "return after n peers succeed, 0 means wait for all peers",
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "timeout of each peer in seconds",
+						Value: 5,
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:   "status",
+						Usage:  "Health check all peers",
+						Action: clusterStatusAction,
+					},
+					{
+						Name:   "reload",
+						Usage:  "Reload resource on all peers",
+						Action: clusterReloadAction,
+					},
+				},
+			},
 			{
 				Name:   "version",
 				Usage:  "Build version",
@@ -181,6 +273,9 @@ func startAction(args *cli.Context) error {
 			buildTime = int64(t)
 		}
 	}
+	if args.Bool("daemon") {
+		return daemonize(appType, appId, time.Duration(args.Int("timeout"))*time.Second)
+	}
 	log.Println("build version:", appVersion)
 	log.Println("build time:", buildTime)
 	log.Infof("%s %d starting...", appType, appId)
@@ -195,6 +290,11 @@ func startAction(args *cli.Context) error {
 	if err := runtime.start(); err != nil {
 		return err
 	}
+	pid := os.Getpid()
+	if err := proj.WritePidFile(appType, appId, pid); err != nil {
+		log.Errorw("write pid file fail", "error", err)
+	}
+	defer proj.RemovePidFile(appType, appId, pid)
 	if err := runtime.Wait(); err != nil {
 		return err
 	}
@@ -246,30 +346,87 @@ func configAction(args *cli.Context) error {
 	return nil
 }
 
+// 停止应用, 等待节点注销并且进程退出
 func stopAction(args *cli.Context) error {
+	appId := int32(args.Int("id"))
+	appType, _ := args.App.Metadata["name"].(string)
+	pid, err := proj.ReadPidFile(appType, appId)
+	if os.IsNotExist(err) {
+		log.Printf("%s %d not running", appType, appId)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !proj.IsProcessAlive(pid) {
+		log.Printf("%s %d not running, remove stale pid file, pid %d", appType, appId, pid)
+		return proj.RemovePidFile(appType, appId, pid)
+	}
+	if err := StartAsClient(&ClientApplication{}, appId, "cli"); err != nil {
+		return err
+	}
+	appFullName := gira.FormatAppFullName(appType, appId, facade.GetZone(), facade.GetEnv())
+	if err := stopProcess(appFullName, pid, time.Duration(args.Int("timeout"))*time.Second); err != nil {
+		return err
+	}
+	log.Printf("%s stopped, pid %d", appFullName, pid)
 	return nil
 }
 
+// 零停机重启, 由正在运行的进程启动新的进程并交接端口
 func restartAction(args *cli.Context) error {
-	return nil
+	appId := int32(args.Int("id"))
+	appType, _ := args.App.Metadata["name"].(string)
+	if err := StartAsClient(&ClientApplication{}, appId, "cli"); err != nil {
+		return err
+	}
+	ctx := facade.Context()
+	appFullName := gira.FormatAppFullName(appType, appId, facade.GetZone(), facade.GetEnv())
+	if resp, err := adminpb.DefaultAdminClients.Unicast().
+		WherePeerFullName(appFullName).
+		WithTimeout(time.Duration(args.Int("timeout"))*time.Second).
+		Restart(ctx, &adminpb.RestartRequest{}); err != nil {
+		log.Println(err)
+		return nil
+	} else {
+		log.Printf("%s restarted, pid %d", appFullName, resp.Pid)
+		return nil
+	}
 }
 
+// 输出pid文件, 进程和节点健康检查的状态
 func statusAction(args *cli.Context) error {
 	appId := int32(args.Int("id"))
-
+	appType, _ := args.App.Metadata["name"].(string)
+	if pid, err := proj.ReadPidFile(appType, appId); err != nil {
+		log.Println("pid: none")
+	} else if proj.IsProcessAlive(pid) {
+		log.Printf("pid: %d running", pid)
+	} else {
+		log.Printf("pid: %d not running", pid)
+	}
 	if err := StartAsClient(&ClientApplication{}, appId, "cli"); err != nil {
 		return err
 	}
 	ctx := facade.Context()
-	serviceName := peer_service.GetServiceName()
-	if _, err := peerpb.DefaultPeerClients.Unicast().Where(serviceName).HealthCheck(ctx, &peerpb.HealthCheckRequest{}); err != nil {
+	appFullName := gira.FormatAppFullName(appType, appId, facade.GetZone(), facade.GetEnv())
+	if resp, err := peerpb.DefaultPeerClients.Unicast().WherePeerFullName(appFullName).HealthCheck(ctx, &peerpb.HealthCheckRequest{}); err != nil {
 		log.Println(err)
 		log.Println("dead")
 		return nil
 	} else {
-		log.Println("alive")
-		return nil
+		log.Printf("alive, version %s, uptime %s", resp.AppVersion, time.Duration(resp.UpTime)*time.Second)
 	}
+	// grpc.health.v1中的状态
+	if peer, err := facade.WhereIsPeer(appFullName); err != nil {
+		log.Println(err)
+	} else if conn, err := facade.GetGrpcConn(peer.Address); err != nil {
+		log.Println(err)
+	} else if resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		log.Println(err)
+	} else {
+		log.Println(resp.Status)
+	}
+	return nil
 }
 
 func unregisterAction(args *cli.Context) error {
@@ -304,6 +461,99 @@ func reloadAction(args *cli.Context) error {
 	}
 }
 
-func runAction(args *cli.Context) error {
+// 输出服务容器中全部服务的状态
+func servicesAction(args *cli.Context) error {
+	appId := int32(args.Int("id"))
+	appType, _ := args.App.Metadata["name"].(string)
+	if err := StartAsClient(&ClientApplication{}, appId, "cli"); err != nil {
+		return err
+	}
+	ctx := facade.Context()
+	appFullName := gira.FormatAppFullName(appType, appId, facade.GetZone(), facade.GetEnv())
+	if resp, err := adminpb.DefaultAdminClients.Unicast().WherePeerFullName(appFullName).ServiceStatus(ctx, &adminpb.ServiceStatusRequest{}); err != nil {
+		log.Println(err)
+		return nil
+	} else {
+		log.Printf("%-20s %-12s %-8s %-20s %s", "NAME", "STATE", "RESTART", "START", "LAST ERROR")
+		for _, v := range resp.Services {
+			log.Printf("%-20s %-12s %-8d %-20s %s", v.Name, v.State, v.RestartCount, time.Unix(v.StartTime, 0).Format("2006-01-02 15:04:05"), v.LastError)
+		}
+		return nil
+	}
+}
+
+// 重新加载配置文件, 输出生效和需要重启的修改
+func reloadConfigAction(args *cli.Context) error {
+	appId := int32(args.Int("id"))
+	if err := StartAsClient(&ClientApplication{}, appId, "cli"); err != nil {
+		return err
+	}
+	ctx := facade.Context()
+	serviceName := peer_service.GetServiceName()
+	if resp, err := adminpb.DefaultAdminClients.Unicast().Where(serviceName).ReloadConfig(ctx, &adminpb.ReloadConfigRequest{}); err != nil {
+		log.Println(err)
+		return nil
+	} else if len(resp.Rejected) > 0 {
+		for _, path := range resp.Rejected {
+			log.Printf("restart required: %s", path)
+		}
+		return nil
+	} else {
+		for _, path := range resp.Changed {
+			log.Printf("changed: %s", path)
+		}
+		log.Println("OK")
+		return nil
+	}
+}
+
+// 集群的所有节点都执行一次健康检查, 按返回的顺序输出
+func clusterStatusAction(args *cli.Context) error {
+	if err := StartAsClient(&ClientApplication{}, 0, "cli"); err != nil {
+		return err
+	}
+	ctx := facade.Context()
+	stream := peerpb.DefaultPeerClients.Broadcast().
+		WithConcurrency(args.Int("concurrency")).
+		WithQuorum(args.Int("quorum")).
+		WithTimeout(time.Duration(args.Int("timeout"))*time.Second).
+		HealthCheckGatherChan(ctx, &peerpb.HealthCheckRequest{})
+	for result := range stream.C {
+		if result.Err != nil {
+			log.Printf("%-20s %-20s dead %v", result.Peer.FullName, result.Peer.Address, result.Err)
+		} else {
+			log.Printf("%-20s %-20s alive", result.Peer.FullName, result.Peer.Address)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		log.Println(err)
+	}
 	return nil
 }
+
+// 集群的所有节点重新加载资源
+func clusterReloadAction(args *cli.Context) error {
+	if err := StartAsClient(&ClientApplication{}, 0, "cli"); err != nil {
+		return err
+	}
+	ctx := facade.Context()
+	err := adminpb.DefaultAdminClients.Broadcast().
+		WithConcurrency(args.Int("concurrency")).
+		WithQuorum(args.Int("quorum")).
+		WithTimeout(time.Duration(args.Int("timeout"))*time.Second).
+		ReloadResourceGather(ctx, &adminpb.ReloadResourceRequest{}, func(result *scatter.Result[*adminpb.ReloadResourceResponse]) {
+			if result.Err != nil {
+				log.Printf("%-20s %v", result.Peer.FullName, result.Err)
+			} else {
+				log.Printf("%-20s OK", result.Peer.FullName)
+			}
+		})
+	if err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func runAction(args *cli.Context) error {
+	return cli.ShowAppHelp(args)
+}
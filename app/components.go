@@ -0,0 +1,552 @@
+package app
+
+///
+/// 内置的运行时组件
+///
+/// 注册的顺序决定了没有依赖关系的组件的顺序, 停止时相反
+///   cron, trace, registry-client, db, resource, platform, gate, http, grpc, registry
+/// registry依赖grpc, 在grpc监听之后注册节点, 在grpc停止之前注销节点
+/// cron依赖registry, 单例的任务需要注册表加锁, 在注销节点之前停止
+///
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/db"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/gate"
+	"github.com/Lyndon-Zhang/gira/gins"
+	"github.com/Lyndon-Zhang/gira/grpc"
+	"github.com/Lyndon-Zhang/gira/grpc/policy"
+	"github.com/Lyndon-Zhang/gira/platform"
+	"github.com/Lyndon-Zhang/gira/proj"
+	"github.com/Lyndon-Zhang/gira/registry"
+	"github.com/Lyndon-Zhang/gira/registryclient"
+	gtrace "github.com/Lyndon-Zhang/gira/trace"
+)
+
+const (
+	component_stop_timeout = 10 * time.Second // 组件默认的停止超时时间
+)
+
+// 按配置注册内置的组件, 然后注册框架和应用的组件
+func (runtime *Runtime) registerComponents() error {
+	config := runtime.GetConfig()
+	components := []gira.RuntimeComponent{
+		&cron_component{runtime: runtime},
+		&trace_component{runtime: runtime},
+	}
+	if config.Module.EtcdClient != nil {
+		components = append(components, &registry_client_component{runtime: runtime})
+	}
+	components = append(components, &db_component{runtime: runtime}, &resource_component{runtime: runtime})
+	if config.Module.Plat != nil {
+		components = append(components, &platform_component{runtime: runtime})
+	}
+	if config.Module.Gateway != nil {
+		components = append(components, &gate_component{runtime: runtime})
+	}
+	if config.Module.Http != nil {
+		components = append(components, &http_component{runtime: runtime})
+	}
+	components = append(components, &grpc_component{runtime: runtime})
+	if config.Module.Etcd != nil {
+		components = append(components, &registry_component{runtime: runtime})
+	}
+	for _, fw := range runtime.frameworks {
+		if provider, ok := fw.(gira.RuntimeComponentProvider); ok {
+			components = append(components, provider.RuntimeComponents()...)
+		}
+	}
+	if provider, ok := runtime.application.(gira.RuntimeComponentProvider); ok {
+		components = append(components, provider.RuntimeComponents()...)
+	}
+	for _, c := range components {
+		if err := runtime.components.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (runtime *Runtime) GetComponent(name string) gira.RuntimeComponent {
+	return runtime.components.Get(name)
+}
+
+type cron_component struct {
+	runtime *Runtime
+}
+
+func (c *cron_component) Name() string {
+	return "cron"
+}
+
+func (c *cron_component) Dependencies() []string {
+	if c.runtime.GetConfig().Module.Etcd != nil {
+		return []string{"registry"}
+	}
+	return nil
+}
+
+func (c *cron_component) Create() error {
+	if cron, err := c.runtime.newCron(); err != nil {
+		return err
+	} else {
+		c.runtime.cron = cron
+		return nil
+	}
+}
+
+func (c *cron_component) Start(ctx context.Context) error {
+	c.runtime.cron.Start()
+	return nil
+}
+
+func (c *cron_component) Stop(ctx context.Context) error {
+	return c.runtime.cron.Stop(ctx)
+}
+
+type trace_component struct {
+	runtime *Runtime
+}
+
+func (c *trace_component) Name() string {
+	return "trace"
+}
+
+func (c *trace_component) Dependencies() []string {
+	return nil
+}
+
+func (c *trace_component) Create() error {
+	runtime := c.runtime
+	if runtime.GetConfig().Module.Trace == nil {
+		return nil
+	}
+	config := *runtime.GetConfig().Module.Trace
+	if config.Exporter == gtrace.EXPORTER_FILE && len(config.File) <= 0 {
+		config.File = path.Join(proj.Dir.LogDir, runtime.appFullName+".trace")
+	}
+	return gtrace.Config(config, runtime.appFullName)
+}
+
+func (c *trace_component) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *trace_component) Serve(ctx context.Context) error {
+	return gtrace.Serve(ctx)
+}
+
+func (c *trace_component) Stop(ctx context.Context) error {
+	return nil
+}
+
+type registry_client_component struct {
+	runtime *Runtime
+}
+
+func (c *registry_client_component) Name() string {
+	return "registry-client"
+}
+
+func (c *registry_client_component) Dependencies() []string {
+	return nil
+}
+
+func (c *registry_client_component) Create() error {
+	runtime := c.runtime
+	if r, err := registryclient.NewConfigRegistryClient(runtime.ctx, runtime.GetConfig().Module.EtcdClient, runtime.appId, runtime.appFullName); err != nil {
+		return err
+	} else {
+		runtime.registryClient = r
+	}
+	return nil
+}
+
+func (c *registry_client_component) Start(ctx context.Context) error {
+	return c.runtime.registryClient.StartAsClient()
+}
+
+func (c *registry_client_component) Stop(ctx context.Context) error {
+	return c.runtime.registryClient.Stop()
+}
+
+type db_component struct {
+	runtime *Runtime
+}
+
+func (c *db_component) Name() string {
+	return "db"
+}
+
+func (c *db_component) Dependencies() []string {
+	return nil
+}
+
+func (c *db_component) Create() error {
+	runtime := c.runtime
+	runtime.dbClients = make(map[string]gira.DbClient)
+	for name, config := range runtime.GetConfig().Db {
+		if client, err := db.NewConfigDbClient(runtime.ctx, name, *config); err != nil {
+			return err
+		} else {
+			runtime.dbClients[name] = client
+			if name == gira.GAMEDB_NAME {
+				runtime.gameDbClient = client
+			} else if name == gira.RESOURCEDB_NAME {
+				runtime.resourceDbClient = client
+			} else if name == gira.STATDB_NAME {
+				runtime.statDbClient = client
+			} else if name == gira.ACCOUNTDB_NAME {
+				runtime.accountDbClient = client
+			} else if name == gira.LOGDB_NAME {
+				runtime.logDbClient = client
+			} else if name == gira.BEHAVIORDB_NAME {
+				runtime.behaviorDbClient = client
+			} else if name == gira.ACCOUNTCACHE_NAME {
+				runtime.accountCacheClient = client
+			} else if name == gira.ADMINCACHE_NAME {
+				runtime.adminCacheClient = client
+			} else if name == gira.ADMINDB_NAME {
+				runtime.adminDbClient = client
+			} else if name == gira.GAMECACHE_NAME {
+				runtime.gameCacheClient = client
+			}
+		}
+	}
+	runtime.dbShardGroups = db.NewShardGroups(runtime.dbClients)
+	return nil
+}
+
+func (c *db_component) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *db_component) Stop(ctx context.Context) error {
+	return nil
+}
+
+type resource_component struct {
+	runtime *Runtime
+}
+
+func (c *resource_component) Name() string {
+	return "resource"
+}
+
+func (c *resource_component) Dependencies() []string {
+	return []string{"db"}
+}
+
+func (c *resource_component) Create() error {
+	runtime := c.runtime
+	resourceComponent, ok := runtime.application.(gira.ResourceSource)
+	if !ok {
+		return nil
+	}
+	runtime.resourceSource = resourceComponent
+	resourceLoader := resourceComponent.GetResourceLoader()
+	if resourceLoader == nil {
+		return nil
+	}
+	runtime.resourceLoader = resourceLoader
+	if err := runtime.resourceLoader.LoadResource(runtime.ctx, runtime.resourceDbClient, path.Join("resource", "conf"), runtime.GetConfig().Resource.Compress); err != nil {
+		return err
+	}
+	resourceComponent.OnResourcePostLoad(false)
+	return nil
+}
+
+func (c *resource_component) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *resource_component) Stop(ctx context.Context) error {
+	return nil
+}
+
+type platform_component struct {
+	runtime *Runtime
+}
+
+func (c *platform_component) Name() string {
+	return "platform"
+}
+
+func (c *platform_component) Dependencies() []string {
+	return nil
+}
+
+func (c *platform_component) Create() error {
+	c.runtime.platformSdk = platform.NewConfigSdk(*c.runtime.GetConfig().Module.Plat)
+	return nil
+}
+
+func (c *platform_component) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *platform_component) Stop(ctx context.Context) error {
+	return nil
+}
+
+type gate_component struct {
+	runtime *Runtime
+	handler gira.GatewayHandler
+}
+
+func (c *gate_component) Name() string {
+	return "gate"
+}
+
+func (c *gate_component) Dependencies() []string {
+	return nil
+}
+
+func (c *gate_component) Create() error {
+	runtime := c.runtime
+	if h, ok := runtime.application.(gira.GatewayHandler); ok {
+		c.handler = h
+	} else {
+		for _, fw := range runtime.frameworks {
+			if h, ok = fw.(gira.GatewayHandler); ok {
+				c.handler = h
+				break
+			}
+		}
+	}
+	if c.handler == nil {
+		return errors.ErrGateHandlerNotImplement
+	}
+	if gate, err := gate.NewConfigServer(runtime.ctx, *runtime.GetConfig().Module.Gateway); err != nil {
+		return err
+	} else {
+		runtime.gate = gate
+	}
+	return nil
+}
+
+// 只绑定端口, OnStart之后才接收连接
+func (c *gate_component) Start(ctx context.Context) error {
+	return c.runtime.gate.Bind()
+}
+
+func (c *gate_component) Serve(ctx context.Context) error {
+	return c.runtime.gate.Serve(c.handler)
+}
+
+func (c *gate_component) Stop(ctx context.Context) error {
+	c.runtime.gate.Shutdown()
+	return nil
+}
+
+type http_component struct {
+	runtime *Runtime
+}
+
+func (c *http_component) Name() string {
+	return "http"
+}
+
+func (c *http_component) Dependencies() []string {
+	return nil
+}
+
+func (c *http_component) Create() error {
+	runtime := c.runtime
+	handler, ok := runtime.application.(gira.HttpHandler)
+	if !ok {
+		return errors.ErrHttpHandlerNotImplement
+	}
+	router := runtime.probe.Wrap(handler.HttpHandler())
+	if httpServer, err := gins.NewConfigHttpServer(runtime.ctx, *runtime.GetConfig().Module.Http, router); err != nil {
+		return err
+	} else {
+		runtime.httpServer = httpServer
+	}
+	return nil
+}
+
+// 启动后马上开始服务, 启动过程中也可以访问/healthz
+func (c *http_component) Start(ctx context.Context) error {
+	if err := c.runtime.httpServer.Listen(); err != nil {
+		return err
+	}
+	c.runtime.errGroup.Go(func() error {
+		return c.runtime.httpServer.Serve()
+	})
+	return nil
+}
+
+func (c *http_component) Stop(ctx context.Context) error {
+	return c.runtime.httpServer.Stop()
+}
+
+// 没有grpc配置时也会创建客户端的连接管理
+type grpc_component struct {
+	runtime *Runtime
+}
+
+func (c *grpc_component) Name() string {
+	return "grpc"
+}
+
+func (c *grpc_component) Dependencies() []string {
+	return nil
+}
+
+func (c *grpc_component) Create() error {
+	runtime := c.runtime
+	config := runtime.GetConfig().Module.Grpc
+	if config == nil {
+		runtime.grpcConnManager = grpc.NewConfigConnManager(runtime.ctx, gira.GrpcConfig{}, nil)
+		return nil
+	}
+	if config.Tls != nil {
+		if creds, err := grpc.NewConfigCredentials(*config.Tls); err != nil {
+			return err
+		} else {
+			runtime.grpcCredentials = creds
+		}
+	}
+	runtime.grpcConnManager = grpc.NewConfigConnManager(runtime.ctx, *config, runtime.grpcCredentials)
+	policy.ConfigBreaker(config.Breaker.Threshold, time.Duration(config.Breaker.OpenTimeout)*time.Second)
+	if s, err := grpc.NewConfigServer(*config, runtime.grpcCredentials); err != nil {
+		return err
+	} else {
+		runtime.grpcServer = s
+	}
+	return nil
+}
+
+// 只监听端口, OnStart之后才处理请求
+func (c *grpc_component) Start(ctx context.Context) error {
+	if c.runtime.grpcServer == nil {
+		return nil
+	}
+	return c.runtime.grpcServer.Listen()
+}
+
+func (c *grpc_component) Serve(ctx context.Context) error {
+	runtime := c.runtime
+	runtime.errGroup.Go(func() error {
+		return runtime.grpcConnManager.Serve()
+	})
+	if runtime.grpcCredentials != nil {
+		runtime.errGroup.Go(func() error {
+			return runtime.grpcCredentials.Serve(ctx)
+		})
+	}
+	if runtime.grpcServer == nil {
+		return nil
+	}
+	return runtime.grpcServer.Serve(ctx)
+}
+
+func (c *grpc_component) Stop(ctx context.Context) error {
+	if c.runtime.grpcServer != nil {
+		c.runtime.grpcServer.Stop()
+	}
+	c.runtime.grpcConnManager.Stop()
+	return nil
+}
+
+type registry_component struct {
+	runtime *Runtime
+}
+
+func (c *registry_component) Name() string {
+	return "registry"
+}
+
+func (c *registry_component) Dependencies() []string {
+	return []string{"grpc"}
+}
+
+func (c *registry_component) Create() error {
+	runtime := c.runtime
+	if r, err := registry.NewConfigRegistry(runtime.ctx, runtime.GetConfig().Module.Etcd); err != nil {
+		return err
+	} else {
+		runtime.registry = r
+	}
+	// 对方的证书必须是已注册的节点
+	if runtime.grpcCredentials != nil {
+		runtime.grpcCredentials.SetPeerVerifier(func(name string) bool {
+			peer, err := runtime.registry.WhereIsPeer(name)
+			return err == nil && peer != nil
+		})
+		// 使用url拨号时目标就是节点全名, 使用地址拨号时按地址查找节点
+		runtime.grpcCredentials.SetTargetResolver(func(target string) (string, bool) {
+			if peer, err := runtime.registry.WhereIsPeer(target); err == nil && peer != nil {
+				return peer.FullName, true
+			}
+			var fullName string
+			runtime.registry.RangePeers(func(k any, v any) bool {
+				if peer := v.(*gira.Peer); peer.Address == target {
+					fullName = peer.FullName
+					return false
+				}
+				return true
+			})
+			return fullName, len(fullName) > 0
+		})
+	}
+	return nil
+}
+
+func (c *registry_component) Start(ctx context.Context) error {
+	runtime := c.runtime
+	if err := runtime.registry.StartAsMember(); err != nil {
+		return err
+	}
+	if runtime.grpcServer != nil && runtime.GetConfig().Module.Grpc.Resolver {
+		if err := runtime.registry.StartReslover(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *registry_component) Serve(ctx context.Context) error {
+	runtime := c.runtime
+	// 节点下线时关闭连接
+	peerWatchHandlers := []gira.PeerWatchHandler{runtime.grpcConnManager}
+	var localPlayerWatchHandlers []gira.LocalPlayerWatchHandler
+	var serviceWatchHandlers []gira.ServiceWatchHandler
+	var configChangeHandlers []gira.ConfigChangeHandler
+	for _, fw := range runtime.frameworks {
+		if handler, ok := fw.(gira.PeerWatchHandler); ok {
+			peerWatchHandlers = append(peerWatchHandlers, handler)
+		}
+		if handler, ok := fw.(gira.LocalPlayerWatchHandler); ok {
+			localPlayerWatchHandlers = append(localPlayerWatchHandlers, handler)
+		}
+		if handler, ok := fw.(gira.ServiceWatchHandler); ok {
+			serviceWatchHandlers = append(serviceWatchHandlers, handler)
+		}
+		if handler, ok := fw.(gira.ConfigChangeHandler); ok {
+			configChangeHandlers = append(configChangeHandlers, handler)
+		}
+	}
+	if handler, ok := runtime.application.(gira.PeerWatchHandler); ok {
+		peerWatchHandlers = append(peerWatchHandlers, handler)
+	}
+	if handler, ok := runtime.application.(gira.LocalPlayerWatchHandler); ok {
+		localPlayerWatchHandlers = append(localPlayerWatchHandlers, handler)
+	}
+	if handler, ok := runtime.application.(gira.ServiceWatchHandler); ok {
+		serviceWatchHandlers = append(serviceWatchHandlers, handler)
+	}
+	if handler, ok := runtime.application.(gira.ConfigChangeHandler); ok {
+		configChangeHandlers = append(configChangeHandlers, handler)
+	}
+	return runtime.registry.Watch(peerWatchHandlers, localPlayerWatchHandlers, serviceWatchHandlers, configChangeHandlers)
+}
+
+func (c *registry_component) Stop(ctx context.Context) error {
+	return c.runtime.registry.Stop()
+}
@@ -0,0 +1,39 @@
+package app
+
+///
+/// 单例的定时任务通过注册表的分布式锁互斥, 锁名为 cron/<服务类型>/<任务名>
+/// 锁绑定在租约上, 节点崩溃后租约过期, 其他节点在下次执行时抢到锁
+///
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Lyndon-Zhang/gira/cron"
+)
+
+type cron_locker struct {
+	runtime *Runtime
+}
+
+func (l *cron_locker) TryLock(name string) (bool, error) {
+	runtime := l.runtime
+	// 没有注册表时每个节点都执行
+	if runtime.registry == nil {
+		return true, nil
+	}
+	return runtime.registry.TryLock(fmt.Sprintf("cron/%s/%s", runtime.appType, name))
+}
+
+func (runtime *Runtime) newCron() (*cron.Cron, error) {
+	c := cron.New(nil)
+	if err := c.SetSpecs(runtime.GetConfig().Cron); err != nil {
+		return nil, err
+	}
+	store, err := cron.NewFileStore(filepath.Join(runtime.runDir, fmt.Sprintf("cron_%s.json", runtime.appFullName)))
+	if err != nil {
+		return nil, err
+	}
+	c.SetStore(store)
+	c.SetLocker(&cron_locker{runtime: runtime})
+	return c, nil
+}
@@ -0,0 +1,94 @@
+package app
+
+///
+/// 服务进程管理
+///
+/// start -d 在后台启动, 标准输出重定向到log目录, 启动完成后写pid文件
+/// stop 发送SIGTERM, 等待注册表中的节点注销并且进程退出
+///
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/facade"
+	"github.com/Lyndon-Zhang/gira/log"
+	"github.com/Lyndon-Zhang/gira/proj"
+	"github.com/Lyndon-Zhang/gira/util/process"
+)
+
+const (
+	daemon_check_interval = 500 * time.Millisecond
+)
+
+// 在后台启动, 等待新进程写入pid文件后返回
+func daemonize(appType string, appId int32, timeout time.Duration) error {
+	if pid, err := proj.ReadPidFile(appType, appId); err == nil && proj.IsProcessAlive(pid) {
+		log.Printf("%s %d already running, pid %d", appType, appId, pid)
+		return errors.ErrProcessAlreadyRunning
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(proj.Dir.LogDir, 0755); err != nil {
+		return err
+	}
+	outFilePath := filepath.Join(proj.Dir.LogDir, fmt.Sprintf("%s_%d.out", appType, appId))
+	out, err := os.OpenFile(outFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	cmd := exec.Command(executable, process.DaemonArgs(os.Args[1:])...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// 脱离终端, 终端关闭时不会收到SIGHUP
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	ticker := time.NewTicker(daemon_check_interval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case err := <-exited:
+			log.Printf("%s %d exited, see %s", appType, appId, outFilePath)
+			if err != nil {
+				return err
+			}
+			return errors.ErrProcessNotRunning
+		case <-deadline:
+			log.Printf("%s %d still starting, pid %d, see %s", appType, appId, pid, outFilePath)
+			return nil
+		case <-ticker.C:
+			if v, err := proj.ReadPidFile(appType, appId); err == nil && v == pid {
+				log.Printf("%s %d started, pid %d", appType, appId, pid)
+				return nil
+			}
+		}
+	}
+}
+
+// 发送SIGTERM, 等待节点注销并且进程退出
+func stopProcess(appFullName string, pid int, timeout time.Duration) error {
+	unregistered := func() bool {
+		_, err := facade.WhereIsPeer(appFullName)
+		return errors.Is(err, errors.ErrPeerNotFound)
+	}
+	err := process.Terminate(pid, timeout, daemon_check_interval, unregistered)
+	if err == errors.ErrProcessStopTimeout {
+		log.Printf("%s stop timeout, unregistered %v, alive %v", appFullName, unregistered(), process.IsAlive(pid))
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+)
+
+const (
+	probe_check_timeout = 3 * time.Second // /readyz每个检查的超时时间
+)
+
+// 按名字排序增加, /readyz输出的顺序固定
+func (runtime *Runtime) addReadinessChecks(checker gira.ReadinessChecker) {
+	checks := checker.ReadinessChecks()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		runtime.probe.AddCheck(name, checks[name])
+	}
+}
+
+func (runtime *Runtime) IsReady() bool {
+	return runtime.probe.IsReady()
+}
+
+func (runtime *Runtime) AddReadinessCheck(name string, check gira.ReadinessCheckFunc) {
+	runtime.probe.AddCheck(name, check)
+}
@@ -0,0 +1,125 @@
+package app
+
+///
+/// 重新加载配置文件
+///
+/// 重新读取配置文件(包括$include和$template), 和当前的配置比较
+///   - 只修改了可以直接生效的配置时, 回调OnConfigReload, 然后生效
+///   - 有需要重启才能生效的修改时, 全部拒绝, 返回需要重启的路径
+///   - 应用自定义的节都可以直接生效, 由OnConfigReload处理
+///   - cron节按任务名覆盖定时任务的表达式
+///
+import (
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/behaviorlog"
+	"github.com/Lyndon-Zhang/gira/config"
+	"github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/cron"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/log"
+	"github.com/Lyndon-Zhang/gira/proj"
+)
+
+// 重新加载配置文件
+func (runtime *Runtime) ReloadConfig() (*gira.ConfigReloadReport, error) {
+	runtime.reloadMu.Lock()
+	defer runtime.reloadMu.Unlock()
+	old := runtime.GetConfig()
+	c, err := proj.ReadApplicationConfig(runtime.appType, runtime.appId)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := config.Diff(old.Raw, c.Raw)
+	if err != nil {
+		return nil, err
+	}
+	report := &gira.ConfigReloadReport{}
+	for _, path := range paths {
+		if config.IsHotReloadPath(path) {
+			report.Changed = append(report.Changed, path)
+		} else {
+			report.Rejected = append(report.Rejected, path)
+		}
+	}
+	if len(report.Rejected) > 0 {
+		corelog.Warnw("config reload rejected", "changed", report.Changed, "rejected", report.Rejected)
+		report.Changed = nil
+		return report, errors.ErrConfigRestartRequired
+	}
+	if len(report.Changed) <= 0 {
+		return report, nil
+	}
+	// 先检查新的配置能不能生效, 不能生效时不回调, 也不修改任何模块
+	if err := runtime.checkConfig(c); err != nil {
+		corelog.Warnw("config reload rejected", "changed", report.Changed, "error", err)
+		return nil, err
+	}
+	// 回调, 返回错误时放弃
+	for _, fw := range runtime.frameworks {
+		if handler, ok := fw.(gira.ConfigReloadHandler); ok {
+			if err := handler.OnConfigReload(old, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if handler, ok := runtime.application.(gira.ConfigReloadHandler); ok {
+		if err := handler.OnConfigReload(old, c); err != nil {
+			return nil, err
+		}
+	}
+	if err := runtime.applyConfig(c); err != nil {
+		return nil, err
+	}
+	runtime.config.Store(c)
+	proj.PublishConfig(c)
+	corelog.Infow("config reload", "changed", report.Changed)
+	return report, nil
+}
+
+// 检查可以直接生效的配置
+func (runtime *Runtime) checkConfig(c *gira.Config) error {
+	if c.CoreLog != nil {
+		if err := corelog.CheckLevels(*c.CoreLog); err != nil {
+			return err
+		}
+	}
+	if c.BehaviorLog != nil {
+		if err := behaviorlog.CheckLevels(*c.BehaviorLog); err != nil {
+			return err
+		}
+	}
+	if c.Log != nil {
+		if err := log.CheckLevels(*c.Log); err != nil {
+			return err
+		}
+	}
+	return cron.CheckSpecs(c.Cron)
+}
+
+// 修改已经创建的模块, 结构相同的部分才会调用到这里
+func (runtime *Runtime) applyConfig(c *gira.Config) error {
+	if c.CoreLog != nil {
+		if err := corelog.SetLevels(*c.CoreLog); err != nil {
+			return err
+		}
+	}
+	if c.BehaviorLog != nil {
+		if err := behaviorlog.SetLevels(*c.BehaviorLog); err != nil {
+			return err
+		}
+	}
+	if c.Log != nil {
+		if err := log.SetLevels(*c.Log); err != nil {
+			return err
+		}
+	}
+	if runtime.gate != nil && c.Module.Gateway != nil {
+		runtime.gate.Reload(*c.Module.Gateway)
+	}
+	if runtime.cron != nil {
+		if err := runtime.cron.SetSpecs(c.Cron); err != nil {
+			return err
+		}
+	}
+	return nil
+}
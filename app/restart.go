@@ -0,0 +1,60 @@
+package app
+
+///
+/// 零停机重启
+///
+/// 1. 启动新的进程, 交接gate, grpc, http的listener, 端口一直处于绑定状态
+/// 2. 新进程启动完成(OnStart, 注册表, 资源加载)后通知旧进程
+/// 3. 旧进程的节点和服务注册信息由新进程接管, 旧进程不再接收新的连接
+/// 4. 旧进程等待会话结束, 超时后踢掉剩下的会话, 然后退出
+///
+/// 新进程启动失败时旧进程继续运行
+///
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/graceful"
+	"github.com/Lyndon-Zhang/gira/proj"
+)
+
+const (
+	restart_ready_timeout = 120 * time.Second // 等待新进程就绪
+	restart_drain_timeout = 30 * time.Second  // 等待会话结束
+)
+
+// 启动新的进程, 新进程就绪后返回新进程的pid, 之后当前进程等待会话结束后退出
+func (runtime *Runtime) Restart() (int, error) {
+	if !atomic.CompareAndSwapInt32(&runtime.restarting, 0, 1) {
+		return 0, errors.ErrGracefulRestarting
+	}
+	pid, err := graceful.Restart(restart_ready_timeout)
+	if err != nil {
+		atomic.StoreInt32(&runtime.restarting, 0)
+		return 0, err
+	}
+	runtime.probe.SetReady(false)
+	if runtime.registry != nil {
+		runtime.registry.Handoff()
+	}
+	// 由pid文件管理时, 换成新进程的pid
+	if v, err := proj.ReadPidFile(runtime.appType, runtime.appId); err == nil && v == os.Getpid() {
+		if err := proj.WritePidFile(runtime.appType, runtime.appId, pid); err != nil {
+			corelog.Warnw("write pid file fail", "error", err)
+		}
+	}
+	go func() {
+		if runtime.gate != nil {
+			ctx, cancelFunc := context.WithTimeout(runtime.ctx, restart_drain_timeout)
+			runtime.gate.Drain(ctx)
+			cancelFunc()
+		}
+		corelog.Infow("graceful restart, old process exit", "new_pid", pid)
+		runtime.Stop()
+	}()
+	return pid, nil
+}
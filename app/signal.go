@@ -0,0 +1,84 @@
+package app
+
+///
+/// SIGUSR1, SIGUSR2对应的动作, 在配置signal中修改
+///
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/facade"
+	"github.com/Lyndon-Zhang/gira/log"
+)
+
+// 执行信号对应的动作, 然后回调OnSignal
+func (runtime *Runtime) onSignal(sig os.Signal) {
+	config := runtime.GetConfig().Signal
+	actions := config.Actions(sig)
+	for i, err := range config.Do(sig, runtime.doSignalAction) {
+		if err != nil {
+			corelog.Warnw("signal action fail", "signal", sig, "action", actions[i], "error", err)
+		} else {
+			corelog.Infow("signal action", "signal", sig, "action", actions[i])
+		}
+	}
+	for _, fw := range runtime.frameworks {
+		if handler, ok := fw.(gira.SignalHandler); ok {
+			handler.OnSignal(sig)
+		}
+	}
+	if handler, ok := runtime.application.(gira.SignalHandler); ok {
+		handler.OnSignal(sig)
+	}
+}
+
+func (runtime *Runtime) doSignalAction(action string) error {
+	switch action {
+	case gira.SIGNAL_ACTION_RELOAD_RESOURCE:
+		return facade.ReloadResource()
+	case gira.SIGNAL_ACTION_RELOAD_CONFIG:
+		_, err := runtime.ReloadConfig()
+		return err
+	case gira.SIGNAL_ACTION_REOPEN_LOG:
+		if err := corelog.Rotate(); err != nil {
+			return err
+		}
+		return log.Rotate()
+	case gira.SIGNAL_ACTION_TOGGLE_DEBUG:
+		corelog.ToggleDebug()
+		debug := log.ToggleDebug()
+		corelog.Infow("toggle debug", "debug", debug)
+		return nil
+	case gira.SIGNAL_ACTION_DUMP_GOROUTINE:
+		return runtime.dumpProfile("goroutine", 2, "txt")
+	case gira.SIGNAL_ACTION_DUMP_HEAP:
+		return runtime.dumpProfile("heap", 0, "pprof")
+	default:
+		return errors.ErrInvalidSignalAction
+	}
+}
+
+// 保存到日志目录, 例如 log/hall_1.goroutine.20230101150405.txt
+func (runtime *Runtime) dumpProfile(name string, debug int, ext string) error {
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return fmt.Errorf("profile %s not found", name)
+	}
+	filePath := filepath.Join(runtime.GetLogDir(), fmt.Sprintf("%s.%s.%s.%s", runtime.appFullName, name, time.Now().Format("20060102150405"), ext))
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := profile.WriteTo(f, debug); err != nil {
+		return err
+	}
+	corelog.Infow("dump profile", "name", name, "file", filePath)
+	return nil
+}
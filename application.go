@@ -3,8 +3,10 @@ package gira
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Lyndon-Zhang/gira/errors"
 )
@@ -31,6 +33,10 @@ OnConfigLoad
 
 	|
 
+RuntimeComponent.Create
+
+	|
+
 OnFrameworkCreate
 
 	|
@@ -39,6 +45,10 @@ OnCreate
 
 	|
 
+RuntimeComponent.Start
+
+	|
+
 OnFrameworkStart
 
 	|
@@ -52,6 +62,10 @@ OnStop
 	|
 
 OnFrameworkStop
+
+	|
+
+RuntimeComponent.Stop
 */
 
 type Framework interface {
@@ -70,6 +84,60 @@ type Application interface {
 	OnStop() error
 }
 
+// 收到SIGUSR1, SIGUSR2时回调, 在配置的动作执行之后
+type SignalHandler interface {
+	OnSignal(sig os.Signal)
+}
+
+// 重新加载配置时回调, 在新配置生效前调用, 返回错误时放弃这次加载
+type ConfigReloadHandler interface {
+	OnConfigReload(old *Config, new *Config) error
+}
+
+// 重新加载配置的结果, 路径为yaml路径, 例如 module.gateway.heartbeat
+type ConfigReloadReport struct {
+	Changed  []string // 已经生效的修改
+	Rejected []string // 需要重启才能生效的修改
+}
+
+// 就绪检查, 返回错误时/readyz返回503
+type ReadinessCheckFunc func(ctx context.Context) error
+
+// 框架和应用增加/readyz的就绪检查, 例如数据库ping, 大厅状态, OnStart之后调用
+type ReadinessChecker interface {
+	ReadinessChecks() map[string]ReadinessCheckFunc
+}
+
+// 运行时组件, 由runtime按依赖的顺序创建和启动, 按相反的顺序停止
+//
+// Create在OnCreate之前调用, Start在OnStart之前调用, Stop在OnStop之后调用
+type RuntimeComponent interface {
+	// 组件名, 其他组件用这个名字声明依赖
+	Name() string
+	// 依赖的组件, 在依赖的组件之后创建和启动, 在依赖的组件之前停止
+	Dependencies() []string
+	Create() error
+	Start(ctx context.Context) error
+	// ctx在超时后取消, 超时后runtime不再等待, 继续停止下一个组件
+	Stop(ctx context.Context) error
+}
+
+// 可选, 在OnStart之后开始服务, 返回错误时runtime退出
+type RuntimeComponentServer interface {
+	Serve(ctx context.Context) error
+}
+
+// 可选, 停止的超时时间
+type RuntimeComponentStopTimeout interface {
+	StopTimeout() time.Duration
+}
+
+// 框架和应用注册自己的运行时组件, 在OnConfigLoad之后调用
+// 内置的组件有cron, trace, registry-client, db, resource, platform, gate, http, grpc, registry
+type RuntimeComponentProvider interface {
+	RuntimeComponents() []RuntimeComponent
+}
+
 type ApplicationFramework interface {
 	OnFrameworkInit() []Framework
 }
@@ -96,10 +164,20 @@ type Runtime interface {
 	GetAppVersion() string
 	GetBuildTime() int64
 	GetUpTime() int64
+	// 重新加载配置文件
+	ReloadConfig() (*ConfigReloadReport, error)
 
 	// ======= 同步接口 ===========
 	Wait() error
 	Stop() error
+	// 零停机重启, 新进程就绪后返回新进程的pid
+	Restart() (int, error)
+	// 是否已经就绪, 和/readyz的结果一致
+	IsReady() bool
+	// 增加/readyz的就绪检查, 同名的检查会被替换
+	AddReadinessCheck(name string, check ReadinessCheckFunc)
+	// 按名字查找运行时组件, 找不到时返回nil
+	GetComponent(name string) RuntimeComponent
 	Context() context.Context
 	Go(f func() error)
 	Done() <-chan struct{}
@@ -112,6 +190,7 @@ type Runtime interface {
 	GetPlatformSdk() PlatformSdk
 	GetCron() Cron
 	GetGrpcServer() GrpcServer
+	GetGrpcConnManager() GrpcConnManager
 	GetRegistry() Registry
 	GetRegistryClient() RegistryClient
 }
@@ -135,6 +214,17 @@ func FormatAppFullName(appType string, appId int32, zone string, env string) str
 	return fmt.Sprintf("%s_%s_%s_%d", appType, zone, env, appId)
 }
 
+// 解析节点所在的区
+func ParseAppZone(fullName string) (zone string, err error) {
+	pats := strings.Split(string(fullName), "_")
+	if len(pats) != 4 {
+		err = errors.New("invalid app full name", "full_name", fullName)
+		return
+	}
+	zone = pats[1]
+	return
+}
+
 func ParseAppFullName(fullName string) (name string, id int32, err error) {
 	pats := strings.Split(string(fullName), "_")
 	if len(pats) != 4 {
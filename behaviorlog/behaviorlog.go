@@ -19,6 +19,21 @@ func Config(config gira.LogConfig) error {
 	}
 }
 
+// 修改日志级别, 不重新创建输出
+func CheckLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.CheckLevels(config)
+	}
+	return nil
+}
+
+func SetLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.SetLevels(config)
+	}
+	return nil
+}
+
 func Info(args ...interface{}) {
 	if defaultLogger == nil {
 		return
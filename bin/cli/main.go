@@ -2,17 +2,28 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	_ "net/http/pprof"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/app"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/facade"
+	"github.com/Lyndon-Zhang/gira/grpc/scatter"
 	"github.com/Lyndon-Zhang/gira/log"
+	"github.com/Lyndon-Zhang/gira/registryclient"
+	"github.com/Lyndon-Zhang/gira/service/admin/adminpb"
+	"github.com/Lyndon-Zhang/gira/service/peer/peerpb"
 	"github.com/urfave/cli/v2"
 
 	"github.com/Lyndon-Zhang/gira/gen/gen_application"
@@ -140,6 +151,102 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:   "registry",
+				Usage:  "registry [dump|diff|restore|gc]",
+				Before: beforeAction1,
+				Subcommands: []*cli.Command{
+					{
+						Name:      "dump",
+						Usage:     "dump peer, service and user keys to file",
+						ArgsUsage: "<file>",
+						Action:    registryDumpAction,
+					},
+					{
+						Name:      "diff",
+						Usage:     "compare two snapshot files",
+						ArgsUsage: "<old file> <new file>",
+						Action:    registryDiffAction,
+					},
+					{
+						Name:      "restore",
+						Usage:     "restore keys from snapshot file",
+						ArgsUsage: "<file>",
+						Action:    registryRestoreAction,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:     "prefix",
+								Usage:    "only restore keys with prefix",
+								Required: false,
+							},
+							&cli.BoolFlag{
+								Name:     "dry-run",
+								Value:    false,
+								Usage:    "print keys without writing",
+								Required: false,
+							},
+						},
+					},
+					{
+						Name:   "gc",
+						Usage:  "delete keys whose owner peer is gone",
+						Action: registryGcAction,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:     "dry-run",
+								Value:    false,
+								Usage:    "print keys without deleting",
+								Required: false,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:   "config",
+				Usage:  "config [set|get|delete|list|rollback]",
+				Before: beforeAction1,
+				Subcommands: []*cli.Command{
+					{
+						Name:      "set",
+						Usage:     "set config value",
+						ArgsUsage: "<key> <value>",
+						Action:    configSetAction,
+					},
+					{
+						Name:      "get",
+						Usage:     "get config value",
+						ArgsUsage: "<key>",
+						Action:    configGetAction,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:     "history",
+								Value:    false,
+								Usage:    "print change history",
+								Required: false,
+							},
+						},
+					},
+					{
+						Name:      "delete",
+						Usage:     "delete config value",
+						ArgsUsage: "<key>",
+						Action:    configDeleteAction,
+					},
+					{
+						Name:      "list",
+						Usage:     "list config values",
+						ArgsUsage: "[prefix]",
+						Action:    configListAction,
+					},
+					{
+						Name:      "rollback",
+						Usage:     "rollback config value",
+						ArgsUsage: "<key> [steps]",
+						Action:    configRollbackAction,
+					},
+				},
+			},
 			{
 				Name:   "macro",
 				Usage:  "macro code",
@@ -157,15 +264,35 @@ func main() {
 				Usage:  "Display status",
 				Action: statusAction,
 			},
+			{
+				Name:   "ps",
+				Usage:  "List all app instances with uptime, version and health",
+				Action: psAction,
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "timeout of each peer in seconds",
+						Value: 5,
+					},
+				},
+			},
 			{
 				Name:   "stop",
 				Usage:  "Stop minigame",
 				Action: stopAction,
 			},
 			{
-				Name:   "restart",
-				Usage:  "Restart minigame",
-				Action: restartAction,
+				Name:      "restart",
+				Usage:     "Restart service without downtime",
+				ArgsUsage: "<name> <id>",
+				Action:    restartAction,
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "wait for the new process to be ready in seconds",
+						Value: 120,
+					},
+				},
 			},
 			{
 				Name:   "reload",
@@ -268,6 +395,236 @@ func migrateAction(c *cli.Context) error {
 	}
 }
 
+// 连接注册表
+func newRegistryClient() (*registryclient.RegistryClient, error) {
+	config, err := proj.LoadCliConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Module.EtcdClient == nil {
+		return nil, errors.New("etcd-client config not found")
+	}
+	return registryclient.NewConfigRegistryClient(context.Background(), config.Module.EtcdClient, 0, "cli")
+}
+
+func registryDumpAction(c *cli.Context) error {
+	if c.Args().Len() < 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	filePath := c.Args().Get(0)
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	snapshot, err := r.DumpSnapshot()
+	if err != nil {
+		return err
+	}
+	if err := snapshot.WriteFile(filePath); err != nil {
+		return err
+	}
+	log.Printf("dump %d keys at revision %d to %s", len(snapshot.Kvs), snapshot.Revision, filePath)
+	return nil
+}
+
+func registryDiffAction(c *cli.Context) error {
+	if c.Args().Len() < 2 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	old, err := registryclient.ReadSnapshotFile(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	new, err := registryclient.ReadSnapshotFile(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	diffs := registryclient.DiffSnapshot(old, new)
+	for _, diff := range diffs {
+		switch diff.Type {
+		case registryclient.SNAPSHOT_DIFF_ADDED:
+			log.Printf("+ %s => %s", diff.Key, diff.New.Value)
+		case registryclient.SNAPSHOT_DIFF_REMOVED:
+			log.Printf("- %s => %s", diff.Key, diff.Old.Value)
+		case registryclient.SNAPSHOT_DIFF_CHANGED:
+			log.Printf("~ %s => %s -> %s", diff.Key, diff.Old.Value, diff.New.Value)
+		}
+	}
+	log.Printf("revision %d(%s) -> %d(%s), %d diffs", old.Revision, time.Unix(old.Time, 0).Format(time.RFC3339),
+		new.Revision, time.Unix(new.Time, 0).Format(time.RFC3339), len(diffs))
+	return nil
+}
+
+func registryRestoreAction(c *cli.Context) error {
+	if c.Args().Len() < 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	snapshot, err := registryclient.ReadSnapshotFile(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	dryRun := c.Bool("dry-run")
+	restored, err := r.RestoreSnapshot(snapshot, c.StringSlice("prefix"), dryRun)
+	for _, v := range restored {
+		log.Printf("restore %s => %s", v.Key, v.Value)
+	}
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		log.Printf("%d keys would be restored (dry run)", len(restored))
+	} else {
+		log.Printf("%d keys restored", len(restored))
+	}
+	return nil
+}
+
+func registryGcAction(c *cli.Context) error {
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	dryRun := c.Bool("dry-run")
+	deleted, err := r.GcSnapshot(dryRun)
+	for _, v := range deleted {
+		log.Printf("gc %s => %s", v.Key, v.Value)
+	}
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		log.Printf("%d keys would be deleted (dry run)", len(deleted))
+	} else {
+		log.Printf("%d keys deleted", len(deleted))
+	}
+	return nil
+}
+
+func configSetAction(c *cli.Context) error {
+	if c.Args().Len() < 2 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	key := c.Args().Get(0)
+	value := c.Args().Get(1)
+	if err := r.SetConfig(key, value); err != nil {
+		return err
+	}
+	log.Printf("set %s => %s", key, value)
+	return nil
+}
+
+func configGetAction(c *cli.Context) error {
+	if c.Args().Len() < 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	key := c.Args().Get(0)
+	if value, ok, err := r.GetConfig(key); err != nil {
+		return err
+	} else if !ok {
+		log.Printf("%s not found", key)
+	} else {
+		log.Printf("%s => %s", key, value)
+	}
+	if c.Bool("history") {
+		histories, err := r.ConfigHistory(key)
+		if err != nil {
+			return err
+		}
+		for i, v := range histories {
+			if v.Deleted {
+				log.Printf("[%d] %s deleted", i, time.Unix(v.Time, 0).Format(time.RFC3339))
+			} else {
+				log.Printf("[%d] %s %s", i, time.Unix(v.Time, 0).Format(time.RFC3339), v.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func configDeleteAction(c *cli.Context) error {
+	if c.Args().Len() < 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	key := c.Args().Get(0)
+	if err := r.DeleteConfig(key); err != nil {
+		return err
+	}
+	log.Printf("delete %s", key)
+	return nil
+}
+
+func configListAction(c *cli.Context) error {
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	values, err := r.ListConfig(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		log.Printf("%s => %s", k, values[k])
+	}
+	return nil
+}
+
+func configRollbackAction(c *cli.Context) error {
+	if c.Args().Len() < 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+	steps := 1
+	if c.Args().Len() >= 2 {
+		if v, err := strconv.Atoi(c.Args().Get(1)); err != nil {
+			return err
+		} else {
+			steps = v
+		}
+	}
+	r, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	key := c.Args().Get(0)
+	history, err := r.RollbackConfig(key, steps)
+	if err != nil {
+		return err
+	}
+	if history.Deleted {
+		log.Printf("rollback %s to deleted", key)
+	} else {
+		log.Printf("rollback %s => %s", key, history.Value)
+	}
+	return nil
+}
+
 func resourceCompressAction(args *cli.Context) error {
 	bin := "bin/resource"
 	argv := []string{"compress"}
@@ -450,7 +807,11 @@ func resourceAction(args *cli.Context) error {
 
 func macroAction(args *cli.Context) error {
 	log.Println("macroAction")
-	if err := gen_macro.Gen(&gen_macro.Config{}); err != nil {
+	if err := gen_macro.Gen(&gen_macro.Config{
+		SrcDir:     proj.Dir.SrcDir,
+		SrcGenDir:  proj.Dir.SrcGenDir,
+		SrcTestDir: proj.Dir.SrcTestDir,
+	}); err != nil {
 		return err
 	}
 	return nil
@@ -464,17 +825,71 @@ func stopAction(args *cli.Context) error {
 	return nil
 }
 
+// 零停机重启, 由正在运行的进程启动新的进程并交接端口
 func restartAction(args *cli.Context) error {
+	if args.Args().Len() < 2 {
+		cli.ShowSubcommandHelp(args)
+		return nil
+	}
+	name := args.Args().Get(0)
+	id, err := strconv.Atoi(args.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	if err := app.StartAsClient(&app.ClientApplication{}, 0, "cli"); err != nil {
+		return err
+	}
+	appFullName := gira.FormatAppFullName(name, int32(id), facade.GetZone(), facade.GetEnv())
+	resp, err := adminpb.DefaultAdminClients.Unicast().
+		WherePeerFullName(appFullName).
+		WithTimeout(time.Duration(args.Int("timeout"))*time.Second).
+		Restart(facade.Context(), &adminpb.RestartRequest{})
+	if err != nil {
+		return err
+	}
+	log.Printf("%s restarted, pid %d", appFullName, resp.Pid)
 	return nil
 }
 
 func statusAction(args *cli.Context) error {
-	/*projectDir, err := getProjectDir()
-	if err != nil {
+	return psAction(args)
+}
+
+// 列出项目的全部节点, pid只有在本机的pid文件中才能找到
+func psAction(args *cli.Context) error {
+	timeout := time.Duration(args.Int("timeout")) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if err := app.StartAsClient(&app.ClientApplication{}, 0, "cli"); err != nil {
 		return err
 	}
-	configPath := fmt.Sprintf("%s/supervisord/supervisord.conf", projectDir)
-	execCommand("supervisorctl", []string{"-c", configPath, "status"})*/
+	stream := peerpb.DefaultPeerClients.Broadcast().
+		WithTimeout(timeout).
+		HealthCheckGatherChan(facade.Context(), &peerpb.HealthCheckRequest{})
+	results := make([]*scatter.Result[*peerpb.HealthCheckResponse], 0)
+	for result := range stream.C {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Peer.FullName < results[j].Peer.FullName
+	})
+	log.Printf("%-30s %-22s %-8s %-12s %-16s %s", "NAME", "ADDRESS", "PID", "UPTIME", "VERSION", "HEALTH")
+	for _, result := range results {
+		pid := "-"
+		if v, err := proj.ReadPidFile(result.Peer.Name, result.Peer.Id); err == nil && proj.IsProcessAlive(v) {
+			pid = strconv.Itoa(v)
+		}
+		if result.Err != nil {
+			log.Printf("%-30s %-22s %-8s %-12s %-16s dead %v", result.Peer.FullName, result.Peer.Address, pid, "-", "-", result.Err)
+		} else {
+			upTime := time.Duration(result.Response.UpTime) * time.Second
+			log.Printf("%-30s %-22s %-8s %-12s %-16s alive", result.Peer.FullName, result.Peer.Address, pid, upTime, result.Response.AppVersion)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		log.Println(err)
+	}
 	return nil
 }
 
@@ -36,12 +36,13 @@ const (
 	grpcPackage    = protogen.GoImportPath("google.golang.org/grpc")
 	codesPackage   = protogen.GoImportPath("google.golang.org/grpc/codes")
 	statusPackage  = protogen.GoImportPath("google.golang.org/grpc/status")
-	syncPackage    = protogen.GoImportPath("sync")
 	giraPackage    = protogen.GoImportPath("github.com/Lyndon-Zhang/gira")
 	errorsPackage  = protogen.GoImportPath("github.com/Lyndon-Zhang/gira/errors")
 	facadePackage  = protogen.GoImportPath("github.com/Lyndon-Zhang/gira/facade")
 	optionsPackage = protogen.GoImportPath("github.com/Lyndon-Zhang/gira/options/service_options")
 	metaPackage    = protogen.GoImportPath("google.golang.org/grpc/metadata")
+	policyPackage  = protogen.GoImportPath("github.com/Lyndon-Zhang/gira/grpc/policy")
+	scatterPackage = protogen.GoImportPath("github.com/Lyndon-Zhang/gira/grpc/scatter")
 	timePackage    = protogen.GoImportPath("time")
 )
 
@@ -63,6 +64,8 @@ type serviceGenerateHelperInterface interface {
 	generateClientsUnicastStruct(g *protogen.GeneratedFile, clientsUnicastName string, clientsName string)
 	generateClientsMulticastStruct(g *protogen.GeneratedFile, clientsMulticastName string, clientsName string)
 	generateNewClientsDefinitions(g *protogen.GeneratedFile, service *protogen.Service, clientName string)
+	generatePolicyInterface(g *protogen.GeneratedFile, builderName string)
+	generatePolicyMethods(g *protogen.GeneratedFile, structName string, builderName string)
 	generateUnimplementedServerType(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service)
 	generateServerFunctions(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service, serverType string, serviceDescVar string)
 	formatHandlerFuncName(service *protogen.Service, hname string) string
@@ -95,8 +98,6 @@ func (serviceGenerateHelper) genFullMethods(g *protogen.GeneratedFile, service *
 func (serviceGenerateHelper) generateClientsStruct(g *protogen.GeneratedFile, clientsName string, clientName string) {
 	g.P("type ", unexport(clientsName), " struct {")
 	// g.P("cc ", grpcPackage.Ident("ClientConnInterface"))
-	g.P("mu  ", syncPackage.Ident("Mutex"))
-	g.P("clientPool  map[string]*sync.Pool")
 	g.P("serviceName string")
 	g.P("}")
 	g.P()
@@ -105,12 +106,19 @@ func (serviceGenerateHelper) generateClientsStruct(g *protogen.GeneratedFile, cl
 func (serviceGenerateHelper) generateClientsUnicastStruct(g *protogen.GeneratedFile, clientsUnicastName string, clientsName string) {
 	g.P("type ", unexport(clientsUnicastName), " struct {")
 	// g.P("cc ", grpcPackage.Ident("ClientConnInterface"))
-	g.P("timeout 		int64")
+	g.P("timeout 		", timePackage.Ident("Duration"))
+	g.P("retry 			int")
+	g.P("backoff 		", timePackage.Ident("Duration"))
+	g.P("hedge 			", timePackage.Ident("Duration"))
+	g.P("breaker 		bool")
 	g.P("peer 			*gira.Peer")
 	g.P("peerFullName   string")
 	g.P("serviceName 	string")
 	g.P("address 		string")
 	g.P("userId 		string")
+	g.P("key 			string")
+	g.P("zone 			string")
+	g.P("allZone 		bool")
 	g.P("local			bool")
 	g.P("client 		*" + unexport(clientsName))
 	g.P("headers		", metaPackage.Ident("MD"))
@@ -121,11 +129,19 @@ func (serviceGenerateHelper) generateClientsUnicastStruct(g *protogen.GeneratedF
 func (serviceGenerateHelper) generateClientsMulticastStruct(g *protogen.GeneratedFile, clientsMulticastName string, clientsName string) {
 	g.P("type ", unexport(clientsMulticastName), " struct {")
 	// g.P("cc ", grpcPackage.Ident("ClientConnInterface"))
-	g.P("timeout 		int64")
+	g.P("timeout 		", timePackage.Ident("Duration"))
+	g.P("retry 			int")
+	g.P("backoff 		", timePackage.Ident("Duration"))
+	g.P("hedge 			", timePackage.Ident("Duration"))
+	g.P("breaker 		bool")
+	g.P("concurrency 	int")
+	g.P("quorum 		int")
 	g.P("count 			int")
 	g.P("serviceName 	string")
 	g.P("regex 			string")
 	g.P("prefix			bool")
+	g.P("zone 			string")
+	g.P("allZone 		bool")
 	g.P("local			bool")
 	g.P("client 		*" + unexport(clientsName))
 	g.P("headers		", metaPackage.Ident("MD"))
@@ -136,10 +152,56 @@ func (serviceGenerateHelper) generateClientsMulticastStruct(g *protogen.Generate
 func (serviceGenerateHelper) generateNewClientsDefinitions(g *protogen.GeneratedFile, service *protogen.Service, clientName string) {
 	g.P("return &", unexport(clientName), "{")
 	g.P("	serviceName: ", service.GoName, "ServerName,")
-	g.P("	clientPool:  make(map[string]*sync.Pool, 0),")
 	g.P("}")
 }
 
+func (serviceGenerateHelper) generatePolicyInterface(g *protogen.GeneratedFile, builderName string) {
+	g.P("    WithTimeout(timeout ", timePackage.Ident("Duration"), ") "+builderName)
+	g.P("    WithRetry(retry int, backoff ", timePackage.Ident("Duration"), ") "+builderName)
+	g.P("    WithHedge(delay ", timePackage.Ident("Duration"), ") "+builderName)
+	g.P("    WithBreaker(enabled bool) " + builderName)
+}
+
+// 调用策略, 重试和对冲只对幂等的方法生效
+func (serviceGenerateHelper) generatePolicyMethods(g *protogen.GeneratedFile, structName string, builderName string) {
+	g.P("// 调用超时, 包括重试的时间")
+	g.P("func (c *", structName, ") WithTimeout(timeout ", timePackage.Ident("Duration"), ") ", builderName, " {")
+	g.P("	c.timeout = timeout")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("// 失败后重试, 只对幂等的方法生效")
+	g.P("func (c *", structName, ") WithRetry(retry int, backoff ", timePackage.Ident("Duration"), ") ", builderName, " {")
+	g.P("	c.retry = retry")
+	g.P("	c.backoff = backoff")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效")
+	g.P("func (c *", structName, ") WithHedge(delay ", timePackage.Ident("Duration"), ") ", builderName, " {")
+	g.P("	c.hedge = delay")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("// 是否使用熔断, 默认开启")
+	g.P("func (c *", structName, ") WithBreaker(enabled bool) ", builderName, " {")
+	g.P("	c.breaker = enabled")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("func (c *", structName, ") callPolicy(idempotent bool) ", policyPackage.Ident("Policy"), " {")
+	g.P("	return ", policyPackage.Ident("Policy"), "{")
+	g.P("		Timeout: c.timeout,")
+	g.P("		Retry: c.retry,")
+	g.P("		Backoff: c.backoff,")
+	g.P("		Hedge: c.hedge,")
+	g.P("		Idempotent: idempotent,")
+	g.P("		Breaker: c.breaker,")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
+
 func (serviceGenerateHelper) generateUnimplementedServerType(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service) {
 	serverType := service.GoName + "Server"
 	mustOrShould := "must"
@@ -352,7 +414,12 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("type ", clientsMulticastName, " interface {")
 	g.P("    WhereRegex(regex string) " + clientsMulticastName)
 	g.P("    WherePrefix(prefix bool) " + clientsMulticastName)
+	g.P("    WhereZone(zone string) " + clientsMulticastName)
+	g.P("    WhereAllZone() " + clientsMulticastName)
 	g.P("    Local() " + clientsMulticastName)
+	helper.generatePolicyInterface(g, clientsMulticastName)
+	g.P("    WithConcurrency(concurrency int) " + clientsMulticastName)
+	g.P("    WithQuorum(quorum int) " + clientsMulticastName)
 	for _, method := range service.Methods {
 		g.Annotate(clientsMulticastName+"."+method.GoName, method.Location)
 		if method.Desc.Options().(*descriptorpb.MethodOptions).GetDeprecated() {
@@ -360,6 +427,10 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 		}
 		g.P(method.Comments.Leading,
 			clientsMulticastSignature(g, method))
+		if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
+			g.P(clientsGatherSignature(g, method))
+			g.P(clientsGatherChanSignature(g, method))
+		}
 	}
 	g.P("}")
 	g.P()
@@ -371,7 +442,11 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("    WherePeerFullName(appFullName string) " + clientsUnicastName)
 	g.P("    WhereAddress(address string) " + clientsUnicastName)
 	g.P("    WhereUser(userId string) " + clientsUnicastName)
+	g.P("    WhereKey(key string) " + clientsUnicastName)
+	g.P("    WhereZone(zone string) " + clientsUnicastName)
+	g.P("    WhereAllZone() " + clientsUnicastName)
 	g.P("    Local() " + clientsUnicastName)
+	helper.generatePolicyInterface(g, clientsUnicastName)
 	g.P()
 	for _, method := range service.Methods {
 		g.Annotate(clientsUnicastName+"."+method.GoName, method.Location)
@@ -398,45 +473,13 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("var Default", clientsName, " = New", clientsName, "()")
 
 	// func getClient
+	// 连接由runtime管理, 同一个地址共用一个连接
 	g.P("func (c *", unexport(service.GoName), "Clients) getClient(address string) (", PbPackageIdent(clientName), ", error) {")
-	g.P("c.mu.Lock()")
-	g.P("var pool *sync.Pool")
-	g.P("var ok bool")
-	g.P("if pool, ok = c.clientPool[address]; !ok {")
-	g.P("	pool = &sync.Pool{")
-	g.P("		New: func() any {")
-	g.P("		conn, err := grpc.Dial(address, grpc.WithInsecure())")
-	g.P("	if err != nil {")
-	g.P("			return err")
-	g.P("	}")
-	g.P("	client := ", PbPackageIdent("New"+clientName), "(conn)")
-	g.P("	return client")
-	g.P("},")
-	g.P("}")
-	g.P("c.clientPool[address] = pool")
-	g.P("c.mu.Unlock()")
-	g.P("} else {")
-	g.P("	c.mu.Unlock()")
-	g.P("}")
-	g.P("if v := pool.Get(); v == nil {")
-	g.P("	return nil, ", errorsPackage.Ident("ErrGrpcClientPoolNil"))
-	g.P("} else if err, ok := v.(error); ok {")
+	g.P("conn, err := ", facadePackage.Ident("GetGrpcConn"), "(address)")
+	g.P("if err != nil {")
 	g.P("	return nil, err")
-	g.P("} else {")
-	g.P("	return v.(", PbPackageIdent(clientName), "), nil")
-	g.P("}")
-	g.P("}")
-	g.P()
-
-	// func putClient
-	g.P("func (c *", unexport(service.GoName), "Clients) putClient(address string, client ", PbPackageIdent(clientName), ") {")
-	g.P("c.mu.Lock()")
-	g.P("var pool *sync.Pool")
-	g.P("var ok bool")
-	g.P("if pool, ok = c.clientPool[address]; ok {")
-	g.P("	pool.Put(client)")
 	g.P("}")
-	g.P("c.mu.Unlock()")
+	g.P("return ", PbPackageIdent("New"+clientName), "(conn), nil")
 	g.P("}")
 	g.P()
 
@@ -451,7 +494,8 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("func (c *", unexport(service.GoName), "Clients) Unicast() ", clientsUnicastName, " {")
 	g.P("   headers := make(map[string]string)")
 	g.P("	u := &" + unexport(clientsUnicastName) + "{")
-	g.P("		timeout: 5,")
+	g.P("		timeout: 5 * ", timePackage.Ident("Second"), ",")
+	g.P("		breaker: true,")
 	g.P("       headers: ", metaPackage.Ident("New"), "(headers),")
 	g.P("		client: c,")
 	g.P("	}")
@@ -463,7 +507,8 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("func (c *", unexport(service.GoName), "Clients) Multicast(count int) ", clientsMulticastName, " {")
 	g.P("   headers := make(map[string]string)")
 	g.P("	u := &" + unexport(clientsMulticastName) + "{")
-	g.P("		timeout: 5,")
+	g.P("		timeout: 5 * ", timePackage.Ident("Second"), ",")
+	g.P("		breaker: true,")
 	g.P("		count: count,")
 	g.P("       headers: ", metaPackage.Ident("New"), "(headers),")
 	g.P("		serviceName: ", fmtPackage.Ident("Sprintf"), "(\"%s/\", c.serviceName),")
@@ -476,6 +521,8 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	// func Broadcast
 	g.P("func (c *", unexport(service.GoName), "Clients) Broadcast() ", clientsMulticastName, " {")
 	g.P("	u := &" + unexport(clientsMulticastName) + "{")
+	g.P("		timeout: 5 * ", timePackage.Ident("Second"), ",")
+	g.P("		breaker: true,")
 	g.P("		count: -1,")
 	g.P("		serviceName: ", fmtPackage.Ident("Sprintf"), "(\"%s/\", c.serviceName),")
 	g.P("		client: c,")
@@ -536,6 +583,38 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("	return c")
 	g.P("}")
 	g.P()
+	// func WhereKey
+	g.P("// 根据key一致性hash选择服务, 服务名为Where设置的目录，默认为服务目录")
+	g.P("func (c *", unexport(service.GoName), "ClientsUnicast) WhereKey(key string) ", clientsUnicastName, " {")
+	g.P("	c.key = key")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	// func WhereZone
+	g.P("// 在指定的区查找服务, 默认只查找本区")
+	g.P("func (c *", unexport(service.GoName), "ClientsUnicast) WhereZone(zone string) ", clientsUnicastName, " {")
+	g.P("	c.zone = zone")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	// func WhereAllZone
+	g.P("// 在全部区查找服务, 本区优先")
+	g.P("func (c *", unexport(service.GoName), "ClientsUnicast) WhereAllZone() ", clientsUnicastName, " {")
+	g.P("	c.allZone = true")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	helper.generatePolicyMethods(g, unexport(service.GoName)+"ClientsUnicast", clientsUnicastName)
+	g.P("func (c *", unexport(service.GoName), "ClientsUnicast) whereOpts(opts ...", optionsPackage.Ident("WhereOption"), ") []", optionsPackage.Ident("WhereOption"), " {")
+	g.P("	if len(c.zone) > 0 {")
+	g.P("		opts = append(opts, ", optionsPackage.Ident("WithWhereZoneOption"), "(c.zone))")
+	g.P("	}")
+	g.P("	if c.allZone {")
+	g.P("		opts = append(opts, ", optionsPackage.Ident("WithWhereAllZoneOption"), "())")
+	g.P("	}")
+	g.P("	return opts")
+	g.P("}")
+	g.P()
 	methodIndex = 0
 	streamIndex = 0
 	for _, method := range service.Methods {
@@ -599,6 +678,7 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("	return c")
 	g.P("}")
 	g.P()
+	helper.generatePolicyMethods(g, unexport(service.GoName)+"ClientsMulticast", clientsMulticastName)
 	// func WhereRegex
 	g.P("func (c *", unexport(service.GoName), "ClientsMulticast) WhereRegex(regex string) ", clientsMulticastName, " {")
 	g.P("	c.regex = regex")
@@ -612,12 +692,28 @@ func genService(gen *protogen.Plugin, file *protogen.File, g *protogen.Generated
 	g.P("	return c")
 	g.P("}")
 	g.P()
+	// func WhereZone
+	g.P("// 在指定的区查找服务, 默认只查找本区")
+	g.P("func (c *", unexport(service.GoName), "ClientsMulticast) WhereZone(zone string) ", clientsMulticastName, " {")
+	g.P("	c.zone = zone")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	// func WhereAllZone
+	g.P("// 在全部区查找服务, 本区优先")
+	g.P("func (c *", unexport(service.GoName), "ClientsMulticast) WhereAllZone() ", clientsMulticastName, " {")
+	g.P("	c.allZone = true")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	genClientsGatherHelper(g, service, clientsMulticastName)
 	methodIndex = 0
 	streamIndex = 0
 	for _, method := range service.Methods {
 		if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
 			// Unary RPC method
 			genClientsMulticastMethod(gen, file, g, method, methodIndex)
+			genClientsGatherMethod(g, method)
 			methodIndex++
 		} else {
 			// Streaming RPC method
@@ -696,6 +792,109 @@ func clientsSignature(g *protogen.GeneratedFile, method *protogen.Method) string
 	return s
 }
 
+func clientsGatherSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
+	return method.GoName + "Gather(ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context")) +
+		", in *" + g.QualifiedGoIdent(method.Input.GoIdent) +
+		", f func(result *" + g.QualifiedGoIdent(scatterPackage.Ident("Result")) + "[*" + g.QualifiedGoIdent(method.Output.GoIdent) + "])" +
+		", opts ..." + g.QualifiedGoIdent(grpcPackage.Ident("CallOption")) + ") error"
+}
+
+func clientsGatherChanSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
+	return method.GoName + "GatherChan(ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context")) +
+		", in *" + g.QualifiedGoIdent(method.Input.GoIdent) +
+		", opts ..." + g.QualifiedGoIdent(grpcPackage.Ident("CallOption")) + ") *" +
+		g.QualifiedGoIdent(scatterPackage.Ident("Stream")) + "[*" + g.QualifiedGoIdent(method.Output.GoIdent) + "]"
+}
+
+// scatter-gather共用的方法
+func genClientsGatherHelper(g *protogen.GeneratedFile, service *protogen.Service, clientsMulticastName string) {
+	structName := unexport(service.GoName) + "ClientsMulticast"
+	g.P("// 最多同时调用多少个节点, 只对Gather生效, 0表示不限制")
+	g.P("func (c *", structName, ") WithConcurrency(concurrency int) ", clientsMulticastName, " {")
+	g.P("	c.concurrency = concurrency")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("// 成功多少个节点后返回, 只对Gather生效, 0表示等待全部节点")
+	g.P("func (c *", structName, ") WithQuorum(quorum int) ", clientsMulticastName, " {")
+	g.P("	c.quorum = quorum")
+	g.P("	return c")
+	g.P("}")
+	g.P()
+	g.P("func (c *", structName, ") gatherOptions() ", scatterPackage.Ident("Options"), " {")
+	g.P("	return ", scatterPackage.Ident("Options"), "{")
+	g.P("		Concurrency: c.concurrency,")
+	g.P("		Quorum: c.quorum,")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// 查找要调用的节点, Gather不支持Local")
+	g.P("func (c *", structName, ") wherePeers() ([]*", giraPackage.Ident("Peer"), ", error) {")
+	g.P("	var whereOpts []", optionsPackage.Ident("WhereOption"))
+	g.P("	whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereCatalogOption"), "())")
+	g.P("	if c.count > 0 {whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereMaxCountOption"), "(c.count))}")
+	g.P("	serviceName := c.serviceName")
+	g.P("	if len(c.regex) > 0 {")
+	g.P("		serviceName = ", fmtPackage.Ident("Sprintf"), "(\"%s%s\", c.serviceName, c.regex)")
+	g.P("		whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereRegexOption"), "())")
+	g.P("	}")
+	g.P("	if c.prefix {")
+	g.P("		whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWherePrefixOption"), "())")
+	g.P("	}")
+	g.P("	if len(c.zone) > 0 {")
+	g.P("		whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereZoneOption"), "(c.zone))")
+	g.P("	}")
+	g.P("	if c.allZone {")
+	g.P("		whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereAllZoneOption"), "())")
+	g.P("	}")
+	g.P("	return ", facadePackage.Ident("WhereIsServiceName"), "(serviceName, whereOpts...)")
+	g.P("}")
+	g.P()
+}
+
+// scatter-gather, 结果按节点返回的顺序通过回调或者channel返回
+func genClientsGatherMethod(g *protogen.GeneratedFile, method *protogen.Method) {
+	service := method.Parent
+	structName := unexport(service.GoName) + "ClientsMulticast"
+	callName := unexport(method.GoName) + "Call"
+	g.P("// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时")
+	g.P("func (c *", structName, ") ", clientsGatherSignature(g, method), " {")
+	g.P("	peers, err := c.wherePeers()")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	return ", scatterPackage.Ident("Gather"), "(ctx, peers, c.gatherOptions(), c.", callName, "(in, opts...), f)")
+	g.P("}")
+	g.P()
+	g.P("// 和", method.GoName, "Gather一样, 结果通过channel返回")
+	g.P("func (c *", structName, ") ", clientsGatherChanSignature(g, method), " {")
+	g.P("	peers, err := c.wherePeers()")
+	g.P("	if err != nil {")
+	g.P("		return ", scatterPackage.Ident("NewErrorStream"), "[*", method.Output.GoIdent, "](err)")
+	g.P("	}")
+	g.P("	return ", scatterPackage.Ident("GatherChan"), "(ctx, peers, c.gatherOptions(), c.", callName, "(in, opts...))")
+	g.P("}")
+	g.P()
+	g.P("func (c *", structName, ") ", callName, "(in *", method.Input.GoIdent, ", opts ...", grpcPackage.Ident("CallOption"), ") func(ctx ", contextPackage.Ident("Context"), ", peer *", giraPackage.Ident("Peer"), ") (*", method.Output.GoIdent, ", error) {")
+	g.P("	return func(ctx ", contextPackage.Ident("Context"), ", peer *", giraPackage.Ident("Peer"), ") (*", method.Output.GoIdent, ", error) {")
+	g.P("		var address string")
+	g.P("		if ", facadePackage.Ident("IsEnableResolver()"), "{")
+	g.P("			address = peer.Url")
+	g.P("		} else {")
+	g.P("			address = peer.Address")
+	g.P("		}")
+	g.P("		client, err := c.client.getClient(address)")
+	g.P("		if err != nil {")
+	g.P("			return nil, ", policyPackage.Ident("Wrap"), "(peer.FullName, err)")
+	g.P("		}")
+	g.P("		return ", policyPackage.Ident("Invoke"), "(ctx, c.callPolicy(", isIdempotent(method), "), peer.FullName, address, func(ctx ", contextPackage.Ident("Context"), ") (*", method.Output.GoIdent, ", error) {")
+	g.P("			return client.", method.Desc.Name(), "(ctx, in, opts...)")
+	g.P("		})")
+	g.P("	}")
+	g.P("}")
+	g.P()
+}
+
 func clientsMulticastSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
 	s := method.GoName + "(ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context"))
 	if !method.Desc.IsStreamingClient() {
@@ -736,7 +935,6 @@ func genClientsMethod(gen *protogen.Plugin, file *protogen.File, g *protogen.Gen
 	if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
 		g.P("client, err := c.getClient(address)")
 		g.P("if err != nil { return nil, err }")
-		g.P("defer c.putClient(address, client)")
 		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, in, opts...)`)
 		g.P("if err != nil { return nil, err }")
 		g.P("return out, nil")
@@ -746,7 +944,6 @@ func genClientsMethod(gen *protogen.Plugin, file *protogen.File, g *protogen.Gen
 	} else if method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
 		g.P("client, err := c.getClient(address)")
 		g.P("if err != nil { return nil, err }")
-		g.P("defer c.putClient(address, client)")
 		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, in, opts...)`)
 		g.P("if err != nil { return nil, err }")
 		g.P("return out, nil")
@@ -756,7 +953,6 @@ func genClientsMethod(gen *protogen.Plugin, file *protogen.File, g *protogen.Gen
 	} else {
 		g.P("client, err := c.getClient(address)")
 		g.P("if err != nil { return nil, err }")
-		g.P("defer c.putClient(address, client)")
 		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, opts...)`)
 		g.P("if err != nil { return nil, err }")
 		g.P("return out, nil")
@@ -776,7 +972,7 @@ func genClientsUnicastMethod(gen *protogen.Plugin, file *protogen.File, g *proto
 
 	g.P("    if c.local {")
 	if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
-		g.P("    cancelCtx, cancelFunc := ", contextPackage.Ident("WithTimeout"), "(ctx, ", timePackage.Ident("Second"), "*", timePackage.Ident("Duration"), "(c.timeout))")
+		g.P("    cancelCtx, cancelFunc := ", contextPackage.Ident("WithTimeout"), "(ctx, c.timeout)")
 		g.P("    defer cancelFunc()")
 		g.P("    if c.headers.Len() > 0 {")
 		g.P("        cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)")
@@ -790,17 +986,24 @@ func genClientsUnicastMethod(gen *protogen.Plugin, file *protogen.File, g *proto
 		g.P(" 	 } ")
 		g.P()
 	} else if method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
-		g.P("return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), `, "method `, method.GoName, ` not implemented")`)
+		g.P("// 流不能在进程内直接调用, 通过本节点的地址建立")
+		g.P("c.local = false")
+		g.P("return c.WherePeerFullName(", facadePackage.Ident("GetAppFullName"), "()).", method.GoName, "(ctx, in, opts...)")
 		g.P()
 	} else {
-		g.P("return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), `, "method `, method.GoName, ` not implemented")`)
+		g.P("// 流不能在进程内直接调用, 通过本节点的地址建立")
+		g.P("c.local = false")
+		g.P("return c.WherePeerFullName(", facadePackage.Ident("GetAppFullName"), "()).", method.GoName, "(ctx, opts...)")
 		g.P()
 	}
 	g.P("    } else {")
 	g.P("var address string")
+	g.P("var peerName string")
 	g.P("if len(c.address) > 0 {")
 	g.P("	address = c.address")
+	g.P("	peerName = c.address")
 	g.P("} else if len(c.peerFullName) > 0 {")
+	g.P("	peerName = c.peerFullName")
 	g.P("	if peer, err := ", facadePackage.Ident("WhereIsPeer"), "(c.peerFullName); err != nil {")
 	g.P("       return nil, err")
 	g.P("   } else if ", facadePackage.Ident("IsEnableResolver()"), " {")
@@ -810,25 +1013,47 @@ func genClientsUnicastMethod(gen *protogen.Plugin, file *protogen.File, g *proto
 	g.P("   }")
 	g.P("} else if c.peer != nil && ", facadePackage.Ident("IsEnableResolver()"), " {")
 	g.P("	address = c.peer.Url")
+	g.P("	peerName = c.peer.FullName")
 	g.P("} else if c.peer != nil {")
 	g.P("	address = c.peer.Address")
+	g.P("	peerName = c.peer.FullName")
+	g.P("} else if len(c.key) > 0 {")
+	g.P("	serviceName := c.client.serviceName")
+	g.P("	if len(c.serviceName) > 0 {")
+	g.P("		serviceName = c.serviceName")
+	g.P("	}")
+	g.P("	if peers, err := ", facadePackage.Ident("WhereIsServiceName"), "(serviceName, c.whereOpts(", optionsPackage.Ident("WithWhereKeyOption"), "(c.key))...); err != nil {")
+	g.P("		return nil, err")
+	g.P("	} else if len(peers) < 1 {")
+	g.P("		return nil, ", errorsPackage.Ident("ErrPeerNotFound"))
+	g.P("	} else if ", facadePackage.Ident("IsEnableResolver()"), " {")
+	g.P("		address = peers[0].Url")
+	g.P("		peerName = peers[0].FullName")
+	g.P("	} else  {")
+	g.P("		address = peers[0].Address")
+	g.P("		peerName = peers[0].FullName")
+	g.P("	}")
 	g.P("} else if len(c.serviceName) > 0 {")
-	g.P("	if peers, err := ", facadePackage.Ident("WhereIsServiceName"), "(c.serviceName); err != nil {")
+	g.P("	if peers, err := ", facadePackage.Ident("WhereIsServiceName"), "(c.serviceName, c.whereOpts()...); err != nil {")
 	g.P("		return nil, err")
 	g.P("	} else if len(peers) < 1 {")
 	g.P("		return nil, ", errorsPackage.Ident("ErrPeerNotFound"))
 	g.P("	} else if ", facadePackage.Ident("IsEnableResolver()"), " {")
 	g.P("		address = peers[0].Url")
+	g.P("		peerName = peers[0].FullName")
 	g.P("	} else  {")
 	g.P("		address = peers[0].Address")
+	g.P("		peerName = peers[0].FullName")
 	g.P("	}")
 	g.P("} else if len(c.userId) > 0 {")
 	g.P("	if peer, err := ", facadePackage.Ident("WhereIsUser"), "(c.userId); err != nil {")
 	g.P("		return nil, err")
 	g.P("	} else if ", facadePackage.Ident("IsEnableResolver()"), " {")
 	g.P("		address = peer.Url")
+	g.P("		peerName = peer.FullName")
 	g.P("	} else {")
 	g.P("		address = peer.Address")
+	g.P("		peerName = peer.FullName")
 	g.P("	}")
 	g.P("}")
 	g.P("if len(address) <= 0 {")
@@ -836,38 +1061,37 @@ func genClientsUnicastMethod(gen *protogen.Plugin, file *protogen.File, g *proto
 	g.P("}")
 	if !method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
 		g.P("client, err := c.client.getClient(address)")
-		g.P("if err != nil { return nil, err }")
-		g.P("defer c.client.putClient(address, client)")
+		g.P("if err != nil { return nil, ", policyPackage.Ident("Wrap"), "(peerName, err) }")
 		g.P("if c.headers.Len() > 0 {")
 		g.P("    ctx = metadata.NewOutgoingContext(ctx, c.headers)")
 		g.P("}")
-		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, in, opts...)`)
-		g.P("if err != nil { return nil, err }")
-		g.P("return out, nil")
+		g.P("return ", policyPackage.Ident("Invoke"), "(ctx, c.callPolicy(", isIdempotent(method), "), peerName, address, func(ctx ", contextPackage.Ident("Context"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P(`	return client.`, method.Desc.Name(), `(ctx, in, opts...)`)
+		g.P("})")
 		g.P("}")
 		g.P()
 	} else if method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
 		g.P("client, err := c.client.getClient(address)")
-		g.P("if err != nil { return nil, err }")
-		g.P("defer c.client.putClient(address, client)")
+		g.P("if err != nil { return nil, ", policyPackage.Ident("Wrap"), "(peerName, err) }")
 		g.P("if c.headers.Len() > 0 {")
 		g.P("    ctx = metadata.NewOutgoingContext(ctx, c.headers)")
 		g.P("}")
-		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, in, opts...)`)
-		g.P("if err != nil { return nil, err }")
-		g.P("return out, nil")
+		g.P("// 流不设置超时, 只使用熔断")
+		g.P("return ", policyPackage.Ident("Invoke"), "(ctx, ", policyPackage.Ident("Policy"), "{Breaker: c.breaker}, peerName, address, func(ctx ", contextPackage.Ident("Context"), ") (", method.Parent.GoName, "_", method.GoName, "Client, error) {")
+		g.P(`	return client.`, method.Desc.Name(), `(ctx, in, opts...)`)
+		g.P("})")
 		g.P("}")
 		g.P()
 	} else {
 		g.P("client, err := c.client.getClient(address)")
-		g.P("if err != nil { return nil, err }")
-		g.P("defer c.client.putClient(address, client)")
+		g.P("if err != nil { return nil, ", policyPackage.Ident("Wrap"), "(peerName, err) }")
 		g.P("if c.headers.Len() > 0 {")
 		g.P("    ctx = metadata.NewOutgoingContext(ctx, c.headers)")
 		g.P("}")
-		g.P(`out, err := client.`, method.Desc.Name(), `(ctx, opts...)`)
-		g.P("if err != nil { return nil, err }")
-		g.P("return out, nil")
+		g.P("// 流不设置超时, 只使用熔断")
+		g.P("return ", policyPackage.Ident("Invoke"), "(ctx, ", policyPackage.Ident("Policy"), "{Breaker: c.breaker}, peerName, address, func(ctx ", contextPackage.Ident("Context"), ") (", method.Parent.GoName, "_", method.GoName, "Client, error) {")
+		g.P(`	return client.`, method.Desc.Name(), `(ctx, opts...)`)
+		g.P("})")
 		g.P("}")
 		g.P()
 	}
@@ -889,7 +1113,7 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 		g.P("	     return nil, ", errorsPackage.Ident("ErrServerNotFound"))
 		g.P(" 	 } else if svr, ok := s.(", PbPackageIdent(service.GoName), "Server); ok {")
 		g.P("		result := &", method.Output.GoIdent.GoName, "_MulticastResult{}")
-		g.P("		cancelCtx, cancelFunc := ", contextPackage.Ident("WithTimeout"), "(ctx, ", timePackage.Ident("Second"), "*", timePackage.Ident("Duration"), "(c.timeout))")
+		g.P("		cancelCtx, cancelFunc := ", contextPackage.Ident("WithTimeout"), "(ctx, c.timeout)")
 		g.P("		defer cancelFunc()")
 		g.P("		if c.headers.Len() > 0 {")
 		g.P("			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)")
@@ -903,11 +1127,25 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 		g.P("	 } else {")
 		g.P("	     return nil, ", errorsPackage.Ident("ErrServerNotFound"))
 		g.P("	 }")
-	} else if method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
-		g.P("return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), `, "method `, method.GoName, ` not implemented")`)
-		g.P()
 	} else {
-		g.P("return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), `, "method `, method.GoName, ` not implemented")`)
+		args := "ctx, opts..."
+		if !method.Desc.IsStreamingClient() {
+			args = "ctx, in, opts..."
+		}
+		g.P("// 流不能在进程内直接调用, 通过本节点的地址建立")
+		g.P("peer, err := ", facadePackage.Ident("WhereIsPeer"), "(", facadePackage.Ident("GetAppFullName"), "())")
+		g.P("if err != nil {")
+		g.P("	return nil, err")
+		g.P("}")
+		g.P("result := &", method.Parent.GoName, "_", method.GoName, "Client_MulticastResult{peerCount: 1}")
+		g.P("if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).", method.GoName, "(", args, "); err != nil {")
+		g.P("	result.errors = append(result.errors, err)")
+		g.P("	result.errorPeers = append(result.errorPeers, peer)")
+		g.P("} else {")
+		g.P("	result.responses = append(result.responses, out)")
+		g.P("	result.successPeers = append(result.successPeers, peer)")
+		g.P("}")
+		g.P("return result, nil")
 		g.P()
 	}
 
@@ -926,6 +1164,12 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 	g.P("if c.prefix {")
 	g.P("    whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWherePrefixOption"), "())")
 	g.P("}")
+	g.P("if len(c.zone) > 0 {")
+	g.P("    whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereZoneOption"), "(c.zone))")
+	g.P("}")
+	g.P("if c.allZone {")
+	g.P("    whereOpts = append(whereOpts, ", optionsPackage.Ident("WithWhereAllZoneOption"), "())")
+	g.P("}")
 	g.P("peers, err := ", facadePackage.Ident("WhereIsServiceName"), "(serviceName, whereOpts...)")
 	g.P("if err != nil {")
 	g.P("	return nil, err")
@@ -948,18 +1192,18 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 		g.P("	}")
 		g.P("	client, err := c.client.getClient(address)")
 		g.P("	if err != nil { ")
-		g.P("		result.errors = append(result.errors, err)")
+		g.P("		result.errors = append(result.errors, ", policyPackage.Ident("Wrap"), "(peer.FullName, err))")
 		g.P("		result.errorPeers = append(result.errorPeers, peer)")
 		g.P("		continue")
 		g.P("	}")
-		g.P(`	out, err := client.`, method.Desc.Name(), `(ctx, in, opts...)`)
+		g.P("	out, err := ", policyPackage.Ident("Invoke"), "(ctx, c.callPolicy(", isIdempotent(method), "), peer.FullName, address, func(ctx ", contextPackage.Ident("Context"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P(`		return client.`, method.Desc.Name(), `(ctx, in, opts...)`)
+		g.P("	})")
 		g.P("	if err != nil { ")
 		g.P("		result.errors = append(result.errors, err)")
 		g.P("		result.errorPeers = append(result.errorPeers, peer)")
-		g.P("		c.client.putClient(address, client)")
 		g.P("		continue")
 		g.P("	}")
-		g.P("	c.client.putClient(address, client)")
 		g.P("	result.responses = append(result.responses, out)")
 		g.P("	result.successPeers = append(result.successPeers, peer)")
 		g.P("}")
@@ -984,7 +1228,6 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 		g.P("	if err != nil { ")
 		g.P("		result.errors = append(result.errors, err)")
 		g.P("		result.errorPeers = append(result.errorPeers, peer)")
-		g.P("		c.client.putClient(address, client)")
 		g.P("		continue")
 		g.P("	}")
 		g.P("	result.responses = append(result.responses, out)")
@@ -1011,7 +1254,6 @@ func genClientsMulticastMethod(gen *protogen.Plugin, file *protogen.File, g *pro
 		g.P("	if err != nil { ")
 		g.P("		result.errors = append(result.errors, err)")
 		g.P("		result.errorPeers = append(result.errorPeers, peer)")
-		g.P("		c.client.putClient(address, client)")
 		g.P("		continue")
 		g.P("	}")
 		g.P("	result.responses = append(result.responses, out)")
@@ -1177,3 +1419,12 @@ func genLeadingComments(g *protogen.GeneratedFile, loc protoreflect.SourceLocati
 const deprecationComment = "// Deprecated: Do not use."
 
 func unexport(s string) string { return strings.ToLower(s[:1]) + s[1:] }
+
+// 方法是否幂等, 在proto中用 option idempotency_level = IDEMPOTENT 标记
+func isIdempotent(method *protogen.Method) string {
+	switch method.Desc.Options().(*descriptorpb.MethodOptions).GetIdempotencyLevel() {
+	case descriptorpb.MethodOptions_IDEMPOTENT, descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+		return "true"
+	}
+	return "false"
+}
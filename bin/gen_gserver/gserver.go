@@ -123,6 +123,7 @@ func (serviceGenerateHelper) generateServerRouterMiddlewareContext(gen *protogen
 	g.P("	 err error")
 	g.P("	 method string")
 	g.P("	 c chan struct{}")
+	g.P("	 done bool")
 	g.P("}")
 	g.P("func (m *", unexport(serverRouterType), "MiddlewareContext) Handler() ", serverType, " {")
 	g.P("    return m.handler")
@@ -134,6 +135,7 @@ func (serviceGenerateHelper) generateServerRouterMiddlewareContext(gen *protogen
 	g.P("        }")
 	g.P("    }()")
 	g.P("    m.out, m.err = m.invoke()")
+	g.P("    m.done = true")
 	g.P("    if m.c != nil {")
 	g.P("        m.c <- struct{}{}")
 	g.P("    }")
@@ -191,10 +193,6 @@ func (serviceGenerateHelper) generateServerRouterType(gen *protogen.Plugin, file
 	g.P("}")
 	g.P()
 	for _, method := range service.Methods {
-		nilArg := ""
-		if !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer() {
-			nilArg = "nil,"
-		}
 		g.P("func (svr* ", unexport(serverRouterType), ") ", serverSignature(g, method), "{")
 
 		if !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer() {
@@ -241,7 +239,53 @@ func (serviceGenerateHelper) generateServerRouterType(gen *protogen.Plugin, file
 			g.P("       }")
 			g.P("   }")
 		} else {
-			g.P("return ", nilArg, statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), `, "method `, method.GoName, ` not implemented")`)
+			// 流式方法, 路由key在流的metadata中
+			fmSymbol := helper.formatFullMethodSymbol(service, method)
+			var args, in string
+			if method.Desc.IsStreamingClient() {
+				args = "s"
+				in = "nil"
+			} else {
+				args = "in, s"
+				in = "in"
+			}
+			g.P("	ctx := s.Context()")
+			g.P("	var kv metadata.MD")
+			g.P("   var ok bool")
+			g.P("	if kv, ok = ", metaPackage.Ident("FromIncomingContext"), "(ctx); !ok {")
+			g.P("       return ", errorsPackage.Ident("ErrServerRouterMetaNotFound"))
+			g.P("	}")
+			g.P("	if keys, ok := kv[", giraPackage.Ident("GRPC_PATH_KEY"), "]; !ok {")
+			g.P("       return ", errorsPackage.Ident("ErrServerRouterKeyNotFound"))
+			g.P("	} else if len(keys) <= 0 {")
+			g.P("       return ", errorsPackage.Ident("ErrServerRouterKeyNotFound"))
+			g.P("	} else if v, ok := svr.handlers.Load(keys[0]); !ok {")
+			g.P("       return ", errorsPackage.Ident("ErrServerRouterHandlerNotRegist"))
+			g.P("	} else if handler, ok := v.(", serverType, "); !ok {")
+			g.P("       return ", errorsPackage.Ident("ErrServerRouterHandlerNotImplement"))
+			g.P("	} else {")
+			g.P("		if middleware := svr.middleware; middleware == nil {")
+			g.P("	        return handler.", method.GoName, "(", args, ")")
+			g.P("		} else {")
+			g.P("			r := &", unexport(serverRouterType), "MiddlewareContext{")
+			g.P("               fullMethod: ", fmSymbol, ",")
+			g.P("               method:	    \"", method.GoName, "\",")
+			g.P("				ctx:        ctx,")
+			g.P("				in: ", in, ",")
+			g.P("				handler: handler,")
+			g.P("				invoke:    func() (resp interface{}, err error) {")
+			g.P("					return nil, handler.", method.GoName, "(", args, ")")
+			g.P("				},")
+			g.P("			}")
+			g.P("			if err := middleware.", serverRouterType, "MiddlewareInvoke(r); err != nil {")
+			g.P("			    return err")
+			g.P("			} ")
+			g.P("			if !r.done && r.err == nil {")
+			g.P("			    return ", errorsPackage.Ident("ErrServerRouterHandlerNotImplement"))
+			g.P("			}")
+			g.P("			return r.err")
+			g.P("       }")
+			g.P("   }")
 		}
 		g.P("}")
 	}
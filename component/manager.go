@@ -0,0 +1,159 @@
+package component
+
+///
+/// 运行时组件管理
+///
+///   - 按依赖排序, 没有依赖关系的组件按注册的顺序
+///   - 按顺序创建和启动, 只停止已经启动的组件, 按相反的顺序
+///   - 每个组件的停止有超时时间, 超时后不再等待, 继续停止下一个组件
+///
+import (
+	"context"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+type Manager struct {
+	stopTimeout time.Duration // 默认的停止超时时间
+	components  []gira.RuntimeComponent
+	dict        map[string]gira.RuntimeComponent
+	sorted      []gira.RuntimeComponent
+	started     int // 已经启动的组件数量, sorted的前缀
+}
+
+func NewManager(stopTimeout time.Duration) *Manager {
+	return &Manager{
+		stopTimeout: stopTimeout,
+		dict:        make(map[string]gira.RuntimeComponent),
+	}
+}
+
+// 注册组件, 在Create之前调用
+func (m *Manager) Register(c gira.RuntimeComponent) error {
+	if _, ok := m.dict[c.Name()]; ok {
+		log.Errorw("component duplicate", "name", c.Name())
+		return errors.ErrComponentDuplicate
+	}
+	m.dict[c.Name()] = c
+	m.components = append(m.components, c)
+	return nil
+}
+
+func (m *Manager) Get(name string) gira.RuntimeComponent {
+	return m.dict[name]
+}
+
+// 排序后的组件
+func (m *Manager) Components() []gira.RuntimeComponent {
+	return m.sorted
+}
+
+// 排序, 然后按顺序创建
+func (m *Manager) Create() error {
+	sorted, err := Sort(m.components)
+	if err != nil {
+		return err
+	}
+	m.sorted = sorted
+	for _, c := range m.sorted {
+		log.Debugw("component create", "name", c.Name())
+		if err := c.Create(); err != nil {
+			log.Errorw("component create fail", "name", c.Name(), "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) Start(ctx context.Context) error {
+	for _, c := range m.sorted[m.started:] {
+		log.Debugw("component start", "name", c.Name())
+		if err := c.Start(ctx); err != nil {
+			log.Errorw("component start fail", "name", c.Name(), "error", err)
+			return err
+		}
+		m.started++
+	}
+	return nil
+}
+
+// 按相反的顺序停止已经启动的组件, 出错时继续停止其他的组件
+func (m *Manager) Stop() {
+	for ; m.started > 0; m.started-- {
+		c := m.sorted[m.started-1]
+		if err := m.stop(c); err != nil {
+			log.Warnw("component stop fail", "name", c.Name(), "error", err)
+		}
+	}
+}
+
+func (m *Manager) stop(c gira.RuntimeComponent) error {
+	timeout := m.stopTimeout
+	if v, ok := c.(gira.RuntimeComponentStopTimeout); ok {
+		timeout = v.StopTimeout()
+	}
+	log.Debugw("component stop", "name", c.Name(), "timeout", timeout)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+	defer cancelFunc()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.ErrComponentStopTimeout
+	}
+}
+
+// 按依赖排序, 没有依赖关系的保持原来的顺序
+func Sort(components []gira.RuntimeComponent) ([]gira.RuntimeComponent, error) {
+	index := make(map[string]int, len(components))
+	for i, c := range components {
+		index[c.Name()] = i
+	}
+	// 还没有排序的依赖数量
+	degrees := make([]int, len(components))
+	dependents := make([][]int, len(components))
+	for i, c := range components {
+		for _, name := range c.Dependencies() {
+			j, ok := index[name]
+			if !ok {
+				log.Errorw("component dependency not found", "name", c.Name(), "dependency", name)
+				return nil, errors.ErrComponentDependencyNotFound
+			}
+			degrees[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	sorted := make([]gira.RuntimeComponent, 0, len(components))
+	visited := make([]bool, len(components))
+	for len(sorted) < len(components) {
+		// 每次取注册顺序最前的一个
+		next := -1
+		for i := range components {
+			if !visited[i] && degrees[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			for i, c := range components {
+				if !visited[i] {
+					log.Errorw("component dependency cycle", "name", c.Name())
+				}
+			}
+			return nil, errors.ErrComponentDependencyCycle
+		}
+		visited[next] = true
+		sorted = append(sorted, components[next])
+		for _, i := range dependents[next] {
+			degrees[i]--
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,129 @@
+package component
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+type test_recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *test_recorder) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+}
+
+func (r *test_recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.names, ",")
+}
+
+type test_component struct {
+	name         string
+	dependencies []string
+	release      chan struct{} // 不为空时模拟超时, ctx取消并且放行后才返回
+	done         chan struct{} // 不为空时在Stop返回前关闭
+	stopped      *test_recorder
+}
+
+func (c *test_component) Name() string {
+	return c.name
+}
+
+func (c *test_component) Dependencies() []string {
+	return c.dependencies
+}
+
+func (c *test_component) Create() error {
+	return nil
+}
+
+func (c *test_component) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *test_component) Stop(ctx context.Context) error {
+	if c.release != nil {
+		<-ctx.Done()
+		<-c.release
+	}
+	c.stopped.add(c.name)
+	if c.done != nil {
+		close(c.done)
+	}
+	return nil
+}
+
+func names(components []gira.RuntimeComponent) string {
+	result := make([]string, 0, len(components))
+	for _, c := range components {
+		result = append(result, c.Name())
+	}
+	return strings.Join(result, ",")
+}
+
+func TestSort(t *testing.T) {
+	components := []gira.RuntimeComponent{
+		&test_component{name: "a", dependencies: []string{"c"}},
+		&test_component{name: "b"},
+		&test_component{name: "c"},
+		&test_component{name: "d", dependencies: []string{"a", "b"}},
+	}
+	sorted, err := Sort(components)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := names(sorted); v != "b,c,a,d" {
+		t.Fatalf("expected b,c,a,d, got %s", v)
+	}
+	components = append(components, &test_component{name: "e", dependencies: []string{"f"}})
+	if _, err := Sort(components); !errors.Is(err, errors.ErrComponentDependencyNotFound) {
+		t.Fatalf("expected dependency not found, got %v", err)
+	}
+	components = []gira.RuntimeComponent{
+		&test_component{name: "a", dependencies: []string{"b"}},
+		&test_component{name: "b", dependencies: []string{"a"}},
+	}
+	if _, err := Sort(components); !errors.Is(err, errors.ErrComponentDependencyCycle) {
+		t.Fatalf("expected dependency cycle, got %v", err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	stopped := &test_recorder{}
+	release := make(chan struct{})
+	done := make(chan struct{})
+	m := NewManager(50 * time.Millisecond)
+	m.Register(&test_component{name: "a", stopped: stopped})
+	m.Register(&test_component{name: "b", dependencies: []string{"a"}, release: release, done: done, stopped: stopped})
+	m.Register(&test_component{name: "c", dependencies: []string{"b"}, stopped: stopped})
+	if err := m.Register(&test_component{name: "a"}); !errors.Is(err, errors.ErrComponentDuplicate) {
+		t.Fatalf("expected duplicate, got %v", err)
+	}
+	if err := m.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	m.Stop()
+	// b超时后不再等待, a在b返回前已经停止
+	if v := stopped.String(); v != "c,a" {
+		t.Fatalf("expected c,a, got %s", v)
+	}
+	close(release)
+	<-done
+	if v := stopped.String(); v != "c,a,b" {
+		t.Fatalf("expected c,a,b, got %s", v)
+	}
+}
@@ -3,8 +3,10 @@ package gira
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Lyndon-Zhang/gira/errors"
@@ -144,6 +146,8 @@ type EtcdConfig struct {
 		Name  string `yaml:"name"`
 		Value string `yaml:"value"`
 	} `yaml:"advertise"`
+	UserCache UserCacheConfig  `yaml:"user-cache"`
+	Zones     []EtcdZoneConfig `yaml:"zones"` // 其他区的注册表, 只读
 }
 
 // registry配置
@@ -152,11 +156,33 @@ type EtcdClientConfig struct {
 		Host string `yaml:"host"`
 		Port int    `yaml:"port"`
 	} `yaml:"endpoints"`
-	Username     string `yaml:"username"`
-	Password     string `yaml:"password"`
-	DialTimeout  int    `yaml:"dial-timeout"`
-	LeaseTimeout int64  `yaml:"lease-timeout"`
-	Address      string `yaml:"address"`
+	Username     string           `yaml:"username"`
+	Password     string           `yaml:"password"`
+	DialTimeout  int              `yaml:"dial-timeout"`
+	LeaseTimeout int64            `yaml:"lease-timeout"`
+	Address      string           `yaml:"address"`
+	UserCache    UserCacheConfig  `yaml:"user-cache"`
+	Zones        []EtcdZoneConfig `yaml:"zones"` // 其他区的注册表, 只读
+}
+
+// 其他区的注册表配置
+type EtcdZoneConfig struct {
+	Zone      string `yaml:"zone"`
+	Endpoints []struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	} `yaml:"endpoints"`
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	DialTimeout int    `yaml:"dial-timeout"`
+}
+
+// 玩家位置缓存配置
+type UserCacheConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Mode        string `yaml:"mode"`        // full|lru, full缓存全部玩家, lru只缓存最近查找过的玩家
+	Size        int    `yaml:"size"`        // lru模式下的最大数量
+	Consistency string `yaml:"consistency"` // eventual|strong, strong模式下watch断开时直接查询etcd
 }
 
 // http模块配置
@@ -223,11 +249,113 @@ type PlatformConfig struct {
 }
 
 type GrpcConfig struct {
-	Address      string `yaml:"address"`
-	Workers      uint32 `yaml:"workers"`
-	Resolver     bool   `yaml:"resolver"` // 是否开启resolver
-	Admin        bool   `yaml:"admin"`
-	EnabledTrace bool   `yaml:"enabled-trace"`
+	Address      string         `yaml:"address"`
+	Workers      uint32         `yaml:"workers"`
+	Resolver     bool           `yaml:"resolver"` // 是否开启resolver
+	Admin        bool           `yaml:"admin"`
+	EnabledTrace bool           `yaml:"enabled-trace"`
+	Reflection   bool           `yaml:"reflection"` // 是否开启grpc reflection, 方便grpcurl调试
+	Conn         GrpcConnConfig `yaml:"conn"`       // 客户端连接
+	Breaker      BreakerConfig  `yaml:"breaker"`
+	// 慢调用阈值, 单位毫秒, 0表示不记录
+	SlowThreshold int64 `yaml:"slow-threshold"`
+	// 节点间认证的token, 为空时不校验
+	Token string `yaml:"token"`
+	// 节点间的mTLS, 为空时使用明文
+	Tls *GrpcTlsConfig `yaml:"tls"`
+}
+
+// grpc mTLS配置, 服务端和客户端使用同一套证书
+type GrpcTlsConfig struct {
+	Ca             string `yaml:"ca"`
+	Cert           string `yaml:"cert"`
+	Key            string `yaml:"key"`
+	ReloadInterval int    `yaml:"reload-interval"` // 检查证书文件修改的间隔, 单位秒
+}
+
+// 熔断配置
+type BreakerConfig struct {
+	Threshold   int `yaml:"threshold"`    // 连续失败多少次后打开
+	OpenTimeout int `yaml:"open-timeout"` // 打开多少秒后尝试恢复
+}
+
+// grpc客户端连接配置, 时间单位为秒
+type GrpcConnConfig struct {
+	IdleTimeout      int `yaml:"idle-timeout"`      // 空闲多久后关闭连接
+	CheckInterval    int `yaml:"check-interval"`    // 检查连接状态的间隔
+	KeepaliveTime    int `yaml:"keepalive-time"`    // 多久没有活动后发送ping
+	KeepaliveTimeout int `yaml:"keepalive-timeout"` // ping超时后断开连接
+}
+
+// 请求追踪配置
+type TraceConfig struct {
+	SampleRatio   float64 `yaml:"sample-ratio"`   // 采样比例, 0-1
+	Exporter      string  `yaml:"exporter"`       // file或者otlp
+	File          string  `yaml:"file"`           // exporter为file时的文件路径
+	Endpoint      string  `yaml:"endpoint"`       // exporter为otlp时的地址, 例如 http://127.0.0.1:4318/v1/traces
+	BatchSize     int     `yaml:"batch-size"`     // 每批导出的数量
+	QueueSize     int     `yaml:"queue-size"`     // 队列满时丢弃
+	FlushInterval int     `yaml:"flush-interval"` // 导出间隔, 单位秒
+}
+
+const (
+	SIGNAL_ACTION_RELOAD_RESOURCE = "reload-resource" // 重新加载资源, 和admin的ReloadResource一样
+	SIGNAL_ACTION_RELOAD_CONFIG   = "reload-config"   // 重新加载配置文件, 和admin的ReloadConfig一样
+	SIGNAL_ACTION_REOPEN_LOG      = "reopen-log"      // 重新打开日志文件
+	SIGNAL_ACTION_TOGGLE_DEBUG    = "toggle-debug"    // 切换debug日志级别
+	SIGNAL_ACTION_DUMP_GOROUTINE  = "dump-goroutine"  // 协程堆栈保存到日志目录
+	SIGNAL_ACTION_DUMP_HEAP       = "dump-heap"       // heap profile保存到日志目录
+)
+
+var signalActions = map[string]struct{}{
+	SIGNAL_ACTION_RELOAD_RESOURCE: {},
+	SIGNAL_ACTION_RELOAD_CONFIG:   {},
+	SIGNAL_ACTION_REOPEN_LOG:      {},
+	SIGNAL_ACTION_TOGGLE_DEBUG:    {},
+	SIGNAL_ACTION_DUMP_GOROUTINE:  {},
+	SIGNAL_ACTION_DUMP_HEAP:       {},
+}
+
+var defaultSignalActions = map[os.Signal][]string{
+	syscall.SIGUSR1: {SIGNAL_ACTION_RELOAD_RESOURCE, SIGNAL_ACTION_REOPEN_LOG},
+	syscall.SIGUSR2: {SIGNAL_ACTION_DUMP_GOROUTINE, SIGNAL_ACTION_DUMP_HEAP},
+}
+
+// 信号对应的动作, 可选值 reload-resource, reload-config, reopen-log, toggle-debug, dump-goroutine, dump-heap
+// 没有配置时, usr1: [reload-resource, reopen-log], usr2: [dump-goroutine, dump-heap]
+type SignalConfig struct {
+	Usr1 []string `yaml:"usr1"`
+	Usr2 []string `yaml:"usr2"`
+}
+
+// 信号对应的动作, 没有配置时使用默认的动作
+func (self SignalConfig) Actions(sig os.Signal) []string {
+	switch sig {
+	case syscall.SIGUSR1:
+		if len(self.Usr1) > 0 {
+			return self.Usr1
+		}
+	case syscall.SIGUSR2:
+		if len(self.Usr2) > 0 {
+			return self.Usr2
+		}
+	}
+	return defaultSignalActions[sig]
+}
+
+// 按顺序执行信号对应的动作, 一个动作失败不影响后面的动作
+// 返回每个动作的结果, 不认识的动作不会执行, 结果为ErrInvalidSignalAction
+func (self SignalConfig) Do(sig os.Signal, f func(action string) error) []error {
+	actions := self.Actions(sig)
+	result := make([]error, len(actions))
+	for i, action := range actions {
+		if _, ok := signalActions[action]; !ok {
+			result[i] = errors.ErrInvalidSignalAction
+		} else {
+			result[i] = f(action)
+		}
+	}
+	return result
 }
 
 type PprofConfig struct {
@@ -246,17 +374,20 @@ type AdminConfig struct {
 
 type Config struct {
 	Raw         []byte
-	Thread      int         `yaml:"thread"`
-	Env         string      `yaml:"env"`
-	Zone        string      `yaml:"zone"`
-	Log         *LogConfig  `yaml:"log"`
-	CoreLog     *LogConfig  `yaml:"core-log"`
-	BehaviorLog *LogConfig  `yaml:"behavior-log"`
-	Pprof       PprofConfig `yaml:"pprof"`
-	Sandbox     int         `yaml:"sandbox"`
+	Thread      int          `yaml:"thread"`
+	Env         string       `yaml:"env"`
+	Zone        string       `yaml:"zone"`
+	Log         *LogConfig   `yaml:"log"`
+	CoreLog     *LogConfig   `yaml:"core-log"`
+	BehaviorLog *LogConfig   `yaml:"behavior-log"`
+	Pprof       PprofConfig  `yaml:"pprof"`
+	Signal      SignalConfig `yaml:"signal"`
+	Sandbox     int          `yaml:"sandbox"`
 	Db          map[string]*DbConfig
 	Resource    ResourceConfig `yaml:"resource"`
-	Module      struct {
+	// 按任务名覆盖AddJob中的表达式, 可以直接生效, 删除后恢复AddJob中的表达式
+	Cron   map[string]string `yaml:"cron"`
+	Module struct {
 		Behavior   *BehaviorConfig   `yaml:"behavior"`
 		Http       *HttpConfig       `yaml:"http,omitempty"`
 		Etcd       *EtcdConfig       `yaml:"etcd"`
@@ -266,5 +397,6 @@ type Config struct {
 		Jwt        *JwtConfig        `yaml:"jwt"`
 		Gateway    *GatewayConfig    `yaml:"gateway"`
 		Admin      *AdminConfig      `yaml:"admin"`
+		Trace      *TraceConfig      `yaml:"trace"`
 	} `yaml:"module"`
 }
@@ -0,0 +1,83 @@
+package config
+
+///
+/// 比较两份配置, 用于重新加载配置
+///   - 按yaml路径比较, 用.分隔, 数组的下标也是路径的一部分, 例如 log.files.0.level
+///   - 数组长度不同时只返回数组的路径
+///   - 比较的是预处理后的yaml, 应用自定义的节也会出现在结果中
+///
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// 返回修改过的路径, 按字母排序
+func Diff(old []byte, new []byte) ([]string, error) {
+	var a, b interface{}
+	if err := yaml.Unmarshal(old, &a); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(new, &b); err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0)
+	diff(a, b, "", &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func diff(a interface{}, b interface{}, path string, paths *[]string) {
+	switch va := a.(type) {
+	case map[interface{}]interface{}:
+		if vb, ok := b.(map[interface{}]interface{}); ok {
+			keys := make(map[string]interface{})
+			for k := range va {
+				keys[fmt.Sprint(k)] = k
+			}
+			for k := range vb {
+				keys[fmt.Sprint(k)] = k
+			}
+			for name, k := range keys {
+				diff(va[k], vb[k], joinPath(path, name), paths)
+			}
+			return
+		}
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok && len(va) == len(vb) {
+			for i := range va {
+				diff(va[i], vb[i], joinPath(path, fmt.Sprint(i)), paths)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*paths = append(*paths, path)
+	}
+}
+
+func joinPath(path string, name string) string {
+	if len(path) <= 0 {
+		return name
+	}
+	return path + "." + name
+}
+
+// path是否在pattern的范围内, pattern中的*匹配任意一节
+// 例如 log.files.*.level 匹配 log.files.0.level, signal 匹配 signal.usr1.0
+func MatchPath(pattern string, path string) bool {
+	patterns := strings.Split(pattern, ".")
+	words := strings.Split(path, ".")
+	if len(words) < len(patterns) {
+		return false
+	}
+	for i, p := range patterns {
+		if p != "*" && p != words[i] {
+			return false
+		}
+	}
+	return true
+}
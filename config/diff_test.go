@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := []byte(`
+env: dev
+log:
+  level: info
+  files:
+    - level: info
+      path: a.log
+module:
+  gateway:
+    heartbeat: 30s
+`)
+	new := []byte(`
+env: dev
+log:
+  level: debug
+  files:
+    - level: debug
+      path: a.log
+module:
+  gateway:
+    heartbeat: 10s
+  behavior:
+    sync-interval: 10
+`)
+	paths, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"log.files.0.level", "log.level", "module.behavior", "module.gateway.heartbeat"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, paths)
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"log.files.*.level", "log.files.0.level", true},
+		{"log.files.*.level", "log.files", false},
+		{"signal", "signal.usr1.0", true},
+		{"log.level", "log.db-level", false},
+	}
+	for _, c := range cases {
+		if MatchPath(c.pattern, c.path) != c.match {
+			t.Fatalf("MatchPath(%s, %s) expected %v", c.pattern, c.path, c.match)
+		}
+	}
+}
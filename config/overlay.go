@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/Lyndon-Zhang/gira"
+	"gopkg.in/yaml.v2"
+)
+
+// 将配置中心的值覆盖到配置上
+// key为yaml路径, 用.分隔, 例如 module.behavior.sync-interval
+// 只覆盖配置文件中已经存在的节, 不会新建模块
+func Overlay(raw []byte, values map[string]string) (*gira.Config, error) {
+	root := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		words := strings.Split(key, ".")
+		node := root
+		for i := 0; i < len(words)-1 && node != nil; i++ {
+			child, _ := node[words[i]].(map[interface{}]interface{})
+			node = child
+		}
+		if node == nil {
+			continue
+		}
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+			v = value
+		}
+		node[words[len(words)-1]] = v
+	}
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	c := newDefaultConfig()
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	c.Raw = data
+	return c, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	raw := []byte(`
+env: dev
+log:
+  level: info
+module:
+  gateway:
+    bind: 0.0.0.0:8080
+    debug: false
+`)
+	c, err := Overlay(raw, map[string]string{
+		"log.level":            "debug",
+		"module.gateway.debug": "true",
+		"module.gateway.bind":  ":9090",
+		// 配置文件中没有的节不会新建
+		"module.admin.bind": ":9091",
+		"cron.daily":        "0 0 * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Env != "dev" {
+		t.Fatalf("expected env dev, got %s", c.Env)
+	}
+	if c.Log == nil || c.Log.Level != "debug" {
+		t.Fatalf("expected log level overlaid, got %v", c.Log)
+	}
+	if c.Module.Gateway == nil || !c.Module.Gateway.Debug || c.Module.Gateway.Bind != ":9090" {
+		t.Fatalf("expected gateway overlaid, got %v", c.Module.Gateway)
+	}
+	if c.Module.Admin != nil {
+		t.Fatalf("expected admin not created, got %v", c.Module.Admin)
+	}
+	if len(c.Cron) != 0 {
+		t.Fatalf("expected cron not created, got %v", c.Cron)
+	}
+	// 覆盖后的配置可以再次覆盖
+	if c, err := Overlay(c.Raw, map[string]string{"log.level": "warn"}); err != nil {
+		t.Fatal(err)
+	} else if c.Log.Level != "warn" || !c.Module.Gateway.Debug {
+		t.Fatalf("expected overlay on raw, got %v %v", c.Log, c.Module.Gateway)
+	}
+	if _, err := Overlay([]byte("env: [dev"), nil); err == nil {
+		t.Fatal("expected invalid yaml rejected")
+	}
+}
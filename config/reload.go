@@ -0,0 +1,62 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Lyndon-Zhang/gira"
+)
+
+// 可以直接生效的配置, *匹配任意一节
+var hotReloadPaths = []string{
+	"log.level",
+	"log.db-level",
+	"log.files.*.level",
+	"log.files.*.filter",
+	"core-log.level",
+	"core-log.db-level",
+	"core-log.files.*.level",
+	"core-log.files.*.filter",
+	"behavior-log.level",
+	"behavior-log.db-level",
+	"behavior-log.files.*.level",
+	"behavior-log.files.*.filter",
+	"signal",
+	"module.gateway.debug",
+	"module.gateway.recv-buff-size",
+	"module.gateway.recv-backlog",
+	"module.gateway.send-backlog",
+	"module.gateway.handshake-timeout",
+	"module.gateway.heartbeat",
+	"module.behavior.sync-interval",
+	"cron",
+	"cron.*",
+}
+
+// gira.Config中的节, 其他的都是应用自定义的
+var frameworkSections = func() map[string]struct{} {
+	sections := make(map[string]struct{})
+	typ := reflect.TypeOf(gira.Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if len(name) <= 0 {
+			name = strings.ToLower(field.Name)
+		}
+		sections[name] = struct{}{}
+	}
+	return sections
+}()
+
+// 修改后可以直接生效的路径, 应用自定义的节都可以直接生效
+func IsHotReloadPath(path string) bool {
+	if _, ok := frameworkSections[strings.Split(path, ".")[0]]; !ok {
+		return true
+	}
+	for _, pattern := range hotReloadPaths {
+		if MatchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestIsHotReloadPath(t *testing.T) {
+	tests := []struct {
+		path string
+		hot  bool
+	}{
+		{"log.level", true},
+		{"core-log.files.1.filter", true},
+		{"module.gateway.heartbeat", true},
+		{"cron", true},
+		{"cron.daily_reward", true},
+		{"game.max_level", true}, // 应用自定义的节
+		{"log.files.0.path", false},
+		{"module.gateway.bind", false},
+		{"module.grpc.address", false},
+		{"thread", false},
+	}
+	for _, tt := range tests {
+		if hot := IsHotReloadPath(tt.path); hot != tt.hot {
+			t.Errorf("%s: expected %v, got %v", tt.path, tt.hot, hot)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gira
+
+import (
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+func TestSignalActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   SignalConfig
+		sig      os.Signal
+		expected []string
+	}{
+		{"usr1 default", SignalConfig{}, syscall.SIGUSR1, []string{SIGNAL_ACTION_RELOAD_RESOURCE, SIGNAL_ACTION_REOPEN_LOG}},
+		{"usr2 default", SignalConfig{}, syscall.SIGUSR2, []string{SIGNAL_ACTION_DUMP_GOROUTINE, SIGNAL_ACTION_DUMP_HEAP}},
+		{"usr1 configured", SignalConfig{Usr1: []string{SIGNAL_ACTION_RELOAD_CONFIG}}, syscall.SIGUSR1, []string{SIGNAL_ACTION_RELOAD_CONFIG}},
+		{"usr2 configured", SignalConfig{Usr2: []string{SIGNAL_ACTION_TOGGLE_DEBUG}}, syscall.SIGUSR2, []string{SIGNAL_ACTION_TOGGLE_DEBUG}},
+		{"usr1 configured keeps usr2 default", SignalConfig{Usr1: []string{SIGNAL_ACTION_RELOAD_CONFIG}}, syscall.SIGUSR2, []string{SIGNAL_ACTION_DUMP_GOROUTINE, SIGNAL_ACTION_DUMP_HEAP}},
+		{"other signal", SignalConfig{Usr1: []string{SIGNAL_ACTION_RELOAD_CONFIG}}, syscall.SIGHUP, nil},
+	}
+	for _, tt := range tests {
+		if v := tt.config.Actions(tt.sig); !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, v)
+		}
+	}
+}
+
+// 不认识的动作和失败的动作不影响后面的动作
+func TestSignalDo(t *testing.T) {
+	config := SignalConfig{Usr1: []string{SIGNAL_ACTION_REOPEN_LOG, "reload-everything", SIGNAL_ACTION_RELOAD_CONFIG, SIGNAL_ACTION_TOGGLE_DEBUG}}
+	actionErr := errors.New("reload config fail")
+	done := make([]string, 0)
+	result := config.Do(syscall.SIGUSR1, func(action string) error {
+		done = append(done, action)
+		if action == SIGNAL_ACTION_RELOAD_CONFIG {
+			return actionErr
+		}
+		return nil
+	})
+	if expected := []string{SIGNAL_ACTION_REOPEN_LOG, SIGNAL_ACTION_RELOAD_CONFIG, SIGNAL_ACTION_TOGGLE_DEBUG}; !reflect.DeepEqual(done, expected) {
+		t.Fatalf("expected %v done, got %v", expected, done)
+	}
+	if expected := []error{nil, errors.ErrInvalidSignalAction, actionErr, nil}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
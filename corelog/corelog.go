@@ -1,12 +1,17 @@
 package corelog
 
 import (
+	"context"
+
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/logger"
 )
 
 var defaultLogger gira.Logger
 
+// 从ctx中提取日志字段, 例如trace_id
+var contextFields func(ctx context.Context) []interface{}
+
 func init() {
 	defaultLogger = logger.NewDefaultLogger()
 }
@@ -15,6 +20,23 @@ func GetDefaultLogger() gira.Logger {
 	return defaultLogger
 }
 
+// 重新打开日志文件
+func Rotate() error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.Rotate()
+	}
+	return nil
+}
+
+// 切换debug级别, 返回切换后的状态
+func ToggleDebug() bool {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		l.SetDebug(!l.IsDebug())
+		return l.IsDebug()
+	}
+	return false
+}
+
 func Config(config gira.LogConfig) error {
 	var err error
 	if defaultLogger, err = logger.NewConfigLogger(config); err != nil {
@@ -24,6 +46,21 @@ func Config(config gira.LogConfig) error {
 	}
 }
 
+// 修改日志级别, 不重新创建输出
+func CheckLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.CheckLevels(config)
+	}
+	return nil
+}
+
+func SetLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.SetLevels(config)
+	}
+	return nil
+}
+
 func Info(args ...interface{}) {
 	defaultLogger.Info(args...)
 }
@@ -88,3 +125,35 @@ func Fatalf(template string, args ...interface{}) {
 func Warnf(template string, args ...interface{}) {
 	defaultLogger.Warnf(template, args...)
 }
+
+// 设置从ctx中提取日志字段的函数
+func SetContextFields(f func(ctx context.Context) []interface{}) {
+	contextFields = f
+}
+
+func withContext(ctx context.Context, keysAndValues []interface{}) []interface{} {
+	if contextFields == nil {
+		return keysAndValues
+	}
+	if fields := contextFields(ctx); len(fields) > 0 {
+		return append(fields, keysAndValues...)
+	}
+	return keysAndValues
+}
+
+// 带上ctx中的字段, 例如trace_id
+func InfowContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	defaultLogger.Infow(msg, withContext(ctx, keysAndValues)...)
+}
+
+func DebugwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	defaultLogger.Debugw(msg, withContext(ctx, keysAndValues)...)
+}
+
+func WarnwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	defaultLogger.Warnw(msg, withContext(ctx, keysAndValues)...)
+}
+
+func ErrorwContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	defaultLogger.Errorw(msg, withContext(ctx, keysAndValues)...)
+}
@@ -1,5 +1,41 @@
 package gira
 
+import (
+	"context"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/options/cron_options"
+)
+
+type CronFunc func(ctx context.Context) error
+
+// 定时任务的状态
+type CronJob struct {
+	Id           int64
+	Name         string
+	Spec         string
+	Location     string
+	Singleton    bool
+	CatchUp      bool
+	Paused       bool
+	Running      bool
+	Next         time.Time // 下次执行的时间, 暂停时为零值
+	Prev         time.Time // 上次开始执行的时间
+	LastError    string
+	LastDuration time.Duration
+	RunCount     int64
+	FailCount    int64
+}
+
 type Cron interface {
+	// 添加匿名的任务
 	AddFunc(spec string, cmd func()) error
+	// 添加任务, 名字在进程内唯一, 返回任务id
+	AddJob(name string, spec string, cmd CronFunc, opt ...cron_options.AddOption) (int64, error)
+	// 删除任务, 正在执行的任务会收到ctx的取消
+	Remove(id int64) error
+	Pause(id int64) error
+	Resume(id int64) error
+	// 按id排序
+	List() []*CronJob
 }
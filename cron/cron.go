@@ -0,0 +1,476 @@
+package cron
+
+///
+/// 定时任务
+///
+/// 表达式使用 github.com/robfig/cron 的格式, 6个字段, 第一个是秒, 也支持 @every 1m, @daily 等
+/// 表达式前面可以加上时区, 例如 TZ=Asia/Shanghai 0 0 5 * * *
+/// 每个任务在自己的协程中调度, 上一次没有执行完时跳过本次
+///   - singleton: 执行前通过Locker加锁, 同类型的节点中只有一个执行
+///   - catch up: 启动时如果错过了执行时间, 马上补执行一次, 上次执行的时间保存在Store中
+/// SetSpecs可以按任务名覆盖表达式, 运行中修改时马上按新的表达式重新调度
+///
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/options/cron_options"
+	robfig "github.com/robfig/cron"
+)
+
+// 分布式锁
+type Locker interface {
+	// 锁被其他节点持有时返回false
+	TryLock(name string) (bool, error)
+}
+
+// 保存任务上次执行的时间
+type Store interface {
+	Load(name string) (time.Time, bool)
+	Save(name string, t time.Time) error
+}
+
+type job struct {
+	id         int64
+	name       string
+	rawSpec    string // AddJob中的表达式
+	options    cron_options.AddOptions
+	cmd        gira.CronFunc
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	wake       chan struct{}
+
+	mu           sync.Mutex
+	spec         string
+	schedule     robfig.Schedule
+	location     *time.Location
+	paused       bool
+	running      bool
+	next         time.Time
+	prev         time.Time
+	lastError    string
+	lastDuration time.Duration
+	runCount     int64
+	failCount    int64
+}
+
+type Cron struct {
+	mu         sync.Mutex
+	seq        int64
+	jobs       map[int64]*job
+	names      map[string]*job
+	specs      map[string]string // 覆盖的表达式
+	location   *time.Location
+	locker     Locker
+	store      Store
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	started    bool
+	wg         sync.WaitGroup
+}
+
+func New(location *time.Location) *Cron {
+	if location == nil {
+		location = time.Local
+	}
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	return &Cron{
+		jobs:       make(map[int64]*job),
+		names:      make(map[string]*job),
+		location:   location,
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+	}
+}
+
+// 在Start之前设置
+func (c *Cron) SetLocker(locker Locker) {
+	c.locker = locker
+}
+
+// 在Start之前设置
+func (c *Cron) SetStore(store Store) {
+	c.store = store
+}
+
+func (c *Cron) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return
+	}
+	c.started = true
+	for _, j := range c.jobs {
+		c.launch(j)
+	}
+}
+
+// 停止调度, 等待正在执行的任务返回
+func (c *Cron) Stop(ctx context.Context) error {
+	c.cancelFunc()
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 匿名的任务按id命名
+func (c *Cron) AddFunc(spec string, cmd func()) error {
+	_, err := c.add("", spec, func(ctx context.Context) error {
+		cmd()
+		return nil
+	}, cron_options.AddOptions{})
+	return err
+}
+
+func (c *Cron) AddJob(name string, spec string, cmd gira.CronFunc, opt ...cron_options.AddOption) (int64, error) {
+	opts := cron_options.AddOptions{}
+	for _, v := range opt {
+		v.ConfigAddOption(&opts)
+	}
+	if len(name) <= 0 || (opts.Singleton && strings.Contains(name, "/")) {
+		return 0, errors.ErrInvalidArgs
+	}
+	return c.add(name, spec, cmd, opts)
+}
+
+// 解析表达式, 时区的优先级: 表达式中的TZ, 选项中的时区, 默认时区
+func (c *Cron) parse(spec string, opts cron_options.AddOptions) (robfig.Schedule, *time.Location, error) {
+	schedule, location, err := ParseSpec(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if location == nil {
+		location = opts.Location
+	}
+	if location == nil {
+		location = c.location
+	}
+	return schedule, location, nil
+}
+
+func (c *Cron) add(name string, rawSpec string, cmd gira.CronFunc, opts cron_options.AddOptions) (int64, error) {
+	if _, _, err := c.parse(rawSpec, opts); err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(name) <= 0 {
+		name = fmt.Sprintf("func_%d", c.seq+1)
+	}
+	if _, ok := c.names[name]; ok {
+		return 0, errors.ErrCronJobDuplicate
+	}
+	spec := rawSpec
+	if v, ok := c.specs[name]; ok {
+		spec = v
+	}
+	schedule, location, err := c.parse(spec, opts)
+	if err != nil {
+		return 0, err
+	}
+	c.seq++
+	j := &job{
+		id:       c.seq,
+		name:     name,
+		rawSpec:  rawSpec,
+		spec:     spec,
+		schedule: schedule,
+		location: location,
+		options:  opts,
+		cmd:      cmd,
+		wake:     make(chan struct{}, 1),
+	}
+	j.ctx, j.cancelFunc = context.WithCancel(c.ctx)
+	c.jobs[j.id] = j
+	c.names[j.name] = j
+	if c.started {
+		c.launch(j)
+	}
+	return j.id, nil
+}
+
+func (c *Cron) Remove(id int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[id]
+	if !ok {
+		return errors.ErrCronJobNotFound
+	}
+	delete(c.jobs, id)
+	delete(c.names, j.name)
+	j.cancelFunc()
+	return nil
+}
+
+// 检查表达式是否正确
+func CheckSpecs(specs map[string]string) error {
+	for name, spec := range specs {
+		if _, _, err := ParseSpec(spec); err != nil {
+			return fmt.Errorf("cron %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// 按任务名覆盖表达式, 之后添加的任务也会使用, 不在specs中的任务恢复AddJob中的表达式
+// 有一个表达式错误时全部不修改
+func (c *Cron) SetSpecs(specs map[string]string) error {
+	if err := CheckSpecs(specs); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.specs = make(map[string]string, len(specs))
+	for name, spec := range specs {
+		c.specs[name] = spec
+	}
+	for _, j := range c.jobs {
+		spec := j.rawSpec
+		if v, ok := c.specs[j.name]; ok {
+			spec = v
+		}
+		schedule, location, err := c.parse(spec, j.options)
+		if err != nil {
+			// AddJob中的表达式已经检查过
+			return err
+		}
+		j.mu.Lock()
+		changed := j.spec != spec
+		j.spec = spec
+		j.schedule = schedule
+		j.location = location
+		j.mu.Unlock()
+		if changed {
+			log.Infow("cron job reschedule", "name", j.name, "spec", spec)
+			j.notify()
+		}
+	}
+	return nil
+}
+
+func (c *Cron) Pause(id int64) error {
+	return c.setPaused(id, true)
+}
+
+func (c *Cron) Resume(id int64) error {
+	return c.setPaused(id, false)
+}
+
+func (c *Cron) setPaused(id int64, paused bool) error {
+	c.mu.Lock()
+	j, ok := c.jobs[id]
+	c.mu.Unlock()
+	if !ok {
+		return errors.ErrCronJobNotFound
+	}
+	j.mu.Lock()
+	j.paused = paused
+	j.mu.Unlock()
+	j.notify()
+	return nil
+}
+
+func (c *Cron) List() []*gira.CronJob {
+	c.mu.Lock()
+	jobs := make([]*gira.CronJob, 0, len(c.jobs))
+	for _, j := range c.jobs {
+		jobs = append(jobs, j.stat())
+	}
+	c.mu.Unlock()
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Id < jobs[k].Id
+	})
+	return jobs
+}
+
+func (c *Cron) launch(j *job) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.schedule(j)
+	}()
+}
+
+func (c *Cron) schedule(j *job) {
+	if j.options.CatchUp && c.missed(j) {
+		log.Infow("cron job catch up", "name", j.name)
+		c.run(j)
+	}
+	for {
+		next, now := j.nextTime()
+		// 暂停或者没有下次执行的时间, 只等待唤醒
+		if next.IsZero() {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-j.wake:
+				continue
+			}
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-j.ctx.Done():
+			timer.Stop()
+			return
+		case <-j.wake:
+			timer.Stop()
+		case <-timer.C:
+			if !j.isPaused() {
+				c.run(j)
+			}
+		}
+	}
+}
+
+// 上次执行之后到现在是否错过了执行时间, 没有记录时从现在开始记录
+func (c *Cron) missed(j *job) bool {
+	if c.store == nil {
+		return false
+	}
+	j.mu.Lock()
+	schedule, location := j.schedule, j.location
+	j.mu.Unlock()
+	now := time.Now().In(location)
+	last, ok := c.store.Load(j.name)
+	if !ok {
+		if err := c.store.Save(j.name, now); err != nil {
+			log.Warnw("cron store save fail", "name", j.name, "error", err)
+		}
+		return false
+	}
+	next := schedule.Next(last.In(location))
+	return !next.IsZero() && next.Before(now)
+}
+
+func (c *Cron) run(j *job) {
+	if j.options.Singleton && c.locker != nil {
+		if ok, err := c.locker.TryLock(j.name); err != nil {
+			log.Warnw("cron job lock fail", "name", j.name, "error", err)
+			return
+		} else if !ok {
+			log.Debugw("cron job locked by other peer", "name", j.name)
+			return
+		}
+	}
+	start := time.Now()
+	j.mu.Lock()
+	j.running = true
+	j.prev = start
+	j.mu.Unlock()
+	err := j.call()
+	duration := time.Since(start)
+	j.mu.Lock()
+	j.running = false
+	j.lastDuration = duration
+	j.runCount++
+	if err != nil {
+		j.failCount++
+		j.lastError = err.Error()
+	}
+	j.mu.Unlock()
+	if err != nil {
+		log.Errorw("cron job fail", "name", j.name, "error", err, "duration", duration)
+	}
+	if j.options.CatchUp && c.store != nil {
+		if err := c.store.Save(j.name, start); err != nil {
+			log.Warnw("cron store save fail", "name", j.name, "error", err)
+		}
+	}
+}
+
+// 捕获panic, 打印堆栈后转成错误
+func (j *job) call() (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Errorw("cron job panic", "name", j.name, "error", e, "stack", string(debug.Stack()))
+			err = fmt.Errorf("%w: %v", errors.ErrCronJobPanic, e)
+		}
+	}()
+	return j.cmd(j.ctx)
+}
+
+// 唤醒调度协程, 重新计算下次执行的时间
+func (j *job) notify() {
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+}
+
+// 计算并记录下次执行的时间, 暂停时为零值
+func (j *job) nextTime() (next time.Time, now time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now = time.Now().In(j.location)
+	if !j.paused {
+		next = j.schedule.Next(now)
+	}
+	j.next = next
+	return
+}
+
+func (j *job) isPaused() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.paused
+}
+
+func (j *job) stat() *gira.CronJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return &gira.CronJob{
+		Id:           j.id,
+		Name:         j.name,
+		Spec:         j.spec,
+		Location:     j.location.String(),
+		Singleton:    j.options.Singleton,
+		CatchUp:      j.options.CatchUp,
+		Paused:       j.paused,
+		Running:      j.running,
+		Next:         j.next,
+		Prev:         j.prev,
+		LastError:    j.lastError,
+		LastDuration: j.lastDuration,
+		RunCount:     j.runCount,
+		FailCount:    j.failCount,
+	}
+}
+
+// 解析表达式, 支持 TZ= 和 CRON_TZ= 前缀指定时区, 没有指定时返回nil
+func ParseSpec(spec string) (robfig.Schedule, *time.Location, error) {
+	var location *time.Location
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil, nil, fmt.Errorf("invalid cron spec: %s", spec)
+		}
+		loc, err := time.LoadLocation(spec[strings.Index(spec, "=")+1 : i])
+		if err != nil {
+			return nil, nil, err
+		}
+		location = loc
+		spec = strings.TrimSpace(spec[i:])
+	}
+	schedule, err := robfig.Parse(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, location, nil
+}
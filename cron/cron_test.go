@@ -0,0 +1,120 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/options/cron_options"
+)
+
+type test_store struct {
+	times map[string]time.Time
+}
+
+func (s *test_store) Load(name string) (time.Time, bool) {
+	t, ok := s.times[name]
+	return t, ok
+}
+
+func (s *test_store) Save(name string, t time.Time) error {
+	s.times[name] = t
+	return nil
+}
+
+func TestParseSpec(t *testing.T) {
+	schedule, location, err := ParseSpec("TZ=Asia/Shanghai 0 0 5 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.String() != "Asia/Shanghai" {
+		t.Fatalf("expected Asia/Shanghai, got %v", location)
+	}
+	next := schedule.Next(time.Date(2023, 7, 1, 4, 0, 0, 0, location))
+	if next.Hour() != 5 || next.Day() != 1 {
+		t.Fatalf("unexpected next %v", next)
+	}
+	if _, location, err = ParseSpec("@every 1m"); err != nil || location != nil {
+		t.Fatalf("unexpected %v %v", location, err)
+	}
+	if _, _, err = ParseSpec("TZ=Nowhere/City 0 0 5 * * *"); err == nil {
+		t.Fatal("expected invalid location")
+	}
+}
+
+func TestCatchUpAndPanic(t *testing.T) {
+	c := New(time.UTC)
+	c.SetStore(&test_store{times: map[string]time.Time{
+		"daily": time.Now().Add(-48 * time.Hour),
+	}})
+	var runs int32
+	id, err := c.AddJob("daily", "@daily", func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		panic("test panic")
+	}, cron_options.WithCatchUpOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddJob("daily", "@daily", nil); !errors.Is(err, errors.ErrCronJobDuplicate) {
+		t.Fatalf("expected duplicate, got %v", err)
+	}
+	c.Start()
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&runs) < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := c.Pause(id); err != nil {
+		t.Fatal(err)
+	}
+	for time.Now().Before(deadline) {
+		if jobs := c.List(); len(jobs) == 1 && jobs[0].RunCount == 1 && jobs[0].Next.IsZero() {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	jobs := c.List()
+	if len(jobs) != 1 || jobs[0].RunCount != 1 || jobs[0].FailCount != 1 || !jobs[0].Paused || !jobs[0].Next.IsZero() {
+		t.Fatalf("unexpected job %+v", jobs)
+	}
+	if err := c.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Remove(id); !errors.Is(err, errors.ErrCronJobNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetSpecs(t *testing.T) {
+	c := New(time.UTC)
+	if _, err := c.AddJob("daily", "0 4 * * *", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	spec := func() string {
+		return c.List()[0].Spec
+	}
+	if err := c.SetSpecs(map[string]string{"daily": "30 5 * * *"}); err != nil {
+		t.Fatal(err)
+	}
+	if spec() != "30 5 * * *" {
+		t.Fatalf("expected spec overridden, got %s", spec())
+	}
+	// 有错误的表达式时全部不修改
+	if err := c.SetSpecs(map[string]string{"daily": "0 6 * * *", "weekly": "bad"}); err == nil {
+		t.Fatal("expected invalid spec")
+	}
+	if spec() != "30 5 * * *" {
+		t.Fatalf("expected spec unchanged, got %s", spec())
+	}
+	// 去掉覆盖后恢复AddJob中的表达式
+	if err := c.SetSpecs(nil); err != nil {
+		t.Fatal(err)
+	}
+	if spec() != "0 4 * * *" {
+		t.Fatalf("expected spec restored, got %s", spec())
+	}
+}
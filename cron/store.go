@@ -0,0 +1,62 @@
+package cron
+
+///
+/// 把任务上次执行的时间保存在本地的json文件中
+///
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type FileStore struct {
+	mu       sync.Mutex
+	filePath string
+	times    map[string]int64
+}
+
+// 文件不存在时从空的记录开始
+func NewFileStore(filePath string) (*FileStore, error) {
+	s := &FileStore{
+		filePath: filePath,
+		times:    make(map[string]int64),
+	}
+	if data, err := os.ReadFile(filePath); err == nil {
+		if err := json.Unmarshal(data, &s.times); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) Load(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.times[name]; ok {
+		return time.Unix(v, 0), true
+	}
+	return time.Time{}, false
+}
+
+// 先写临时文件再改名
+func (s *FileStore) Save(name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.times[name] = t.Unix()
+	data, err := json.Marshal(s.times)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return err
+	}
+	tmpFilePath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpFilePath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, s.filePath)
+}
@@ -45,7 +45,26 @@ type MongoClient interface {
 	GetMongoDatabase() *mongo.Database
 }
 
+// 分片选择, 返回[0, n)的序号
+type DbShardSelector func(key string, n int) int
+
+// 分片组, 配置中按 <组名>_<序号> 命名的数据库, 例如 gamedb_0, gamedb_1
+type DbShardGroup interface {
+	Name() string
+	Len() int
+	// 按序号返回分片, 超出范围时返回nil
+	Get(index int) DbClient
+	// 按key选择分片
+	Select(key string) DbClient
+	// 修改分片选择, 在OnCreate中设置
+	SetSelector(selector DbShardSelector)
+}
+
 type DbClientComponent interface {
+	// 按配置中的名字返回, 没有配置时返回nil
+	GetDbClient(name string) DbClient
+	// 按组名返回分片组, 没有配置时返回nil
+	GetDbShardGroup(name string) DbShardGroup
 	GetGameDbClient() DbClient
 	GetStatDbClient() DbClient
 	GetAccountDbClient() DbClient
@@ -0,0 +1,113 @@
+package db
+
+///
+/// 数据库分片
+///
+/// 配置中按 <组名>_<序号> 命名的数据库组成一个分片组, 序号从0开始连续, 例如 gamedb_0, gamedb_1
+/// 默认按key的crc32取模选择分片, 分片数量修改后需要迁移数据
+///
+import (
+	"hash/crc32"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+)
+
+var shardNameRegexp = regexp.MustCompile(`^(.+)_(\d+)$`)
+
+// 默认的分片选择
+func DefaultShardSelector(key string, n int) int {
+	return int(crc32.ChecksumIEEE([]byte(key)) % uint32(n))
+}
+
+type ShardGroup struct {
+	name     string
+	clients  []gira.DbClient
+	selector gira.DbShardSelector
+}
+
+func NewShardGroup(name string, clients []gira.DbClient) *ShardGroup {
+	return &ShardGroup{
+		name:     name,
+		clients:  clients,
+		selector: DefaultShardSelector,
+	}
+}
+
+func (self *ShardGroup) Name() string {
+	return self.name
+}
+
+func (self *ShardGroup) Len() int {
+	return len(self.clients)
+}
+
+func (self *ShardGroup) Get(index int) gira.DbClient {
+	if index < 0 || index >= len(self.clients) {
+		return nil
+	}
+	return self.clients[index]
+}
+
+func (self *ShardGroup) Select(key string) gira.DbClient {
+	return self.Get(self.selector(key, len(self.clients)))
+}
+
+func (self *ShardGroup) SetSelector(selector gira.DbShardSelector) {
+	self.selector = selector
+}
+
+// 解析分片的名字, 例如 gamedb_1 返回 gamedb, 1
+func ParseShardName(name string) (group string, index int, ok bool) {
+	matches := shardNameRegexp.FindStringSubmatch(name)
+	if matches == nil {
+		return "", 0, false
+	}
+	index, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return matches[1], index, true
+}
+
+// 按名字把数据库分组, 序号不连续或者重复(例如 gamedb_01 和 gamedb_1)的不组成分片组
+func NewShardGroups(clients map[string]gira.DbClient) map[string]*ShardGroup {
+	shards := make(map[string]map[int]gira.DbClient)
+	duplicates := make(map[string]bool)
+	for name, client := range clients {
+		if group, index, ok := ParseShardName(name); ok {
+			if _, ok := shards[group]; !ok {
+				shards[group] = make(map[int]gira.DbClient)
+			}
+			if _, ok := shards[group][index]; ok {
+				duplicates[group] = true
+			}
+			shards[group][index] = client
+		}
+	}
+	groups := make(map[string]*ShardGroup)
+	for group, dict := range shards {
+		if duplicates[group] {
+			log.Warnw("db shard index duplicate", "group", group)
+			continue
+		}
+		indexes := make([]int, 0, len(dict))
+		for index := range dict {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		if indexes[len(indexes)-1] != len(indexes)-1 {
+			log.Warnw("db shard index not continuous", "group", group, "indexes", indexes)
+			continue
+		}
+		arr := make([]gira.DbClient, len(indexes))
+		for _, index := range indexes {
+			arr[index] = dict[index]
+		}
+		groups[group] = NewShardGroup(group, arr)
+	}
+	return groups
+}
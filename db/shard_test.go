@@ -0,0 +1,56 @@
+package db
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+)
+
+type test_client struct {
+	uri string
+}
+
+func (c *test_client) Uri() string {
+	return c.uri
+}
+
+func TestNewShardGroups(t *testing.T) {
+	clients := map[string]gira.DbClient{
+		"gamedb_0": &test_client{uri: "0"},
+		"gamedb_1": &test_client{uri: "1"},
+		"gamedb_2": &test_client{uri: "2"},
+		"logdb_1":  &test_client{uri: "1"},
+		// 序号重复, 不组成分片组
+		"userdb_0":  &test_client{uri: "0"},
+		"userdb_1":  &test_client{uri: "1"},
+		"userdb_01": &test_client{uri: "01"},
+		"statdb":    &test_client{uri: "stat"},
+	}
+	groups := NewShardGroups(clients)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	group, ok := groups["gamedb"]
+	if !ok || group.Len() != 3 {
+		t.Fatalf("expected gamedb with 3 shards, got %v", groups)
+	}
+	for i := 0; i < group.Len(); i++ {
+		if group.Get(i) != clients[shardName("gamedb", i)] {
+			t.Fatalf("shard %d not match", i)
+		}
+	}
+	if group.Select("user_1") != group.Select("user_1") {
+		t.Fatal("select not stable")
+	}
+	group.SetSelector(func(key string, n int) int {
+		return n - 1
+	})
+	if group.Select("user_1").Uri() != "2" {
+		t.Fatal("selector not used")
+	}
+}
+
+func shardName(group string, index int) string {
+	return group + "_" + strconv.Itoa(index)
+}
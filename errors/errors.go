@@ -25,19 +25,50 @@ var (
 	ErrDbClientComponentNotImplement      = New("db client component not implement")
 	ErrServerRouterHandlerNotImplement    = New("cata server handler not implement")
 	ErrCronNotImplement                   = New("cron not implement")
+	ErrCronJobNotFound                    = New("cron job not found")
+	ErrCronJobDuplicate                   = New("cron job duplicate")
+	ErrCronJobPanic                       = New("cron job panic")
+	ErrTimerHandlerNotFound               = New("timer handler not found")
 	ErrAdminClientNotImplement            = New("admin client 接口末实现")
 	ErrRegistryNOtImplement               = New("注册表功能未实现")
 	ErrServiceContainerNotImplement       = New("service container 未实现")
 	ErrSdkPayOrderCheckMethodNotImplement = New("sdk pay order check 方法未实现")
 	ErrServiceNotImplement                = New("service接口未实现")
+	ErrServicePanic                       = New("service panic")
+	ErrServiceStopTimeout                 = New("service stop timeout")
 	ErrDataNotExist                       = New("data not exist")
 	ErrDataNotFound                       = New("data not found")
 	ErrDataInsertFail                     = New("data insert fail")
 	ErrDataDeleteFail                     = New("data delete fail")
 	ErrPeerNotFound                       = New("peer not found")
+	ErrZoneNotFound                       = New("zone not found")
+	ErrConfigHistoryNotFound              = New("config history not found")
+	ErrConfigHistoryMismatch              = New("config history mismatch")
 	ErrUserInstead                        = New("账号在其他地方登录")
 	ErrUserLocked                         = New("账号在其他地方被锁定")
 	ErrGrpcClientPoolNil                  = New("grpc pool无法申请client")
+	ErrGrpcConnManagerNotImplement        = New("grpc conn manager not implement")
+	ErrRpcTimeout                         = New("rpc timeout")
+	ErrRpcCanceled                        = New("rpc canceled")
+	ErrRpcUnavailable                     = New("rpc unavailable")
+	ErrCircuitOpen                        = New("circuit breaker open")
+	ErrTlsCertificateRequired             = New("tls certificate required")
+	ErrTlsPeerNotRegistered               = New("tls peer not registered")
+	ErrTlsPeerMismatch                    = New("tls peer mismatch")
+	ErrQuorumNotReached                   = New("quorum not reached")
+	ErrConfigRestartRequired              = New("config change requires restart")
+	ErrLogLevelFieldChanged               = New("log level field changed, restart required")
+	ErrInvalidLogLevel                    = New("invalid log level")
+	ErrInvalidSignalAction                = New("invalid signal action")
+	ErrGracefulRestartFail                = New("graceful restart fail")
+	ErrGracefulRestarting                 = New("graceful restart in progress")
+	ErrProcessAlreadyRunning              = New("process already running")
+	ErrProcessNotRunning                  = New("process not running")
+	ErrProcessStopTimeout                 = New("process stop timeout")
+	ErrComponentDuplicate                 = New("component duplicate")
+	ErrComponentDependencyNotFound        = New("component dependency not found")
+	ErrComponentDependencyCycle           = New("component dependency cycle")
+	ErrComponentStopTimeout               = New("component stop timeout")
 	ErrBrokenChannel                      = New("管道已关闭，不能再写数据")
 	ErrSessionClosed                      = New("会话已经关闭")
 	ErrUpstreamUnavailable                = New("上游服务不可用")
@@ -50,11 +81,15 @@ var (
 	ErrInvalidMemberId                    = New("member id非法")
 	ErrBehaviorNotInit                    = New("behavior driver not init")
 	ErrDbNotSupport                       = New("数据库类型不支持")
+	ErrDbClientNotFound                   = New("数据库没有配置")
 	ErrInvalidService                     = New("service格式非法")
 	ErrServiceNotFound                    = New("查找不到service")
 	ErrServiceLocked                      = New("注册service失败")
 	ErrUserNotFound                       = New("用户不在线")
 	ErrActorCallTimeOut                   = New("actor call timeout")
+	ErrActorStopped                       = New("actor stopped")
+	ErrActorMailboxFull                   = New("actor mailbox full")
+	ErrActorPanic                         = New("actor panic")
 	ErrInterrupt                          = New("interrupt")
 	ErrServerRouterMetaNotFound           = New("server router incoming context not found")
 	ErrServerRouterKeyNotFound            = New("server router-key not found")
@@ -180,6 +215,29 @@ func (e *Error) TraceWithSkip(skip int, values ...interface{}) *TraceError {
 	return err
 }
 
+// 远程调用错误, 带上出错的节点
+// errors.Is可以匹配映射后的错误, 例如ErrRpcTimeout, Unwrap返回原始的错误
+type RpcError struct {
+	Peer  string
+	Err   error // 映射后的错误
+	Cause error // 原始错误
+}
+
+func (e *RpcError) Error() string {
+	if e.Err == e.Cause {
+		return fmt.Sprintf("%s, peer: %s", e.Cause.Error(), e.Peer)
+	}
+	return fmt.Sprintf("%s, peer: %s, cause: %s", e.Err.Error(), e.Peer, e.Cause.Error())
+}
+
+func (e *RpcError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *RpcError) Is(err error) bool {
+	return e == err || e.Err == err
+}
+
 // 保存发生错误时的调用栈
 type TraceError struct {
 	err    error
@@ -3,10 +3,14 @@ package facade
 import (
 	"context"
 	"path"
+	"strconv"
+	"time"
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/options/cron_options"
 	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"google.golang.org/grpc"
 )
 
 // 返回app配置
@@ -142,6 +146,31 @@ func ReloadResource() error {
 	}
 }
 
+// 重新加载配置文件, 有需要重启的修改时返回ErrConfigRestartRequired
+func ReloadConfig() (*gira.ConfigReloadReport, error) {
+	return gira.GetRuntime().ReloadConfig()
+}
+
+// 零停机重启, 返回新进程的pid
+func Restart() (int, error) {
+	return gira.GetRuntime().Restart()
+}
+
+// 是否已经就绪
+func IsReady() bool {
+	return gira.GetRuntime().IsReady()
+}
+
+// 增加/readyz的就绪检查
+func AddReadinessCheck(name string, check gira.ReadinessCheckFunc) {
+	gira.GetRuntime().AddReadinessCheck(name, check)
+}
+
+// 按名字查找运行时组件
+func GetComponent(name string) gira.RuntimeComponent {
+	return gira.GetRuntime().GetComponent(name)
+}
+
 // 广播重载配置
 func BroadcastReloadResource(ctx context.Context, name string) (result gira.BroadcastReloadResourceResult, err error) {
 	application := gira.GetRuntime()
@@ -187,6 +216,97 @@ func WhereIsUser(userId string) (*gira.Peer, error) {
 	}
 }
 
+// 玩家位置缓存统计
+func GetUserCacheStats() gira.UserCacheStats {
+	application := gira.GetRuntime()
+	if r := application.GetRegistry(); r != nil {
+		return r.UserCacheStats()
+	} else if r := application.GetRegistryClient(); r != nil {
+		return r.UserCacheStats()
+	} else {
+		return gira.UserCacheStats{}
+	}
+}
+
+// 返回覆盖了配置中心的值后的配置, 没有注册表时返回app配置
+func GetEffectiveConfig() *gira.Config {
+	application := gira.GetRuntime()
+	if r := application.GetRegistry(); r != nil {
+		return r.GetEffectiveConfig()
+	} else {
+		return application.GetConfig()
+	}
+}
+
+// 侦听配置中心的变化
+func WatchConfig(prefix string, f func(change *gira.ConfigChange)) error {
+	application := gira.GetRuntime()
+	if r := application.GetRegistry(); r != nil {
+		r.WatchConfig(prefix, f)
+		return nil
+	} else {
+		return errors.ErrRegistryNOtImplement
+	}
+}
+
+// 配置中心的值
+func GetConfigValue(key string) (string, bool) {
+	application := gira.GetRuntime()
+	if r := application.GetRegistry(); r != nil {
+		return r.GetConfigValue(key)
+	} else {
+		return "", false
+	}
+}
+
+func GetConfigString(key string, def string) string {
+	if v, ok := GetConfigValue(key); ok {
+		return v
+	}
+	return def
+}
+
+func GetConfigInt(key string, def int64) int64 {
+	if v, ok := GetConfigValue(key); !ok {
+		return def
+	} else if i, err := strconv.ParseInt(v, 10, 64); err != nil {
+		return def
+	} else {
+		return i
+	}
+}
+
+func GetConfigBool(key string, def bool) bool {
+	if v, ok := GetConfigValue(key); !ok {
+		return def
+	} else if b, err := strconv.ParseBool(v); err != nil {
+		return def
+	} else {
+		return b
+	}
+}
+
+func GetConfigFloat(key string, def float64) float64 {
+	if v, ok := GetConfigValue(key); !ok {
+		return def
+	} else if f, err := strconv.ParseFloat(v, 64); err != nil {
+		return def
+	} else {
+		return f
+	}
+}
+
+// 值的格式为time.ParseDuration支持的格式, 例如 10s
+func GetConfigDuration(key string, def time.Duration) time.Duration {
+	if v, ok := GetConfigValue(key); !ok {
+		return def
+	} else if d, err := time.ParseDuration(v); err != nil {
+		return def
+	} else {
+		return d
+	}
+}
+
 func ListLocalUser() []string {
 	application := gira.GetRuntime()
 	if r := application.GetRegistry(); r != nil {
@@ -303,6 +423,65 @@ func UnregisterPeer(appFullName string) error {
 }
 
 // ================= db client component =============================
+// 按配置中的名字返回db client, 没有配置时返回nil
+func GetDbClient(name string) gira.DbClient {
+	application := gira.GetRuntime()
+	if c, ok := application.(gira.DbClientComponent); ok {
+		return c.GetDbClient(name)
+	} else {
+		return nil
+	}
+}
+
+// 按组名返回分片组, 例如配置了gamedb_0, gamedb_1时组名为gamedb
+func GetDbShardGroup(name string) gira.DbShardGroup {
+	application := gira.GetRuntime()
+	if c, ok := application.(gira.DbClientComponent); ok {
+		return c.GetDbShardGroup(name)
+	} else {
+		return nil
+	}
+}
+
+// 按名字返回mongo client, 类型不对时返回ErrDbNotSupport
+func GetMongoClient(name string) (gira.MongoClient, error) {
+	client := GetDbClient(name)
+	if client == nil {
+		return nil, errors.ErrDbClientNotFound
+	}
+	if c, ok := client.(gira.MongoClient); ok {
+		return c, nil
+	} else {
+		return nil, errors.ErrDbNotSupport
+	}
+}
+
+// 按名字返回redis client, 类型不对时返回ErrDbNotSupport
+func GetRedisClient(name string) (gira.RedisClient, error) {
+	client := GetDbClient(name)
+	if client == nil {
+		return nil, errors.ErrDbClientNotFound
+	}
+	if c, ok := client.(gira.RedisClient); ok {
+		return c, nil
+	} else {
+		return nil, errors.ErrDbNotSupport
+	}
+}
+
+// 按名字返回mysql client, 类型不对时返回ErrDbNotSupport
+func GetMysqlClient(name string) (gira.MysqlClient, error) {
+	client := GetDbClient(name)
+	if client == nil {
+		return nil, errors.ErrDbClientNotFound
+	}
+	if c, ok := client.(gira.MysqlClient); ok {
+		return c, nil
+	} else {
+		return nil, errors.ErrDbNotSupport
+	}
+}
+
 // 返回预定义的admindb client
 func GetAdminDbClient() gira.DbClient {
 	application := gira.GetRuntime()
@@ -418,6 +597,44 @@ func IsEnableResolver() bool {
 	}
 }
 
+// 返回地址对应的grpc连接, 由runtime管理, 不需要关闭
+func GetGrpcConn(address string) (*grpc.ClientConn, error) {
+	application := gira.GetRuntime()
+	if m := application.GetGrpcConnManager(); m == nil {
+		return nil, errors.ErrGrpcConnManagerNotImplement
+	} else {
+		return m.GetConn(address)
+	}
+}
+
+// grpc连接统计
+func GetGrpcConnStats() gira.GrpcConnStats {
+	application := gira.GetRuntime()
+	if m := application.GetGrpcConnManager(); m == nil {
+		return gira.GrpcConnStats{}
+	} else {
+		return m.Stats()
+	}
+}
+
+// grpc方法统计
+func GetGrpcMethodStats() []gira.GrpcMethodStat {
+	application := gira.GetRuntime()
+	if s := application.GetGrpcServer(); s == nil {
+		return nil
+	} else {
+		return s.MethodStats()
+	}
+}
+
+// 设置grpc.health.v1中服务的状态, 例如依赖的资源不可用时设置为false
+func SetGrpcServiceStatus(serviceName string, serving bool) {
+	application := gira.GetRuntime()
+	if s := application.GetGrpcServer(); s != nil {
+		s.SetServiceStatus(serviceName, serving)
+	}
+}
+
 // 查看grpc server
 func WhereIsServer(name string) (svr interface{}, ok bool) {
 	application := gira.GetRuntime()
@@ -460,19 +677,28 @@ func StopService(service gira.Service) error {
 	}
 }
 
-// 启动服务
-func StartService(name string, service gira.Service) error {
+// 启动服务, 可以设置重启策略
+func StartService(name string, service gira.Service, opt ...service_options.StartOption) error {
 	application := gira.GetRuntime()
 	if s := application.GetServiceContainer(); s == nil {
 		return errors.ErrServiceContainerNotImplement
 	} else {
-		return s.StartService(name, service)
+		return s.StartService(name, service, opt...)
+	}
+}
+
+// 全部服务的状态
+func ServiceStates() ([]*gira.ServiceState, error) {
+	application := gira.GetRuntime()
+	if s := application.GetServiceContainer(); s == nil {
+		return nil, errors.ErrServiceContainerNotImplement
+	} else {
+		return s.ServiceStates(), nil
 	}
 }
 
 // ================= cron =============================
 // 设置定时调度函数
-// 并发不安全, github.com/robfig/cron 中的AddFunc函数会对同一个切片进行修改
 func Cron(spec string, cmd func()) error {
 	application := gira.GetRuntime()
 	if s := application.GetCron(); s == nil {
@@ -481,3 +707,53 @@ func Cron(spec string, cmd func()) error {
 		return s.AddFunc(spec, cmd)
 	}
 }
+
+// 添加定时任务, 返回任务id
+func AddCronJob(name string, spec string, cmd gira.CronFunc, opt ...cron_options.AddOption) (int64, error) {
+	application := gira.GetRuntime()
+	if s := application.GetCron(); s == nil {
+		return 0, errors.ErrCronNotImplement
+	} else {
+		return s.AddJob(name, spec, cmd, opt...)
+	}
+}
+
+// 删除定时任务
+func RemoveCronJob(id int64) error {
+	application := gira.GetRuntime()
+	if s := application.GetCron(); s == nil {
+		return errors.ErrCronNotImplement
+	} else {
+		return s.Remove(id)
+	}
+}
+
+// 暂停定时任务
+func PauseCronJob(id int64) error {
+	application := gira.GetRuntime()
+	if s := application.GetCron(); s == nil {
+		return errors.ErrCronNotImplement
+	} else {
+		return s.Pause(id)
+	}
+}
+
+// 恢复定时任务
+func ResumeCronJob(id int64) error {
+	application := gira.GetRuntime()
+	if s := application.GetCron(); s == nil {
+		return errors.ErrCronNotImplement
+	} else {
+		return s.Resume(id)
+	}
+}
+
+// 全部定时任务的状态
+func ListCronJobs() ([]*gira.CronJob, error) {
+	application := gira.GetRuntime()
+	if s := application.GetCron(); s == nil {
+		return nil, errors.ErrCronNotImplement
+	} else {
+		return s.List(), nil
+	}
+}
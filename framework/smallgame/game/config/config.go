@@ -16,6 +16,8 @@ type GameConfig struct {
 	PushBufferSize       int   `yaml:"push-buffer-size"`
 	SessionActorBuffSize int   `yaml:"session-actor-buffer-size"`
 	TraceProtoDebugMsg   bool  `yaml:"trace-proto-debug-msg"`
+	// 玩家定时器的精度, 毫秒
+	TimerTick int64 `yaml:"timer-tick"`
 }
 
 type Config struct {
@@ -6,6 +6,7 @@ import (
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/actor"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/game/config"
+	"github.com/Lyndon-Zhang/gira/timer"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -35,6 +36,8 @@ type Session interface {
 	Push(resp gira.ProtoPush) (err error)
 	Kick(ctx context.Context, reason string) (err error)
 	Inbox() chan actor.Request
+	// 定时器, 回调在session的协程中执行
+	Timers() *timer.Timers
 	// 让出session的控制权后执行f函数， 返回后将重新获得控制权
 	Yield(f func() error) error
 	// 抢占session的控制台执行f函数，返回后释放控制台
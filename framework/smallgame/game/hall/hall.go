@@ -14,6 +14,7 @@ import (
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/game/config"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/gen/service/hallpb"
 	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"github.com/Lyndon-Zhang/gira/timer"
 )
 
 type HallService interface {
@@ -41,6 +42,10 @@ func GetServiceName() string {
 	return facade.NewServiceName(hallpb.HallServerName, service_options.WithAsAppServiceOption())
 }
 
+const (
+	default_timer_tick = 100 * time.Millisecond
+)
+
 type hall_service struct {
 	hallServer           *hall_server
 	ctx                  context.Context
@@ -55,6 +60,7 @@ type hall_service struct {
 	proto                gira.Proto
 	config               config.GameConfig
 	status               hallpb.HallStatus
+	timerWheel           *timer.Wheel
 }
 
 func (hall *hall_service) OnStart(ctx context.Context) error {
@@ -65,6 +71,13 @@ func (hall *hall_service) OnStart(ctx context.Context) error {
 	// 后台运行
 	hall.backgroundCtx, hall.backgroundCancelFunc = context.WithCancel(context.Background())
 	hall.gateStreamCtx, hall.gateStreamCancelFunc = context.WithCancel(hall.backgroundCtx)
+	// 全部session共用一个时间轮
+	tick := time.Duration(hall.config.TimerTick) * time.Millisecond
+	if tick <= 0 {
+		tick = default_timer_tick
+	}
+	hall.timerWheel = timer.NewWheel(tick, timer.RealClock)
+	hall.timerWheel.Start(hall.backgroundCtx)
 	hallpb.RegisterHallServer(facade.GrpcServer(), hall.hallServer)
 	return nil
 }
@@ -15,6 +15,8 @@ import (
 	"github.com/Lyndon-Zhang/gira/facade"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/game"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/gen/service/hallpb"
+	"github.com/Lyndon-Zhang/gira/timer"
+	"github.com/Lyndon-Zhang/gira/trace"
 )
 
 // 锁是和session绑定的，因此由session来抢占会释放
@@ -36,6 +38,7 @@ type hall_sesssion struct {
 	clientCancelFunc context.CancelFunc
 	isClosed         int32
 	mu               sync.Mutex
+	timers           *timer.Timers
 }
 
 func newSession(hall *hall_service, sessionId uint64, memberId string) (session *hall_sesssion, err error) {
@@ -100,9 +103,10 @@ func newSession(hall *hall_service, sessionId uint64, memberId string) (session
 		userId:    userId,
 		memberId:  memberId,
 		avatar:    avatar,
-		Actor:     actor.NewActor(hall.config.SessionActorBuffSize),
 	}
+	session.Actor = actor.NewActor(hall.config.SessionActorBuffSize, actor.WithInterceptor(session.processActorRequest))
 	session.ctx, session.cancelFunc = context.WithCancel(ctx)
+	session.timers = timer.NewTimers(hall.timerWheel, session)
 
 	if _, loaded := hall.sessionDict.LoadOrStore(userId, session); loaded {
 		log.Warnw("session store fail", "session_id", sessionId)
@@ -123,6 +127,10 @@ func newSession(hall *hall_service, sessionId uint64, memberId string) (session
 	return
 }
 
+func (session *hall_sesssion) Timers() *timer.Timers {
+	return session.timers
+}
+
 func (session *hall_sesssion) Yield(f func() error) error {
 	session.mu.Unlock()
 	defer func() {
@@ -173,16 +181,7 @@ func (session *hall_sesssion) serve() {
 			}
 		}
 	}()
-	go func() {
-		for {
-			select {
-			case r := <-session.Inbox():
-				session.processActorRequest(r)
-			case <-session.ctx.Done():
-				return
-			}
-		}
-	}()
+	go session.Actor.Serve(session.ctx)
 	for {
 		select {
 		// 定时保存数据
@@ -281,12 +280,12 @@ func (self *hall_sesssion) sendPacketAndClose(ctx context.Context, typ hallpb.Pa
 }
 
 // 处理actor的request
-func (session *hall_sesssion) processActorRequest(req actor.Request) {
+func (session *hall_sesssion) processActorRequest(next func()) {
 	defer func() {
 		session.mu.Unlock()
 	}()
 	session.mu.Lock()
-	req.Next()
+	next()
 }
 
 // 处理peer的push消息
@@ -322,14 +321,22 @@ func (session *hall_sesssion) processClientMessage(message *hallpb.ClientMessage
 	var req interface{}
 	var resp []byte
 	var pushArr []gira.ProtoPush
+	// 沿用网关生成的trace
+	ctx := trace.ContextWithRemote(session.ctx, message.TraceId, message.SpanId, message.Sampled)
+	ctx, span := trace.StartSpan(ctx, "hall/request", trace.SPAN_KIND_SERVER)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
 	name, reqId, req, err = session.hall.proto.RequestDecode(message.Data)
 	if err != nil {
-		log.Errorw("request decode fail", "session_id", sessionId, "error", err)
+		log.ErrorwContext(ctx, "request decode fail", "session_id", sessionId, "error", err)
 		return
 	}
-	log.Infow("request ", "session_id", sessionId, "name", name, "req_id", reqId, "data", message.Data)
+	span.SetAttribute("route", name)
+	log.InfowContext(ctx, "request ", "session_id", sessionId, "name", name, "req_id", reqId, "data", message.Data)
 
-	timeoutCtx, timeoutFunc := context.WithTimeout(session.ctx, 10*time.Second)
+	timeoutCtx, timeoutFunc := context.WithTimeout(ctx, 10*time.Second)
 	defer func() {
 		session.mu.Unlock()
 		timeoutFunc()
@@ -343,7 +350,7 @@ func (session *hall_sesssion) processClientMessage(message *hallpb.ClientMessage
 	session.mu.Lock()
 	resp, pushArr, err = session.hall.proto.RequestDispatch(timeoutCtx, session.hall.playerHandler, session.player, name, reqId, req, session.hall.config.TraceProtoDebugMsg)
 	if err != nil {
-		log.Errorw("request dispatch fail", "session_id", sessionId, "error", err)
+		log.ErrorwContext(ctx, "request dispatch fail", "session_id", sessionId, "error", err)
 		return
 	}
 	if session.isClosed != 0 {
@@ -400,6 +407,7 @@ func (session *hall_sesssion) Close(ctx context.Context) (err error) {
 			}
 		}
 		session.cancelFunc()
+		session.timers.Stop()
 		peer, err := facade.UnlockLocalUser(userId)
 		log.Infow("unlock local user return", "session_id", sessionId, "peer", peer, "err", err)
 		// 从agent dict释放
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	log "github.com/Lyndon-Zhang/gira/corelog"
 	"github.com/Lyndon-Zhang/gira/errors"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/gen/service/hallpb"
+	"github.com/Lyndon-Zhang/gira/trace"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -22,6 +24,8 @@ type client_session struct {
 	stream          hallpb.Hall_ClientStreamClient
 	server          *Server
 	pendingMessages []gira.GatewayMessage
+	spanMu          sync.Mutex
+	spans           map[uint64]*trace.Span // 等待响应的请求
 }
 
 func newSession(server *Server, sessionId uint64, memberId string) *client_session {
@@ -30,6 +34,7 @@ func newSession(server *Server, sessionId uint64, memberId string) *client_sessi
 		sessionId:       sessionId,
 		memberId:        memberId,
 		pendingMessages: make([]gira.GatewayMessage, 0),
+		spans:           make(map[uint64]*trace.Span),
 	}
 }
 
@@ -64,6 +69,7 @@ func (session *client_session) serve(ctx context.Context, client gira.GatewayCon
 		log.Infow("session close", "session_id", sessionId)
 		atomic.AddInt64(&session.server.SessionCount, -1)
 		session.cancelFunc()
+		session.endSpans(errors.ErrUpstreamUnavailable)
 	}()
 	// 将上游消息转发到客户端
 	errGroup.Go(func() (err error) {
@@ -189,9 +195,11 @@ func (session *client_session) serve(ctx context.Context, client gira.GatewayCon
 func (self *client_session) processClientMessage(message gira.GatewayMessage) error {
 	sessionId := self.sessionId
 	memberId := self.memberId
-	log.Infow("client=>upstream", "session_id", sessionId, "len", len(message.Payload()), "req_id", message.ReqId())
+	ctx, span := self.startSpan(message.ReqId())
+	log.InfowContext(ctx, "client=>upstream", "session_id", sessionId, "len", len(message.Payload()), "req_id", message.ReqId())
 	if self.stream == nil {
-		log.Warnw("当前服务器不可以用，无法转发", "req_id", message.ReqId())
+		log.WarnwContext(ctx, "当前服务器不可以用，无法转发", "req_id", message.ReqId())
+		self.failSpan(message.ReqId(), span, errors.ErrUpstreamUnavailable)
 		return errors.ErrUpstreamUnavailable
 	} else {
 		data := &hallpb.ClientMessageRequest{
@@ -199,14 +207,69 @@ func (self *client_session) processClientMessage(message gira.GatewayMessage) er
 			SessionId: sessionId,
 			ReqId:     message.ReqId(),
 			Data:      message.Payload(),
+			TraceId:   span.TraceId,
+			SpanId:    span.SpanId,
+			Sampled:   span.Sampled,
 		}
 		if err := self.stream.Send(data); err != nil {
+			self.failSpan(message.ReqId(), span, err)
 			return err
 		}
+		// 通知没有响应
+		if message.ReqId() == 0 {
+			span.End()
+		}
 		return nil
 	}
 }
 
+// 每次转发一个span, 通知没有响应, 不用等待
+func (self *client_session) startSpan(reqId uint64) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(self.ctx, "gateway/request", trace.SPAN_KIND_SERVER)
+	span.SetAttribute("member_id", self.memberId)
+	if reqId != 0 {
+		self.spanMu.Lock()
+		self.spans[reqId] = span
+		self.spanMu.Unlock()
+	}
+	return ctx, span
+}
+
+// 转发失败时结束span, 补发时重新开始一个span
+func (self *client_session) failSpan(reqId uint64, span *trace.Span, err error) {
+	self.spanMu.Lock()
+	if self.spans[reqId] == span {
+		delete(self.spans, reqId)
+	}
+	self.spanMu.Unlock()
+	span.SetError(err)
+	span.End()
+}
+
+// 收到响应时结束span
+func (self *client_session) endSpan(reqId uint64, err error) {
+	self.spanMu.Lock()
+	span, ok := self.spans[reqId]
+	delete(self.spans, reqId)
+	self.spanMu.Unlock()
+	if ok {
+		span.SetError(err)
+		span.End()
+	}
+}
+
+// session关闭时结束还没有响应的span
+func (self *client_session) endSpans(err error) {
+	self.spanMu.Lock()
+	spans := self.spans
+	self.spans = make(map[uint64]*trace.Span)
+	self.spanMu.Unlock()
+	for _, span := range spans {
+		span.SetError(err)
+		span.End()
+	}
+}
+
 // 处理上游的消息
 func (session *client_session) processStreamMessage(message *hallpb.ClientMessageResponse) error {
 	sessionId := session.sessionId
@@ -216,6 +279,7 @@ func (session *client_session) processStreamMessage(message *hallpb.ClientMessag
 	case hallpb.PacketType_DATA:
 		if message.ReqId != 0 {
 			session.client.Response(message.ReqId, message.Data)
+			session.endSpan(message.ReqId, nil)
 		} else {
 			session.client.Push("", message.Data)
 		}
@@ -13,7 +13,6 @@ import (
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/gateway/config"
 	"github.com/Lyndon-Zhang/gira/framework/smallgame/gen/service/hallpb"
 	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc"
 )
 
 // hall.ctx
@@ -27,7 +26,7 @@ type Upstream struct {
 	FullName string
 	Address  string
 
-	client      hallpb.HallClient
+	client      hallpb.HallClientsUnicast
 	ctx         context.Context
 	cancelFunc  context.CancelFunc
 	playerCount int64
@@ -160,8 +159,6 @@ func (server *Upstream) HealthCheck() (err error) {
 }
 
 func (server *Upstream) serve() error {
-	var conn *grpc.ClientConn
-	var client hallpb.HallClient
 	var stream hallpb.Hall_GateStreamClient
 	var err error
 	address := server.Address
@@ -174,27 +171,8 @@ func (server *Upstream) serve() error {
 		dialTicker.Stop()
 		log.Infow("server upstream exit", "full_name", server.FullName, "address", server.Address)
 	}()
-	// 1.dial
-	for {
-		// TODO: 有什么可选参数
-		conn, err = grpc.Dial(address, grpc.WithInsecure())
-		if err != nil {
-			log.Errorw("server dail fail", "error", err, "full_name", server.FullName, "address", server.Address)
-			select {
-			case <-server.ctx.Done():
-				return server.ctx.Err()
-			case <-dialTicker.C:
-				// 重连
-				continue
-			}
-		} else {
-			log.Infow("server dial success", "full_name", server.FullName)
-			break
-		}
-	}
-	defer conn.Close()
-	// 2.new stream
-	client = hallpb.NewHallClient(conn)
+	// 1.new stream, 连接由runtime管理
+	client := hallpb.DefaultHallClients.Unicast().WhereAddress(address)
 	for {
 		stream, err = client.GateStream(streamCtx)
 		if err != nil {
@@ -214,7 +192,7 @@ func (server *Upstream) serve() error {
 	dialTicker.Stop()
 	heartbeatTicker := time.NewTicker(time.Duration(config.Gateway.Framework.Gateway.Upstream.HeartbeatInvertal) * time.Second)
 	defer heartbeatTicker.Stop()
-	// 3.init
+	// 2.init
 	{
 		req := &hallpb.InfoRequest{}
 		resp, err := client.Info(server.ctx, req)
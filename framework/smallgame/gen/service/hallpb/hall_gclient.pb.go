@@ -12,12 +12,11 @@ import (
 	gira "github.com/Lyndon-Zhang/gira"
 	errors "github.com/Lyndon-Zhang/gira/errors"
 	facade "github.com/Lyndon-Zhang/gira/facade"
+	policy "github.com/Lyndon-Zhang/gira/grpc/policy"
+	scatter "github.com/Lyndon-Zhang/gira/grpc/scatter"
 	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
 	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
 	metadata "google.golang.org/grpc/metadata"
-	status "google.golang.org/grpc/status"
-	sync "sync"
 	time "time"
 )
 
@@ -496,25 +495,47 @@ type HallClients interface {
 type HallClientsMulticast interface {
 	WhereRegex(regex string) HallClientsMulticast
 	WherePrefix(prefix bool) HallClientsMulticast
+	WhereZone(zone string) HallClientsMulticast
+	WhereAllZone() HallClientsMulticast
 	Local() HallClientsMulticast
+	WithTimeout(timeout time.Duration) HallClientsMulticast
+	WithRetry(retry int, backoff time.Duration) HallClientsMulticast
+	WithHedge(delay time.Duration) HallClientsMulticast
+	WithBreaker(enabled bool) HallClientsMulticast
+	WithConcurrency(concurrency int) HallClientsMulticast
+	WithQuorum(quorum int) HallClientsMulticast
 	// client消息流
 	ClientStream(ctx context.Context, opts ...grpc.CallOption) (*Hall_ClientStreamClient_MulticastResult, error)
 	// 网关消息流
 	GateStream(ctx context.Context, opts ...grpc.CallOption) (*Hall_GateStreamClient_MulticastResult, error)
 	// 状态
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse_MulticastResult, error)
+	InfoGather(ctx context.Context, in *InfoRequest, f func(result *scatter.Result[*InfoResponse]), opts ...grpc.CallOption) error
+	InfoGatherChan(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) *scatter.Stream[*InfoResponse]
 	// 心跳
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse_MulticastResult, error)
+	HealthCheckGather(ctx context.Context, in *HealthCheckRequest, f func(result *scatter.Result[*HealthCheckResponse]), opts ...grpc.CallOption) error
+	HealthCheckGatherChan(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) *scatter.Stream[*HealthCheckResponse]
 	// rpc PushStream (stream PushStreamNotify) returns (PushStreamPush) {}
 	MustPush(ctx context.Context, in *MustPushRequest, opts ...grpc.CallOption) (*MustPushResponse_MulticastResult, error)
+	MustPushGather(ctx context.Context, in *MustPushRequest, f func(result *scatter.Result[*MustPushResponse]), opts ...grpc.CallOption) error
+	MustPushGatherChan(ctx context.Context, in *MustPushRequest, opts ...grpc.CallOption) *scatter.Stream[*MustPushResponse]
 	// 发送消息
 	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse_MulticastResult, error)
+	SendMessageGather(ctx context.Context, in *SendMessageRequest, f func(result *scatter.Result[*SendMessageResponse]), opts ...grpc.CallOption) error
+	SendMessageGatherChan(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) *scatter.Stream[*SendMessageResponse]
 	// 发送消息
 	CallMessage(ctx context.Context, in *CallMessageRequest, opts ...grpc.CallOption) (*CallMessageResponse_MulticastResult, error)
+	CallMessageGather(ctx context.Context, in *CallMessageRequest, f func(result *scatter.Result[*CallMessageResponse]), opts ...grpc.CallOption) error
+	CallMessageGatherChan(ctx context.Context, in *CallMessageRequest, opts ...grpc.CallOption) *scatter.Stream[*CallMessageResponse]
 	// 顶号下线
 	UserInstead(ctx context.Context, in *UserInsteadRequest, opts ...grpc.CallOption) (*UserInsteadResponse_MulticastResult, error)
+	UserInsteadGather(ctx context.Context, in *UserInsteadRequest, f func(result *scatter.Result[*UserInsteadResponse]), opts ...grpc.CallOption) error
+	UserInsteadGatherChan(ctx context.Context, in *UserInsteadRequest, opts ...grpc.CallOption) *scatter.Stream[*UserInsteadResponse]
 	// 踢人下线
 	Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickResponse_MulticastResult, error)
+	KickGather(ctx context.Context, in *KickRequest, f func(result *scatter.Result[*KickResponse]), opts ...grpc.CallOption) error
+	KickGatherChan(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) *scatter.Stream[*KickResponse]
 }
 
 type HallClientsUnicast interface {
@@ -523,7 +544,14 @@ type HallClientsUnicast interface {
 	WherePeerFullName(appFullName string) HallClientsUnicast
 	WhereAddress(address string) HallClientsUnicast
 	WhereUser(userId string) HallClientsUnicast
+	WhereKey(key string) HallClientsUnicast
+	WhereZone(zone string) HallClientsUnicast
+	WhereAllZone() HallClientsUnicast
 	Local() HallClientsUnicast
+	WithTimeout(timeout time.Duration) HallClientsUnicast
+	WithRetry(retry int, backoff time.Duration) HallClientsUnicast
+	WithHedge(delay time.Duration) HallClientsUnicast
+	WithBreaker(enabled bool) HallClientsUnicast
 
 	// client消息流
 	ClientStream(ctx context.Context, opts ...grpc.CallOption) (Hall_ClientStreamClient, error)
@@ -546,57 +574,23 @@ type HallClientsUnicast interface {
 }
 
 type hallClients struct {
-	mu          sync.Mutex
-	clientPool  map[string]*sync.Pool
 	serviceName string
 }
 
 func NewHallClients() HallClients {
 	return &hallClients{
 		serviceName: HallServerName,
-		clientPool:  make(map[string]*sync.Pool, 0),
 	}
 }
 
 var DefaultHallClients = NewHallClients()
 
 func (c *hallClients) getClient(address string) (HallClient, error) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; !ok {
-		pool = &sync.Pool{
-			New: func() any {
-				conn, err := grpc.Dial(address, grpc.WithInsecure())
-				if err != nil {
-					return err
-				}
-				client := NewHallClient(conn)
-				return client
-			},
-		}
-		c.clientPool[address] = pool
-		c.mu.Unlock()
-	} else {
-		c.mu.Unlock()
-	}
-	if v := pool.Get(); v == nil {
-		return nil, errors.ErrGrpcClientPoolNil
-	} else if err, ok := v.(error); ok {
+	conn, err := facade.GetGrpcConn(address)
+	if err != nil {
 		return nil, err
-	} else {
-		return v.(HallClient), nil
-	}
-}
-
-func (c *hallClients) putClient(address string, client HallClient) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; ok {
-		pool.Put(client)
 	}
-	c.mu.Unlock()
+	return NewHallClient(conn), nil
 }
 
 func (c *hallClients) WithServiceName(serviceName string) HallClients {
@@ -607,7 +601,8 @@ func (c *hallClients) WithServiceName(serviceName string) HallClients {
 func (c *hallClients) Unicast() HallClientsUnicast {
 	headers := make(map[string]string)
 	u := &hallClientsUnicast{
-		timeout: 5,
+		timeout: 5 * time.Second,
+		breaker: true,
 		headers: metadata.New(headers),
 		client:  c,
 	}
@@ -617,7 +612,8 @@ func (c *hallClients) Unicast() HallClientsUnicast {
 func (c *hallClients) Multicast(count int) HallClientsMulticast {
 	headers := make(map[string]string)
 	u := &hallClientsMulticast{
-		timeout:     5,
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       count,
 		headers:     metadata.New(headers),
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
@@ -628,6 +624,8 @@ func (c *hallClients) Multicast(count int) HallClientsMulticast {
 
 func (c *hallClients) Broadcast() HallClientsMulticast {
 	u := &hallClientsMulticast{
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       -1,
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
 		client:      c,
@@ -640,7 +638,6 @@ func (c *hallClients) ClientStream(ctx context.Context, address string, opts ...
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.ClientStream(ctx, opts...)
 	if err != nil {
 		return nil, err
@@ -653,7 +650,6 @@ func (c *hallClients) GateStream(ctx context.Context, address string, opts ...gr
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GateStream(ctx, opts...)
 	if err != nil {
 		return nil, err
@@ -666,7 +662,6 @@ func (c *hallClients) Info(ctx context.Context, address string, in *InfoRequest,
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.Info(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -679,7 +674,6 @@ func (c *hallClients) HealthCheck(ctx context.Context, address string, in *Healt
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.HealthCheck(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -692,7 +686,6 @@ func (c *hallClients) MustPush(ctx context.Context, address string, in *MustPush
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.MustPush(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -705,7 +698,6 @@ func (c *hallClients) SendMessage(ctx context.Context, address string, in *SendM
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.SendMessage(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -718,7 +710,6 @@ func (c *hallClients) CallMessage(ctx context.Context, address string, in *CallM
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.CallMessage(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -731,7 +722,6 @@ func (c *hallClients) UserInstead(ctx context.Context, address string, in *UserI
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.UserInstead(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -744,7 +734,6 @@ func (c *hallClients) Kick(ctx context.Context, address string, in *KickRequest,
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.Kick(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -753,12 +742,19 @@ func (c *hallClients) Kick(ctx context.Context, address string, in *KickRequest,
 }
 
 type hallClientsUnicast struct {
-	timeout      int64
+	timeout      time.Duration
+	retry        int
+	backoff      time.Duration
+	hedge        time.Duration
+	breaker      bool
 	peer         *gira.Peer
 	peerFullName string
 	serviceName  string
 	address      string
 	userId       string
+	key          string
+	zone         string
+	allZone      bool
 	local        bool
 	client       *hallClients
 	headers      metadata.MD
@@ -795,15 +791,84 @@ func (c *hallClientsUnicast) WhereUser(userId string) HallClientsUnicast {
 	return c
 }
 
+// 根据key一致性hash选择服务, 服务名为Where设置的目录，默认为服务目录
+func (c *hallClientsUnicast) WhereKey(key string) HallClientsUnicast {
+	c.key = key
+	return c
+}
+
+// 在指定的区查找服务, 默认只查找本区
+func (c *hallClientsUnicast) WhereZone(zone string) HallClientsUnicast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *hallClientsUnicast) WhereAllZone() HallClientsUnicast {
+	c.allZone = true
+	return c
+}
+
+// 调用超时, 包括重试的时间
+func (c *hallClientsUnicast) WithTimeout(timeout time.Duration) HallClientsUnicast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *hallClientsUnicast) WithRetry(retry int, backoff time.Duration) HallClientsUnicast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *hallClientsUnicast) WithHedge(delay time.Duration) HallClientsUnicast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *hallClientsUnicast) WithBreaker(enabled bool) HallClientsUnicast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *hallClientsUnicast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
+func (c *hallClientsUnicast) whereOpts(opts ...service_options.WhereOption) []service_options.WhereOption {
+	if len(c.zone) > 0 {
+		opts = append(opts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		opts = append(opts, service_options.WithWhereAllZoneOption())
+	}
+	return opts
+}
+
 func (c *hallClientsUnicast) ClientStream(ctx context.Context, opts ...grpc.CallOption) (Hall_ClientStreamClient, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ClientStream not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		c.local = false
+		return c.WherePeerFullName(facade.GetAppFullName()).ClientStream(ctx, opts...)
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -813,25 +878,47 @@ func (c *hallClientsUnicast) ClientStream(ctx context.Context, opts ...grpc.Call
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -839,29 +926,32 @@ func (c *hallClientsUnicast) ClientStream(ctx context.Context, opts ...grpc.Call
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.ClientStream(ctx, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		// 流不设置超时, 只使用熔断
+		return policy.Invoke(ctx, policy.Policy{Breaker: c.breaker}, peerName, address, func(ctx context.Context) (Hall_ClientStreamClient, error) {
+			return client.ClientStream(ctx, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) GateStream(ctx context.Context, opts ...grpc.CallOption) (Hall_GateStreamClient, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method GateStream not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		c.local = false
+		return c.WherePeerFullName(facade.GetAppFullName()).GateStream(ctx, opts...)
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -871,25 +961,47 @@ func (c *hallClientsUnicast) GateStream(ctx context.Context, opts ...grpc.CallOp
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -897,23 +1009,21 @@ func (c *hallClientsUnicast) GateStream(ctx context.Context, opts ...grpc.CallOp
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GateStream(ctx, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		// 流不设置超时, 只使用熔断
+		return policy.Invoke(ctx, policy.Policy{Breaker: c.breaker}, peerName, address, func(ctx context.Context) (Hall_GateStreamClient, error) {
+			return client.GateStream(ctx, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -928,9 +1038,12 @@ func (c *hallClientsUnicast) Info(ctx context.Context, in *InfoRequest, opts ...
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -940,25 +1053,47 @@ func (c *hallClientsUnicast) Info(ctx context.Context, in *InfoRequest, opts ...
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -966,23 +1101,20 @@ func (c *hallClientsUnicast) Info(ctx context.Context, in *InfoRequest, opts ...
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.Info(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*InfoResponse, error) {
+			return client.Info(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -997,9 +1129,12 @@ func (c *hallClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1009,25 +1144,47 @@ func (c *hallClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1035,23 +1192,20 @@ func (c *hallClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.HealthCheck(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+			return client.HealthCheck(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) MustPush(ctx context.Context, in *MustPushRequest, opts ...grpc.CallOption) (*MustPushResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1066,9 +1220,12 @@ func (c *hallClientsUnicast) MustPush(ctx context.Context, in *MustPushRequest,
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1078,25 +1235,47 @@ func (c *hallClientsUnicast) MustPush(ctx context.Context, in *MustPushRequest,
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1104,23 +1283,20 @@ func (c *hallClientsUnicast) MustPush(ctx context.Context, in *MustPushRequest,
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.MustPush(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*MustPushResponse, error) {
+			return client.MustPush(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1135,9 +1311,12 @@ func (c *hallClientsUnicast) SendMessage(ctx context.Context, in *SendMessageReq
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1147,25 +1326,47 @@ func (c *hallClientsUnicast) SendMessage(ctx context.Context, in *SendMessageReq
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1173,23 +1374,20 @@ func (c *hallClientsUnicast) SendMessage(ctx context.Context, in *SendMessageReq
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.SendMessage(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*SendMessageResponse, error) {
+			return client.SendMessage(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) CallMessage(ctx context.Context, in *CallMessageRequest, opts ...grpc.CallOption) (*CallMessageResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1204,9 +1402,12 @@ func (c *hallClientsUnicast) CallMessage(ctx context.Context, in *CallMessageReq
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1216,25 +1417,47 @@ func (c *hallClientsUnicast) CallMessage(ctx context.Context, in *CallMessageReq
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1242,23 +1465,20 @@ func (c *hallClientsUnicast) CallMessage(ctx context.Context, in *CallMessageReq
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.CallMessage(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*CallMessageResponse, error) {
+			return client.CallMessage(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) UserInstead(ctx context.Context, in *UserInsteadRequest, opts ...grpc.CallOption) (*UserInsteadResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1273,9 +1493,12 @@ func (c *hallClientsUnicast) UserInstead(ctx context.Context, in *UserInsteadReq
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1285,25 +1508,47 @@ func (c *hallClientsUnicast) UserInstead(ctx context.Context, in *UserInsteadReq
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1311,23 +1556,20 @@ func (c *hallClientsUnicast) UserInstead(ctx context.Context, in *UserInsteadReq
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.UserInstead(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*UserInsteadResponse, error) {
+			return client.UserInstead(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *hallClientsUnicast) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1342,9 +1584,12 @@ func (c *hallClientsUnicast) Kick(ctx context.Context, in *KickRequest, opts ...
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1354,25 +1599,47 @@ func (c *hallClientsUnicast) Kick(ctx context.Context, in *KickRequest, opts ...
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1380,27 +1647,32 @@ func (c *hallClientsUnicast) Kick(ctx context.Context, in *KickRequest, opts ...
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.Kick(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*KickResponse, error) {
+			return client.Kick(ctx, in, opts...)
+		})
 	}
 
 }
 
 type hallClientsMulticast struct {
-	timeout     int64
+	timeout     time.Duration
+	retry       int
+	backoff     time.Duration
+	hedge       time.Duration
+	breaker     bool
+	concurrency int
+	quorum      int
 	count       int
 	serviceName string
 	regex       string
 	prefix      bool
+	zone        string
+	allZone     bool
 	local       bool
 	client      *hallClients
 	headers     metadata.MD
@@ -1506,6 +1778,42 @@ func (c *hallClientsMulticast) Local() HallClientsMulticast {
 	return c
 }
 
+// 调用超时, 包括重试的时间
+func (c *hallClientsMulticast) WithTimeout(timeout time.Duration) HallClientsMulticast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *hallClientsMulticast) WithRetry(retry int, backoff time.Duration) HallClientsMulticast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *hallClientsMulticast) WithHedge(delay time.Duration) HallClientsMulticast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *hallClientsMulticast) WithBreaker(enabled bool) HallClientsMulticast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *hallClientsMulticast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
 func (c *hallClientsMulticast) WhereRegex(regex string) HallClientsMulticast {
 	c.regex = regex
 	return c
@@ -1516,9 +1824,77 @@ func (c *hallClientsMulticast) WherePrefix(prefix bool) HallClientsMulticast {
 	return c
 }
 
+// 在指定的区查找服务, 默认只查找本区
+func (c *hallClientsMulticast) WhereZone(zone string) HallClientsMulticast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *hallClientsMulticast) WhereAllZone() HallClientsMulticast {
+	c.allZone = true
+	return c
+}
+
+// 最多同时调用多少个节点, 只对Gather生效, 0表示不限制
+func (c *hallClientsMulticast) WithConcurrency(concurrency int) HallClientsMulticast {
+	c.concurrency = concurrency
+	return c
+}
+
+// 成功多少个节点后返回, 只对Gather生效, 0表示等待全部节点
+func (c *hallClientsMulticast) WithQuorum(quorum int) HallClientsMulticast {
+	c.quorum = quorum
+	return c
+}
+
+func (c *hallClientsMulticast) gatherOptions() scatter.Options {
+	return scatter.Options{
+		Concurrency: c.concurrency,
+		Quorum:      c.quorum,
+	}
+}
+
+// 查找要调用的节点, Gather不支持Local
+func (c *hallClientsMulticast) wherePeers() ([]*gira.Peer, error) {
+	var whereOpts []service_options.WhereOption
+	whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+	if c.count > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+	}
+	serviceName := c.serviceName
+	if len(c.regex) > 0 {
+		serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+		whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+	}
+	if c.prefix {
+		whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+	}
+	if len(c.zone) > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+	}
+	return facade.WhereIsServiceName(serviceName, whereOpts...)
+}
+
 func (c *hallClientsMulticast) ClientStream(ctx context.Context, opts ...grpc.CallOption) (*Hall_ClientStreamClient_MulticastResult, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ClientStream not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		peer, err := facade.WhereIsPeer(facade.GetAppFullName())
+		if err != nil {
+			return nil, err
+		}
+		result := &Hall_ClientStreamClient_MulticastResult{peerCount: 1}
+		if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).ClientStream(ctx, opts...); err != nil {
+			result.errors = append(result.errors, err)
+			result.errorPeers = append(result.errorPeers, peer)
+		} else {
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
 
 	} else {
 		var peers []*gira.Peer
@@ -1536,6 +1912,12 @@ func (c *hallClientsMulticast) ClientStream(ctx context.Context, opts ...grpc.Ca
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1559,7 +1941,6 @@ func (c *hallClientsMulticast) ClientStream(ctx context.Context, opts ...grpc.Ca
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
 			result.responses = append(result.responses, out)
@@ -1571,7 +1952,20 @@ func (c *hallClientsMulticast) ClientStream(ctx context.Context, opts ...grpc.Ca
 }
 func (c *hallClientsMulticast) GateStream(ctx context.Context, opts ...grpc.CallOption) (*Hall_GateStreamClient_MulticastResult, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method GateStream not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		peer, err := facade.WhereIsPeer(facade.GetAppFullName())
+		if err != nil {
+			return nil, err
+		}
+		result := &Hall_GateStreamClient_MulticastResult{peerCount: 1}
+		if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).GateStream(ctx, opts...); err != nil {
+			result.errors = append(result.errors, err)
+			result.errorPeers = append(result.errorPeers, peer)
+		} else {
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
 
 	} else {
 		var peers []*gira.Peer
@@ -1589,6 +1983,12 @@ func (c *hallClientsMulticast) GateStream(ctx context.Context, opts ...grpc.Call
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1612,7 +2012,6 @@ func (c *hallClientsMulticast) GateStream(ctx context.Context, opts ...grpc.Call
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
 			result.responses = append(result.responses, out)
@@ -1628,7 +2027,7 @@ func (c *hallClientsMulticast) Info(ctx context.Context, in *InfoRequest, opts .
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &InfoResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1658,6 +2057,12 @@ func (c *hallClientsMulticast) Info(ctx context.Context, in *InfoRequest, opts .
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1673,18 +2078,18 @@ func (c *hallClientsMulticast) Info(ctx context.Context, in *InfoRequest, opts .
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.Info(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*InfoResponse, error) {
+				return client.Info(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1692,13 +2097,50 @@ func (c *hallClientsMulticast) Info(ctx context.Context, in *InfoRequest, opts .
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) InfoGather(ctx context.Context, in *InfoRequest, f func(result *scatter.Result[*InfoResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.infoCall(in, opts...), f)
+}
+
+// 和InfoGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) InfoGatherChan(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) *scatter.Stream[*InfoResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*InfoResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.infoCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) infoCall(in *InfoRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*InfoResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*InfoResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*InfoResponse, error) {
+			return client.Info(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &HealthCheckResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1728,6 +2170,12 @@ func (c *hallClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1743,18 +2191,18 @@ func (c *hallClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.HealthCheck(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+				return client.HealthCheck(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1762,13 +2210,50 @@ func (c *hallClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) HealthCheckGather(ctx context.Context, in *HealthCheckRequest, f func(result *scatter.Result[*HealthCheckResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.healthCheckCall(in, opts...), f)
+}
+
+// 和HealthCheckGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) HealthCheckGatherChan(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) *scatter.Stream[*HealthCheckResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*HealthCheckResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.healthCheckCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) healthCheckCall(in *HealthCheckRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*HealthCheckResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*HealthCheckResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+			return client.HealthCheck(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) MustPush(ctx context.Context, in *MustPushRequest, opts ...grpc.CallOption) (*MustPushResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &MustPushResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1798,6 +2283,12 @@ func (c *hallClientsMulticast) MustPush(ctx context.Context, in *MustPushRequest
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1813,18 +2304,18 @@ func (c *hallClientsMulticast) MustPush(ctx context.Context, in *MustPushRequest
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.MustPush(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*MustPushResponse, error) {
+				return client.MustPush(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1832,13 +2323,50 @@ func (c *hallClientsMulticast) MustPush(ctx context.Context, in *MustPushRequest
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) MustPushGather(ctx context.Context, in *MustPushRequest, f func(result *scatter.Result[*MustPushResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.mustPushCall(in, opts...), f)
+}
+
+// 和MustPushGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) MustPushGatherChan(ctx context.Context, in *MustPushRequest, opts ...grpc.CallOption) *scatter.Stream[*MustPushResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*MustPushResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.mustPushCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) mustPushCall(in *MustPushRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*MustPushResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*MustPushResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*MustPushResponse, error) {
+			return client.MustPush(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &SendMessageResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1868,6 +2396,12 @@ func (c *hallClientsMulticast) SendMessage(ctx context.Context, in *SendMessageR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1883,18 +2417,18 @@ func (c *hallClientsMulticast) SendMessage(ctx context.Context, in *SendMessageR
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.SendMessage(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*SendMessageResponse, error) {
+				return client.SendMessage(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1902,13 +2436,50 @@ func (c *hallClientsMulticast) SendMessage(ctx context.Context, in *SendMessageR
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) SendMessageGather(ctx context.Context, in *SendMessageRequest, f func(result *scatter.Result[*SendMessageResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.sendMessageCall(in, opts...), f)
+}
+
+// 和SendMessageGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) SendMessageGatherChan(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) *scatter.Stream[*SendMessageResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*SendMessageResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.sendMessageCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) sendMessageCall(in *SendMessageRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*SendMessageResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*SendMessageResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*SendMessageResponse, error) {
+			return client.SendMessage(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) CallMessage(ctx context.Context, in *CallMessageRequest, opts ...grpc.CallOption) (*CallMessageResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &CallMessageResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1938,6 +2509,12 @@ func (c *hallClientsMulticast) CallMessage(ctx context.Context, in *CallMessageR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1953,18 +2530,18 @@ func (c *hallClientsMulticast) CallMessage(ctx context.Context, in *CallMessageR
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.CallMessage(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*CallMessageResponse, error) {
+				return client.CallMessage(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1972,13 +2549,50 @@ func (c *hallClientsMulticast) CallMessage(ctx context.Context, in *CallMessageR
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) CallMessageGather(ctx context.Context, in *CallMessageRequest, f func(result *scatter.Result[*CallMessageResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.callMessageCall(in, opts...), f)
+}
+
+// 和CallMessageGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) CallMessageGatherChan(ctx context.Context, in *CallMessageRequest, opts ...grpc.CallOption) *scatter.Stream[*CallMessageResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*CallMessageResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.callMessageCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) callMessageCall(in *CallMessageRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*CallMessageResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*CallMessageResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*CallMessageResponse, error) {
+			return client.CallMessage(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) UserInstead(ctx context.Context, in *UserInsteadRequest, opts ...grpc.CallOption) (*UserInsteadResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &UserInsteadResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -2008,6 +2622,12 @@ func (c *hallClientsMulticast) UserInstead(ctx context.Context, in *UserInsteadR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -2023,18 +2643,18 @@ func (c *hallClientsMulticast) UserInstead(ctx context.Context, in *UserInsteadR
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.UserInstead(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*UserInsteadResponse, error) {
+				return client.UserInstead(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -2042,13 +2662,50 @@ func (c *hallClientsMulticast) UserInstead(ctx context.Context, in *UserInsteadR
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) UserInsteadGather(ctx context.Context, in *UserInsteadRequest, f func(result *scatter.Result[*UserInsteadResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.userInsteadCall(in, opts...), f)
+}
+
+// 和UserInsteadGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) UserInsteadGatherChan(ctx context.Context, in *UserInsteadRequest, opts ...grpc.CallOption) *scatter.Stream[*UserInsteadResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*UserInsteadResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.userInsteadCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) userInsteadCall(in *UserInsteadRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*UserInsteadResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*UserInsteadResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*UserInsteadResponse, error) {
+			return client.UserInstead(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *hallClientsMulticast) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(HallServer); ok {
 			result := &KickResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -2078,6 +2735,12 @@ func (c *hallClientsMulticast) Kick(ctx context.Context, in *KickRequest, opts .
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -2093,18 +2756,18 @@ func (c *hallClientsMulticast) Kick(ctx context.Context, in *KickRequest, opts .
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.Kick(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*KickResponse, error) {
+				return client.Kick(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -2112,3 +2775,39 @@ func (c *hallClientsMulticast) Kick(ctx context.Context, in *KickRequest, opts .
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *hallClientsMulticast) KickGather(ctx context.Context, in *KickRequest, f func(result *scatter.Result[*KickResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.kickCall(in, opts...), f)
+}
+
+// 和KickGather一样, 结果通过channel返回
+func (c *hallClientsMulticast) KickGatherChan(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) *scatter.Stream[*KickResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*KickResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.kickCall(in, opts...))
+}
+
+func (c *hallClientsMulticast) kickCall(in *KickRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*KickResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*KickResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*KickResponse, error) {
+			return client.Kick(ctx, in, opts...)
+		})
+	}
+}
@@ -71,7 +71,7 @@ func (a *Conn) send(data []byte) (err error) {
 
 // 如果链接已关闭, 则返回ErrBrokenPipe
 func (self *Conn) Push(route string, data []byte) error {
-	if self.server.debug {
+	if self.server.getLimits().debug {
 		// log.Debugw("conn push", "session_id", self.session.Id(), "route", route, "len", len(data))
 	}
 	var err error
@@ -98,7 +98,7 @@ func (self *Conn) Push(route string, data []byte) error {
 
 // 如果链接已关闭,则返回ErrBrokenPipe
 func (self *Conn) Response(mid uint64, data []byte) error {
-	if self.server.debug {
+	if self.server.getLimits().debug {
 		log.Debugw("conn response", "session_id", self.session.Id(), "req_id", mid, "len", len(data))
 	}
 	if mid <= 0 {
@@ -171,7 +171,7 @@ func (self *Conn) Close() error {
 		return nil
 	}
 	self.setStatus(conn_status_closed)
-	if self.server.debug {
+	if self.server.getLimits().debug {
 		log.Infow("close conn", "session_id", self.session.Id(), "remote_addr", self.conn.RemoteAddr())
 	}
 	self.cancelFunc()
@@ -208,7 +208,7 @@ func (self *Conn) serialize(data []byte) ([]byte, error) {
 
 func (self *Conn) recvHandshake(ctx context.Context) error {
 	buf := make([]byte, 2048)
-	timeoutCtx, timeoutFunc := context.WithTimeout(ctx, self.server.handshakeTimeout)
+	timeoutCtx, timeoutFunc := context.WithTimeout(ctx, self.server.getLimits().handshakeTimeout)
 	defer timeoutFunc()
 	go func() {
 		select {
@@ -223,7 +223,7 @@ func (self *Conn) recvHandshake(ctx context.Context) error {
 	for {
 		n, err := self.conn.Read(buf)
 		if err != nil {
-			if self.server.debug {
+			if self.server.getLimits().debug {
 				log.Debugw("conn read fail", "err", err, "session_id", self.session.Id())
 			}
 			return err
@@ -260,7 +260,7 @@ func (self *Conn) recvHandshake(ctx context.Context) error {
 		data, err := json.Marshal(map[string]interface{}{
 			"code": 200,
 			"sys": map[string]interface{}{
-				"heartbeat": self.server.heartbeat.Seconds(),
+				"heartbeat": self.server.getLimits().heartbeat.Seconds(),
 				"session":   self.session.Id(),
 			},
 		})
@@ -274,7 +274,7 @@ func (self *Conn) recvHandshake(ctx context.Context) error {
 		if _, err := self.conn.Write(handsharkResponse); err != nil {
 			return err
 		}
-		if self.server.debug {
+		if self.server.getLimits().debug {
 			log.Debugw("handshake success", "session_id", self.session.Id(), "remote_addr", self.conn.RemoteAddr(), "secret", self.session.getSecret())
 		}
 		return nil
@@ -282,7 +282,7 @@ func (self *Conn) recvHandshake(ctx context.Context) error {
 }
 
 func (self *Conn) recvHandshakeAck(ctx context.Context) ([]*packet.Packet, error) {
-	cancelCtx, cancelFunc := context.WithTimeout(ctx, self.server.handshakeTimeout)
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, self.server.getLimits().handshakeTimeout)
 	defer cancelFunc()
 	go func() {
 		select {
@@ -298,7 +298,7 @@ func (self *Conn) recvHandshakeAck(ctx context.Context) ([]*packet.Packet, error
 	for {
 		n, err := self.conn.Read(buf)
 		if err != nil {
-			if self.server.debug {
+			if self.server.getLimits().debug {
 				log.Debugw("conn read fail", "err", err, "session_id", self.session.Id())
 			}
 			return nil, err
@@ -315,7 +315,7 @@ func (self *Conn) recvHandshakeAck(ctx context.Context) ([]*packet.Packet, error
 			return nil, ErrInvalidPacket
 		}
 		self.setStatus(conn_status_working)
-		if self.server.debug {
+		if self.server.getLimits().debug {
 			log.Debugw("recv handshake ack success", "session_id", self.session.Id(), "remote_addr", self.conn.RemoteAddr())
 		}
 		return packets[1:], nil
@@ -328,11 +328,11 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 	var packets []*packet.Packet
 	self.conn = conn
 	sessionId := self.session.Id()
-	if self.server.debug {
+	if self.server.getLimits().debug {
 		log.Debugw("conn established", "session_id", sessionId)
 	}
 	defer func() {
-		if self.server.debug {
+		if self.server.getLimits().debug {
 			log.Debugw("conn closed", "session_id", sessionId)
 		}
 		// 关闭链接， self.ctx还是空的，不用关闭ctx
@@ -356,8 +356,8 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 	self.setStatus(conn_status_working)
 
 	// 握手成功，开始收发消息
-	self.chMessage = make(chan *Message, self.server.recvBacklog)
-	self.chSend = make(chan []byte, self.server.sendBacklog)
+	self.chMessage = make(chan *Message, self.server.getLimits().recvBacklog)
+	self.chSend = make(chan []byte, self.server.getLimits().sendBacklog)
 
 	errGroup, errCtx := errgroup.WithContext(self.ctx)
 	self.errGroup, self.errCtx = errGroup, errCtx
@@ -367,7 +367,7 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 	errGroup.Go(func() (err error) {
 		defer func() {
 			close(self.chMessage)
-			if self.server.debug {
+			if self.server.getLimits().debug {
 				log.Debugw("conn recv goroutine exit", "sessionid", sessionId)
 			}
 		}()
@@ -379,12 +379,12 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 		}
 		var n int
 		// 持续读数据
-		buf := make([]byte, self.server.recvBuffSize)
+		buf := make([]byte, self.server.getLimits().recvBuffSize)
 		var packets []*packet.Packet
 		for {
 			n, err = self.conn.Read(buf)
 			if err != nil {
-				if self.server.debug {
+				if self.server.getLimits().debug {
 					log.Debugw("conn read fail", "err", err, "session_id", self.session.Id())
 				}
 				return
@@ -412,19 +412,19 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 		defer func() {
 			// 发送完数据后，可以关闭socket了，使recv可以解除阻塞
 			self.conn.Close()
-			if self.server.debug {
+			if self.server.getLimits().debug {
 				log.Debugw("conn send goroutine exit", "session_id", sessionId)
 			}
 		}()
 		err = func() (err error) {
-			ticker := time.NewTicker(self.server.heartbeat)
+			ticker := time.NewTicker(self.server.getLimits().heartbeat)
 			defer func() {
 				ticker.Stop()
 			}()
 			for {
 				select {
 				case <-ticker.C:
-					deadline := time.Now().Add(-2 * self.server.heartbeat).Unix()
+					deadline := time.Now().Add(-2 * self.server.getLimits().heartbeat).Unix()
 					if atomic.LoadInt64(&self.lastAt) < deadline {
 						log.Infof("gate connection heartbeat timeout, sessionid=%d, lastTime=%d, deadline=%d\n", sessionId, atomic.LoadInt64(&self.lastAt), deadline)
 						err = ErrHeartbeatTimeout
@@ -496,7 +496,7 @@ func (self *Conn) serve(ctx context.Context, conn net.Conn) error {
 	self.setStatus(conn_status_closed)
 	self.cancelFunc()
 	err = errGroup.Wait()
-	if self.server.debug {
+	if self.server.getLimits().debug {
 		log.Debugw("conn wait group exit", "error", err)
 	}
 	atomic.AddInt64(&self.server.Stat.ActiveSessionCount, -1)
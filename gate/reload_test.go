@@ -0,0 +1,26 @@
+package gate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+)
+
+func TestReload(t *testing.T) {
+	server := newDefaultServer()
+	WithHeartbeatInterval(10 * time.Second)(server)
+	WithSendBacklog(8)(server)
+	// 选项和Reload都不能修改已经取出的limits
+	limits := server.getLimits()
+	WithDebugMode(true)(server)
+	server.Reload(gira.GatewayConfig{Heartbeat: 20 * time.Second, RecvBacklog: 32})
+	if limits.debug || limits.heartbeat != 10*time.Second || limits.recvBacklog != 16 {
+		t.Fatalf("stored limits modified %+v", *limits)
+	}
+	limits = server.getLimits()
+	// Reload中为0的参数保持不变, debug总是替换
+	if limits.debug || limits.heartbeat != 20*time.Second || limits.sendBacklog != 8 || limits.recvBacklog != 32 || limits.handshakeTimeout != 2*time.Second {
+		t.Fatalf("unexpected limits %+v", *limits)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/Lyndon-Zhang/gira/facade"
 	"github.com/Lyndon-Zhang/gira/gate/packet"
 	"github.com/Lyndon-Zhang/gira/gate/ws"
+	"github.com/Lyndon-Zhang/gira/graceful"
 	"github.com/Lyndon-Zhang/gira/log"
 	"golang.org/x/sync/errgroup"
 )
@@ -53,16 +54,11 @@ type Server struct {
 	tslCertificate     string
 	tslKey             string
 	handshakeValidator func([]byte) error
-	heartbeat          time.Duration
 	checkOrigin        func(*http.Request) bool
-	debug              bool
 	wsPath             string
 	rsaPrivateKey      string
 	sessionModifer     uint64
-	handshakeTimeout   time.Duration
-	sendBacklog        int
-	recvBacklog        int
-	recvBuffSize       int
+	limits             atomic.Value // *server_limits
 	state              int32
 	mu                 sync.RWMutex
 	sessions           map[uint64]*Session
@@ -73,6 +69,16 @@ type Server struct {
 	Stat               Stat
 }
 
+// 可以在运行时修改的参数, 修改时整体替换
+type server_limits struct {
+	debug            bool
+	heartbeat        time.Duration
+	handshakeTimeout time.Duration
+	sendBacklog      int
+	recvBacklog      int
+	recvBuffSize     int
+}
+
 type Stat struct {
 	ActiveSessionCount        int64 // 当前会话数量
 	CumulativeSessionCount    int64 // 累计会话数量
@@ -84,17 +90,19 @@ type Stat struct {
 func newDefaultServer() *Server {
 	gate := &Server{
 		state:              server_status_start,
-		heartbeat:          30 * time.Second,
-		debug:              false,
 		checkOrigin:        func(_ *http.Request) bool { return true },
 		handshakeValidator: func(_ []byte) error { return nil },
 		middlewareArr:      make([]MiddleWareInterface, 0),
 		sessions:           map[uint64]*Session{},
-		handshakeTimeout:   2 * time.Second,
-		sendBacklog:        16,
-		recvBacklog:        16,
-		recvBuffSize:       4096,
 	}
+	gate.limits.Store(&server_limits{
+		heartbeat:        30 * time.Second,
+		debug:            false,
+		handshakeTimeout: 2 * time.Second,
+		sendBacklog:      16,
+		recvBacklog:      16,
+		recvBuffSize:     4096,
+	})
 	return gate
 }
 
@@ -122,6 +130,42 @@ func NewConfigServer(ctx context.Context, config gira.GatewayConfig) (*Server, e
 	return server, nil
 }
 
+func (server *Server) getLimits() *server_limits {
+	return server.limits.Load().(*server_limits)
+}
+
+// 复制一份修改后整体替换, 已经保存的不会被修改
+func (server *Server) setLimits(f func(limits *server_limits)) {
+	limits := *server.getLimits()
+	f(&limits)
+	server.limits.Store(&limits)
+}
+
+// 修改运行时参数, debug立即生效, 其他的只影响之后建立的连接
+// 为0的参数保持不变
+func (server *Server) Reload(config gira.GatewayConfig) {
+	limits := *server.getLimits()
+	limits.debug = config.Debug
+	if config.Heartbeat > 0 {
+		limits.heartbeat = config.Heartbeat
+	}
+	if config.HandshakeTimeout > 0 {
+		limits.handshakeTimeout = config.HandshakeTimeout
+	}
+	if config.SendBacklog > 0 {
+		limits.sendBacklog = config.SendBacklog
+	}
+	if config.RecvBacklog > 0 {
+		limits.recvBacklog = config.RecvBacklog
+	}
+	if config.RecvBuffSize > 0 {
+		limits.recvBuffSize = config.RecvBuffSize
+	}
+	server.limits.Store(&limits)
+	corelog.Infow("gateway reload", "debug", limits.debug, "heartbeat", limits.heartbeat, "handshake_timeout", limits.handshakeTimeout,
+		"send_backlog", limits.sendBacklog, "recv_backlog", limits.recvBacklog, "recv_buff_size", limits.recvBuffSize)
+}
+
 type Option func(gateway *Server)
 
 func WithSessionModifer(v uint64) Option {
@@ -138,7 +182,9 @@ func WithHandshakeValidator(fn func([]byte) error) Option {
 
 func WithDebugMode(v bool) Option {
 	return func(server *Server) {
-		server.debug = v
+		server.setLimits(func(limits *server_limits) {
+			limits.debug = v
+		})
 	}
 }
 
@@ -150,31 +196,41 @@ func WithCheckOriginFunc(fn func(*http.Request) bool) Option {
 
 func WithHeartbeatInterval(d time.Duration) Option {
 	return func(server *Server) {
-		server.heartbeat = d
+		server.setLimits(func(limits *server_limits) {
+			limits.heartbeat = d
+		})
 	}
 }
 
 func WithHandshakeTimeout(d time.Duration) Option {
 	return func(server *Server) {
-		server.handshakeTimeout = d
+		server.setLimits(func(limits *server_limits) {
+			limits.handshakeTimeout = d
+		})
 	}
 }
 
 func WithRecvBuffSize(v int) Option {
 	return func(server *Server) {
-		server.recvBuffSize = v
+		server.setLimits(func(limits *server_limits) {
+			limits.recvBuffSize = v
+		})
 	}
 }
 
 func WithSendBacklog(v int) Option {
 	return func(server *Server) {
-		server.sendBacklog = v
+		server.setLimits(func(limits *server_limits) {
+			limits.sendBacklog = v
+		})
 	}
 }
 
 func WithRecvBacklog(v int) Option {
 	return func(server *Server) {
-		server.recvBacklog = v
+		server.setLimits(func(limits *server_limits) {
+			limits.recvBacklog = v
+		})
 	}
 }
 
@@ -275,6 +331,27 @@ func (server *Server) Maintain(m bool) {
 	}
 }
 
+// 关闭listener, 不再接收新的连接, 等待已有的会话结束
+// ctx结束时返回, 剩下的会话由Shutdown踢掉
+func (server *Server) Drain(ctx context.Context) {
+	server.Maintain(true)
+	if server.httpServer != nil {
+		server.httpServer.Shutdown(ctx)
+	} else if server.listener != nil {
+		server.listener.Close()
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&server.Stat.ActiveSessionCount) > 0 {
+		select {
+		case <-ctx.Done():
+			corelog.Infow("gate drain timeout", "count", atomic.LoadInt64(&server.Stat.ActiveSessionCount))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (server *Server) Shutdown() {
 	if server.status() == server_status_closed {
 		return
@@ -291,7 +368,7 @@ func (server *Server) Shutdown() {
 	server.Kick("shutdown")
 	server.cancelFunc()
 	err := server.errGroup.Wait()
-	if server.debug {
+	if server.getLimits().debug {
 		corelog.Debugw("gate shutdown", "error", err)
 	}
 }
@@ -324,12 +401,24 @@ func (server *Server) Kick(reason string) {
 	}
 }
 
-func (server *Server) listenAndServe() error {
-	listener, err := net.Listen("tcp", server.BindAddr)
+// 绑定端口, 在Serve之前调用时端口会提前绑定, 没有调用时由Serve绑定
+func (server *Server) Bind() error {
+	if server.listener != nil {
+		return nil
+	}
+	listener, err := graceful.Listen("gate", "tcp", server.BindAddr)
 	if err != nil {
 		return err
 	}
 	server.listener = listener
+	return nil
+}
+
+func (server *Server) listenAndServe() error {
+	if err := server.Bind(); err != nil {
+		return err
+	}
+	listener := server.listener
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
@@ -353,9 +442,12 @@ func (server *Server) listenAndServeWS() error {
 		}
 		server.serveWsConn(conn)
 	})
+	if err := server.Bind(); err != nil {
+		return err
+	}
 	httpServer := &http.Server{Addr: server.BindAddr}
 	server.httpServer = httpServer
-	if err := httpServer.ListenAndServe(); err == http.ErrServerClosed {
+	if err := httpServer.Serve(server.listener); err == http.ErrServerClosed {
 		return nil
 	} else if err != nil {
 		return err
@@ -378,9 +470,12 @@ func (server *Server) listenAndServeWSTLS() error {
 		}
 		server.serveWsConn(conn)
 	})
+	if err := server.Bind(); err != nil {
+		return err
+	}
 	httpServer := &http.Server{Addr: server.BindAddr}
 	server.httpServer = httpServer
-	if err := httpServer.ListenAndServeTLS(server.tslCertificate, server.tslKey); err == http.ErrServerClosed {
+	if err := httpServer.ServeTLS(server.listener, server.tslCertificate, server.tslKey); err == http.ErrServerClosed {
 		return nil
 	} else if err != nil {
 		return err
@@ -401,11 +496,11 @@ func (server *Server) handleConn(conn net.Conn) {
 		atomic.AddInt64(&server.Stat.ActiveConnectionCount, -1)
 	}()
 	c := newConn(server)
-	if server.debug {
+	if server.getLimits().debug {
 		corelog.Debugw("accept a conn", "session_id", c.session.Id(), "remote_addr", conn.RemoteAddr())
 	}
 	err := c.serve(server.ctx, conn)
-	if server.debug {
+	if server.getLimits().debug {
 		corelog.Debugw("conn serve exit", "session_id", c.session.Id(), "error", err)
 	}
 }
@@ -189,6 +189,15 @@ func Use(ctx context.Context, client gira.DbClient, config gira.BehaviorConfig)
 	}
 }
 
+// 按配置中的名字使用数据库
+func UseByName(ctx context.Context, name string, config gira.BehaviorConfig) error {
+	client := facade.GetDbClient(name)
+	if client == nil {
+		return errors.ErrDbClientNotFound
+	}
+	return Use(ctx, client, config)
+}
+
 func UseMongo(ctx context.Context, client gira.MongoClient, config gira.BehaviorConfig) error {
 	if globalDao != nil {
 		return errors.ErrTODO
@@ -241,7 +250,8 @@ func (self *<<.MongoDaoStructName>>) Migrate(ctx context.Context, opts ...behavi
 }
 
 func (self *<<.MongoDaoStructName>>) Serve(ctx context.Context, config gira.BehaviorConfig) (err error) {
-	ticker := time.NewTicker(time.Duration(config.SyncInterval)*time.Second)
+	interval := config.SyncInterval
+	ticker := time.NewTicker(time.Duration(interval)*time.Second)
 	defer func() {
 		ticker.Stop()
 	}()
@@ -256,6 +266,11 @@ func (self *<<.MongoDaoStructName>>) Serve(ctx context.Context, config gira.Beha
 			return nil
 		case <-ticker.C:
 			self.Sync(ctx, opts...)
+			// 配置重新加载后使用新的同步间隔
+			if c := facade.GetConfig(); c != nil && c.Module.Behavior != nil && c.Module.Behavior.SyncInterval > 0 && c.Module.Behavior.SyncInterval != interval {
+				interval = c.Module.Behavior.SyncInterval
+				ticker.Reset(time.Duration(interval)*time.Second)
+			}
 		}
 	}
 }
@@ -0,0 +1,381 @@
+package gen_macro
+
+///
+/// @actor 注解
+///
+/// 在方法前加上 // @actor(timeout=3s, priority=high), 生成
+///   - Call<方法名>(ctx, ..., opts ...actor.CallOption): 在actor的协程中执行并等待结果, 需要第一个参数是context.Context, 最后一个返回值是error
+///   - Cast<方法名>(..., opts ...actor.CastOption) error: 不等待结果, 返回值被忽略, 收件箱满或者actor已经停止时返回错误
+/// 参数
+///   - call, cast: 只生成其中一种, 默认都生成, 不满足Call的条件时只生成Cast
+///   - timeout: Call的默认超时时间, 可以被调用时的选项覆盖
+///   - priority: normal|high, 投递到哪个收件箱
+/// 接收者需要嵌入*actor.Actor
+/// 每个源文件第一次生成时会同时生成 <文件名>_actor_test.go 测试框架, 已经存在时不会覆盖
+/// 测试框架为每个方法生成一个表驱动的测试, 创建actor后调用Call和Cast
+///
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+)
+
+type ActorMacroGenerator struct {
+}
+
+type actor_macro_state struct {
+	Method      *Method
+	Call        bool
+	Cast        bool
+	Params      string // 方法的参数, 后面加上了逗号
+	CallOptions string
+	CastOptions string
+}
+
+func (self *ActorMacroGenerator) Check(macro *MacroFunc, method *Method) error {
+	_, err := self.parse(macro, method)
+	return err
+}
+
+func (self *ActorMacroGenerator) parse(macro *MacroFunc, method *Method) (*actor_macro_state, error) {
+	state := &actor_macro_state{
+		Method: method,
+	}
+	// Call和Cast的最后一个参数是选项
+	for _, arg := range method.Args {
+		if strings.HasPrefix(arg.Type, "...") {
+			return nil, fmt.Errorf("@actor variadic argument not supported, %s", method.Declaration)
+		} else if arg.Name == "opts" {
+			return nil, fmt.Errorf("@actor argument name opts is reserved, %s", method.Declaration)
+		}
+	}
+	callable := len(method.Args) > 0 && method.Arg0.Type == "context.Context" && len(method.Returns) > 0 && method.ReturnsTail.Type == "error"
+	callOptions := make([]string, 0)
+	castOptions := make([]string, 0)
+	for _, arg := range macro.Args {
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		switch arg {
+		case "call":
+			state.Call = true
+		case "cast":
+			state.Cast = true
+		default:
+			return nil, fmt.Errorf("@actor unknown argument %s, %s", arg, method.Declaration)
+		}
+	}
+	if !state.Call && !state.Cast {
+		state.Call = callable
+		state.Cast = true
+	} else if state.Call && !callable {
+		return nil, fmt.Errorf("@actor(call) first argument must context.Context and last return type must error, %s", method.Declaration)
+	}
+	keys := make([]string, 0, len(macro.Options))
+	for k := range macro.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := macro.Options[k]
+		switch k {
+		case "timeout":
+			if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+				return nil, fmt.Errorf("@actor invalid timeout %s, %s", v, method.Declaration)
+			} else {
+				callOptions = append(callOptions, fmt.Sprintf("actor.WithCallTimeOut(%d /* %s */)", int64(d), v))
+			}
+		case "priority":
+			switch v {
+			case "normal":
+			case "high":
+				callOptions = append(callOptions, "actor.WithCallPriority(actor.PRIORITY_HIGH)")
+				castOptions = append(castOptions, "actor.WithCastPriority(actor.PRIORITY_HIGH)")
+			default:
+				return nil, fmt.Errorf("@actor priority must normal or high, %s", method.Declaration)
+			}
+		default:
+			return nil, fmt.Errorf("@actor unknown option %s, %s", k, method.Declaration)
+		}
+	}
+	if params := JoinArgsWithType(method.Args).(string); len(params) > 0 {
+		state.Params = params + ", "
+	}
+	state.CallOptions = strings.Join(callOptions, ", ")
+	state.CastOptions = strings.Join(castOptions, ", ")
+	return state, nil
+}
+
+func (self *ActorMacroGenerator) Gen(macro *MacroFunc, method *Method, sb *strings.Builder) {
+	state, err := self.parse(macro, method)
+	if err != nil {
+		log.Info(err)
+		return
+	}
+	funcMap := template.FuncMap{
+		"join_args":           JoinArgs,
+		"join_args_with_type": JoinArgsWithType,
+	}
+	code := `
+<<- if .Call>>
+
+// 在actor的协程中执行<<.Method.MethodName>>并等待结果
+func (<<.Method.ReceiverName>> <<.Method.ReceiverPtr>><<.Method.ReceiverType>>) Call<<.Method.MethodName>>(<<.Params>>opts ...actor.CallOption) (<<join_args_with_type .Method.Returns>>) {
+	<<- if .CallOptions>>
+	opts = append([]actor.CallOption{<<.CallOptions>>}, opts...)
+	<<- end>>
+	<<- if .Method.ReturnsHead>>
+	type __result__ struct {
+		<<- range .Method.ReturnsHead>>
+		<<.Name>> <<.Ptr>><<.Type>>
+		<<- end>>
+	}
+	var __r__ __result__
+	__r__, <<.Method.ReturnsTail.Name>> = actor.Call(<<.Method.Arg0.Name>>, <<.Method.ReceiverName>>, func() (__result__, error) {
+		var __r__ __result__
+		var __err__ error
+		<<range .Method.ReturnsHead>>__r__.<<.Name>>, <<end>>__err__ = <<.Method.ReceiverName>>.<<.Method.MethodName>>(<<join_args "" .Method.Args>>)
+		return __r__, __err__
+	}, opts...)
+	return <<range .Method.ReturnsHead>>__r__.<<.Name>>, <<end>><<.Method.ReturnsTail.Name>>
+	<<- else>>
+	_, <<.Method.ReturnsTail.Name>> = actor.Call(<<.Method.Arg0.Name>>, <<.Method.ReceiverName>>, func() (struct{}, error) {
+		return struct{}{}, <<.Method.ReceiverName>>.<<.Method.MethodName>>(<<join_args "" .Method.Args>>)
+	}, opts...)
+	return <<.Method.ReturnsTail.Name>>
+	<<- end>>
+}
+<<- end>>
+<<- if .Cast>>
+
+// 投递到actor的协程中执行<<.Method.MethodName>>, 不等待结果
+func (<<.Method.ReceiverName>> <<.Method.ReceiverPtr>><<.Method.ReceiverType>>) Cast<<.Method.MethodName>>(<<.Params>>opts ...actor.CastOption) error {
+	<<- if .CastOptions>>
+	opts = append([]actor.CastOption{<<.CastOptions>>}, opts...)
+	<<- end>>
+	return <<.Method.ReceiverName>>.Cast(func() {
+		<<.Method.ReceiverName>>.<<.Method.MethodName>>(<<join_args "" .Method.Args>>)
+	}, opts...)
+}
+<<- end>>
+`
+	tmpl := template.New("actor").Delims("<<", ">>")
+	tmpl.Funcs(funcMap)
+	if tmpl, err := tmpl.Parse(code); err != nil {
+		log.Info(err)
+		return
+	} else if err := tmpl.Execute(sb, state); err != nil {
+		log.Info(err)
+		return
+	}
+}
+
+var actorTestCode = `package <<.Package>>
+
+/// gen_macro生成的测试框架, 文件已经存在时不会覆盖
+/// 在tests中补充参数和期望的结果
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/actor"
+	<<- range .Imports>>
+	<<.>>
+	<<- end>>
+)
+<<- range .Types>>
+
+// 创建测试用的<<.>>, 需要初始化其他字段时在这里补充
+func new<<.>>ForTest() *<<.>> {
+	return &<<.>>{Actor: actor.NewActor(16)}
+}
+<<- end>>
+<<- range .States>>
+
+func Test<<.Method.ReceiverType>>_<<.Method.MethodName>>(t *testing.T) {
+	type args struct {
+		<<- range .Method.Args>>
+		<<- if ne .Type "context.Context">>
+		<<.Name>> <<.Ptr>><<.Type>>
+		<<- end>>
+		<<- end>>
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{name: "zero value"},
+	}
+	receiver := new<<.Method.ReceiverType>>ForTest()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	go receiver.Serve(ctx)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			<<- if .Call>>
+			<<range .Method.ReturnsHead>>_, <<end>>err := receiver.Call<<.Method.MethodName>>(<<test_call_args .Method.Args>>)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Call<<.Method.MethodName>> error = %v, wantErr %v", err, tt.wantErr)
+			}
+			<<- end>>
+			<<- if .Cast>>
+			if err := receiver.Cast<<.Method.MethodName>>(<<test_call_args .Method.Args>>); err != nil {
+				t.Fatalf("Cast<<.Method.MethodName>> error = %v", err)
+			}
+			<<- end>>
+		})
+	}
+}
+<<- end>>
+`
+
+// 调用Call和Cast的参数, context.Context使用测试的ctx, 其他的来自测试表
+func testCallArgs(args []*Arg) string {
+	params := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg.Type == "context.Context" {
+			params = append(params, "ctx")
+		} else {
+			params = append(params, "tt.args."+arg.Name)
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+var reQualifier = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.`)
+
+// 测试表中的参数用到的其他包, 从源文件的import中查找
+func testImports(path string, states []*actor_macro_state) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	specs := make(map[string]string)
+	for _, spec := range f.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		// 测试框架已经导入了
+		switch importPath {
+		case "context", "testing", "time", "github.com/Lyndon-Zhang/gira/actor":
+			continue
+		}
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		line := spec.Path.Value
+		if spec.Name != nil {
+			name = spec.Name.Name
+			line = name + " " + line
+		}
+		specs[name] = line
+	}
+	used := make(map[string]struct{})
+	imports := make([]string, 0)
+	for _, state := range states {
+		for _, arg := range state.Method.Args {
+			if arg.Type == "context.Context" {
+				continue
+			}
+			for _, matches := range reQualifier.FindAllStringSubmatch(arg.Type, -1) {
+				line, ok := specs[matches[1]]
+				if _, dup := used[matches[1]]; ok && !dup {
+					used[matches[1]] = struct{}{}
+					imports = append(imports, line)
+				}
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// 为包含@actor的源文件生成测试框架
+func genActorTests(fileMacros map[string][]*Macro) error {
+	generator := &ActorMacroGenerator{}
+	files := make(map[string][]*actor_macro_state)
+	packages := make(map[string]string)
+	for _, macros := range fileMacros {
+		for _, macro := range macros {
+			if macro.Method == nil {
+				continue
+			}
+			for _, f := range macro.MacroFuncs {
+				if f.Name != "@actor" {
+					continue
+				}
+				if state, err := generator.parse(f, macro.Method); err != nil {
+					return err
+				} else {
+					files[macro.FilePath] = append(files[macro.FilePath], state)
+					packages[macro.FilePath] = macro.Package
+				}
+			}
+		}
+	}
+	for path, states := range files {
+		testFilePath := strings.TrimSuffix(path, ".go") + "_actor_test.go"
+		if _, err := os.Stat(testFilePath); err == nil {
+			continue
+		}
+		imports, err := testImports(path, states)
+		if err != nil {
+			return err
+		}
+		data, err := genActorTest(packages[path], imports, states)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(testFilePath, data, 0644); err != nil {
+			return err
+		}
+		log.Infow("gen actor test", "path", testFilePath)
+	}
+	return nil
+}
+
+// 生成一个源文件的测试框架, 返回格式化后的代码
+func genActorTest(packageName string, imports []string, states []*actor_macro_state) ([]byte, error) {
+	types := make([]string, 0)
+	for _, state := range states {
+		if !contains(types, state.Method.ReceiverType) {
+			types = append(types, state.Method.ReceiverType)
+		}
+	}
+	funcMap := template.FuncMap{
+		"test_call_args": testCallArgs,
+	}
+	tmpl, err := template.New("actor_test").Delims("<<", ">>").Funcs(funcMap).Parse(actorTestCode)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{
+		"Package": packageName,
+		"Imports": imports,
+		"Types":   types,
+		"States":  states,
+	}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func contains(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
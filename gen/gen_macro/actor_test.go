@@ -0,0 +1,122 @@
+package gen_macro
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestMethod(args string, returns string) *Method {
+	method := &Method{
+		Declaration:  "func (p *Player) Test(" + args + ") (" + returns + ")",
+		ReceiverName: "p",
+		ReceiverPtr:  "*",
+		ReceiverType: "Player",
+		MethodName:   "Test",
+		Args:         parseArgs(args),
+		Returns:      parseReturns(returns),
+	}
+	if len(method.Args) > 0 {
+		method.Arg0 = method.Args[0]
+	}
+	if len(method.Returns) > 0 {
+		method.ReturnsHead = method.Returns[:len(method.Returns)-1]
+		method.ReturnsTail = method.Returns[len(method.Returns)-1]
+	}
+	return method
+}
+
+func TestActorParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		options     map[string]string
+		params      string
+		returns     string
+		call        bool
+		cast        bool
+		callOptions string
+		castOptions string
+		err         string
+	}{
+		{name: "default", params: "ctx context.Context, gold int64", returns: "int64, error", call: true, cast: true},
+		{name: "not callable", params: "gold int64", returns: "", cast: true},
+		{name: "no error return", params: "ctx context.Context", returns: "int64", cast: true},
+		{name: "cast only", args: []string{"cast"}, params: "ctx context.Context", returns: "error", cast: true},
+		{name: "call only", args: []string{"call"}, params: "ctx context.Context", returns: "error", call: true},
+		{name: "call not callable", args: []string{"call"}, params: "gold int64", returns: "error", err: "@actor(call)"},
+		{name: "unknown argument", args: []string{"send"}, params: "ctx context.Context", returns: "error", err: "unknown argument send"},
+		{
+			name:        "options",
+			args:        []string{"timeout=3s", "priority=high"},
+			options:     map[string]string{"timeout": "3s", "priority": "high"},
+			params:      "ctx context.Context",
+			returns:     "error",
+			call:        true,
+			cast:        true,
+			callOptions: "actor.WithCallPriority(actor.PRIORITY_HIGH), actor.WithCallTimeOut(3000000000 /* 3s */)",
+			castOptions: "actor.WithCastPriority(actor.PRIORITY_HIGH)",
+		},
+		{name: "normal priority", options: map[string]string{"priority": "normal"}, params: "ctx context.Context", returns: "error", call: true, cast: true},
+		{name: "invalid timeout", options: map[string]string{"timeout": "3"}, params: "ctx context.Context", returns: "error", err: "invalid timeout"},
+		{name: "negative timeout", options: map[string]string{"timeout": "-1s"}, params: "ctx context.Context", returns: "error", err: "invalid timeout"},
+		{name: "invalid priority", options: map[string]string{"priority": "low"}, params: "ctx context.Context", returns: "error", err: "priority"},
+		{name: "unknown option", options: map[string]string{"retry": "3"}, params: "ctx context.Context", returns: "error", err: "unknown option retry"},
+		{name: "variadic", params: "ctx context.Context, tags ...string", returns: "error", err: "variadic"},
+		{name: "reserved name", params: "ctx context.Context, opts int", returns: "error", err: "reserved"},
+	}
+	generator := &ActorMacroGenerator{}
+	for _, tt := range tests {
+		macro := &MacroFunc{Name: "@actor", Args: tt.args, Options: tt.options}
+		state, err := generator.parse(macro, newTestMethod(tt.params, tt.returns))
+		if len(tt.err) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: expected error %s, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if state.Call != tt.call || state.Cast != tt.cast {
+			t.Errorf("%s: expected call %v cast %v, got %v %v", tt.name, tt.call, tt.cast, state.Call, state.Cast)
+		}
+		if state.CallOptions != tt.callOptions || state.CastOptions != tt.castOptions {
+			t.Errorf("%s: unexpected options %q %q", tt.name, state.CallOptions, state.CastOptions)
+		}
+	}
+}
+
+func TestGenActorTest(t *testing.T) {
+	generator := &ActorMacroGenerator{}
+	call, err := generator.parse(&MacroFunc{}, newTestMethod("ctx context.Context, gold int64, where *service_options.WhereOptions", "int64, error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kick := newTestMethod("reason string", "")
+	kick.MethodName = "Kick"
+	cast, err := generator.parse(&MacroFunc{Args: []string{"cast"}}, kick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 格式化时会检查语法
+	data, err := genActorTest("hall", []string{`"github.com/Lyndon-Zhang/gira/options/service_options"`}, []*actor_macro_state{call, cast})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(data)
+	for _, s := range []string{
+		"func newPlayerForTest() *Player",
+		"where *service_options.WhereOptions",
+		"_, err := receiver.CallTest(ctx, tt.args.gold, tt.args.where)",
+		"receiver.CastTest(ctx, tt.args.gold, tt.args.where)",
+		"receiver.CastKick(tt.args.reason)",
+	} {
+		if !strings.Contains(code, s) {
+			t.Errorf("expected %q in\n%s", s, code)
+		}
+	}
+	if strings.Count(code, "func newPlayerForTest") != 1 || strings.Contains(code, "t.Skip") {
+		t.Errorf("unexpected code\n%s", code)
+	}
+}
@@ -13,8 +13,6 @@ import (
 	"text/template"
 
 	log "github.com/Lyndon-Zhang/gira/corelog"
-
-	"github.com/Lyndon-Zhang/gira/proj"
 )
 
 /// 每个目录生成一个文件
@@ -46,6 +44,7 @@ type Method struct {
 type Macro struct {
 	file       *os.File
 	FilePath   string    // 所有的文件
+	Package    string    // 文件的包名
 	Type       MacroType //类型
 	MacroFuncs []*MacroFunc
 	Method     *Method
@@ -60,19 +59,21 @@ type MacroFunc struct {
 	Arg2 string
 	Arg3 string
 	Arg4 string
+	// key=value 形式的参数
+	Options map[string]string
 }
 
 func scanDirFiles(config *Config) map[string][]string {
 	dirArr := make([]string, 0)
-	dirArr = append(dirArr, proj.Dir.SrcDir)
+	dirArr = append(dirArr, config.SrcDir)
 	if config.SrcDirs != nil {
 		dirArr = append(dirArr, config.SrcDirs...)
 	}
 	files := make(map[string][]string, 0)
 	for _, dir := range dirArr {
 		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
-			if strings.HasPrefix(path, proj.Dir.SrcGenDir) {
-			} else if strings.HasPrefix(path, proj.Dir.SrcTestDir) {
+			if len(config.SrcGenDir) > 0 && strings.HasPrefix(path, config.SrcGenDir) {
+			} else if len(config.SrcTestDir) > 0 && strings.HasPrefix(path, config.SrcTestDir) {
 			} else if !d.IsDir() && strings.HasSuffix(d.Name(), "_test.go") {
 			} else if !d.IsDir() && strings.HasSuffix(d.Name(), "_macro.go") {
 			} else if !d.IsDir() && strings.HasSuffix(d.Name(), ".macro.go") {
@@ -153,6 +154,9 @@ func parseArgs(line string) []*Arg {
 func scanMacros(files map[string][]string) (map[string][]*Macro, error) {
 	results := make(map[string][]*Macro, 0)
 	reMacroFunc := regexp.MustCompile(`// #(\w+)\(([^)]*)\)`)
+	// 注解形式, 参数可以省略, 例如 // @actor(timeout=3s, priority=high)
+	reAnnotation := regexp.MustCompile(`^// (@actor)\b(?:\(([^)]*)\))?`)
+	rePackage := regexp.MustCompile(`^package\s+(\w+)`)
 	reFunc := regexp.MustCompile(`^func\s+\(([a-zA-Z0-9_]+)\s+([*]*)([a-zA-Z0-9_]+)\)\s+([a-zA-Z0-9_]+)\((.*?)\)\s*[\(]*(.*?)[\)]*\s*\{$`)
 	for dir, arr := range files {
 		for _, path := range arr {
@@ -171,13 +175,24 @@ func scanMacros(files map[string][]string) (map[string][]*Macro, error) {
 				}
 				lines = append(lines, string(line))
 			}
+			var packageName string
+			for _, v := range lines {
+				if matches := rePackage.FindStringSubmatch(v); len(matches) > 0 {
+					packageName = matches[1]
+					break
+				}
+			}
 			macro := &Macro{
 				FilePath: path,
+				Package:  packageName,
 			}
 			for _, v := range lines {
-				if strings.HasPrefix(v, "// #") {
+				if strings.HasPrefix(v, "// #") || reAnnotation.MatchString(v) {
 					// log.Info(v)
 					matches := reMacroFunc.FindStringSubmatch(v)
+					if len(matches) <= 0 {
+						matches = reAnnotation.FindStringSubmatch(v)
+					}
 					if len(matches) > 0 {
 						macroName := matches[1]
 						args := matches[2]
@@ -242,6 +257,7 @@ func scanMacros(files map[string][]string) (map[string][]*Macro, error) {
 						}
 						macro = &Macro{
 							FilePath: path,
+							Package:  packageName,
 						}
 					}
 				}
@@ -258,8 +274,12 @@ type GenState struct {
 var code = `// afafa
 `
 
+// 目录由调用方传入, 一般来自proj.Dir
 type Config struct {
-	SrcDirs []string
+	SrcDir     string // src/
+	SrcGenDir  string // src/gen/, 不扫描
+	SrcTestDir string // src/test/, 不扫描
+	SrcDirs    []string
 }
 
 func Gen(config *Config) error {
@@ -291,6 +311,13 @@ func Gen(config *Config) error {
 					}
 				}
 				for _, m := range macro.MacroFuncs {
+					m.Options = make(map[string]string)
+					for k, v := range m.Args {
+						m.Args[k] = strings.TrimSpace(v)
+						if kv := strings.SplitN(m.Args[k], "=", 2); len(kv) == 2 {
+							m.Options[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+						}
+					}
 					if len(m.Args) > 0 {
 						m.Arg0 = m.Args[0]
 					}
@@ -399,6 +426,9 @@ func Gen(config *Config) error {
 			f.WriteString(`
 /// =============宏展开的地方，不要在文件末尾添加代码============`)
 		}
+		if err := genActorTests(fileMacros); err != nil {
+			return err
+		}
 	}
 	log.Info("===============gen macro finished===============")
 	return nil
@@ -600,6 +630,7 @@ func (<<.Method.ReceiverName>> <<.Method.ReceiverPtr>><<.Method.ReceiverType>>)
 
 var builders = map[MacroType]map[string]MacroGenerator{
 	MacroTypeMethod: {
-		"actor": &MethodMacroGenerator{},
+		"actor":  &MethodMacroGenerator{},
+		"@actor": &ActorMacroGenerator{},
 	},
 }
@@ -123,6 +123,7 @@ import (
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/db"
 	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/facade"
 	"github.com/Lyndon-Zhang/gira/corelog"
 	"context"
 	"encoding/json"
@@ -151,6 +152,15 @@ func Use(ctx context.Context, client gira.DbClient) (<<.DaoInterfaceName>>, erro
 	}
 }
 
+// 按配置中的名字使用数据库, 例如 gamedb, gamedb_1
+func UseByName(ctx context.Context, name string) (<<.DaoInterfaceName>>, error) {
+	client := facade.GetDbClient(name)
+	if client == nil {
+		return nil, errors.ErrDbClientNotFound
+	}
+	return Use(ctx, client)
+}
+
 func NewMongoDao() *<<.MongoDaoStructName>> {
 	self := &<<.MongoDaoStructName>>{}
 	<<- range .CollectionArr>> 
@@ -0,0 +1,129 @@
+package gins
+
+///
+/// http探针, 给编排系统检查进程状态
+///
+///   /healthz 存活, 进程还能处理http请求就返回200
+///   /readyz  就绪, 启动完成并且全部检查通过时返回200, 否则返回503
+///
+/// 启动完成指OnStart返回, 节点已经注册到注册表, 资源已经加载
+/// 零停机重启排空会话和停止时变成没有就绪
+///
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+)
+
+const (
+	HEALTHZ_PATH = "/healthz"
+	READYZ_PATH  = "/readyz"
+)
+
+type probe_check struct {
+	name  string
+	check gira.ReadinessCheckFunc
+}
+
+type Probe struct {
+	ready   int32
+	timeout time.Duration // 每次检查的超时时间
+	mu      sync.Mutex
+	checks  []*probe_check
+}
+
+func NewProbe(timeout time.Duration) *Probe {
+	return &Probe{
+		timeout: timeout,
+	}
+}
+
+func (self *Probe) SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&self.ready, 1)
+	} else {
+		atomic.StoreInt32(&self.ready, 0)
+	}
+}
+
+func (self *Probe) IsReady() bool {
+	return atomic.LoadInt32(&self.ready) == 1
+}
+
+// 增加就绪检查, 同名的检查会被替换
+func (self *Probe) AddCheck(name string, check gira.ReadinessCheckFunc) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for _, v := range self.checks {
+		if v.name == name {
+			v.check = check
+			return
+		}
+	}
+	self.checks = append(self.checks, &probe_check{name: name, check: check})
+}
+
+// 按增加的顺序检查, 返回每个检查的结果和是否全部通过
+func (self *Probe) Check(ctx context.Context) (lines []string, ok bool) {
+	ok = self.IsReady()
+	if ok {
+		lines = append(lines, "[+]lifecycle ok")
+	} else {
+		lines = append(lines, "[-]lifecycle not ready")
+	}
+	self.mu.Lock()
+	checks := make([]*probe_check, len(self.checks))
+	copy(checks, self.checks)
+	self.mu.Unlock()
+	for _, v := range checks {
+		checkCtx, cancelFunc := context.WithTimeout(ctx, self.timeout)
+		err := v.check(checkCtx)
+		cancelFunc()
+		if err != nil {
+			ok = false
+			lines = append(lines, fmt.Sprintf("[-]%s failed: %v", v.name, err))
+		} else {
+			lines = append(lines, fmt.Sprintf("[+]%s ok", v.name))
+		}
+	}
+	return
+}
+
+func (self *Probe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	switch r.URL.Path {
+	case HEALTHZ_PATH:
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	case READYZ_PATH:
+		lines, ok := self.Check(r.Context())
+		if ok {
+			w.WriteHeader(http.StatusOK)
+			lines = append(lines, "readyz check passed")
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			lines = append(lines, "readyz check failed")
+		}
+		fmt.Fprintln(w, strings.Join(lines, "\n"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// 探针的路径由Probe处理, 其他的交给handler
+func (self *Probe) Wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == HEALTHZ_PATH || r.URL.Path == READYZ_PATH {
+			self.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
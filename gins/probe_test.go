@@ -0,0 +1,69 @@
+package gins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbe(t *testing.T) {
+	probe := NewProbe(10 * time.Millisecond)
+	var dbErr error
+	probe.AddCheck("db", func(ctx context.Context) error {
+		return dbErr
+	})
+	// 超时的检查算失败
+	probe.AddCheck("registry", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	handler := probe.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code, w.Body.String()
+	}
+	if code, body := get(HEALTHZ_PATH); code != http.StatusOK || body != "ok\n" {
+		t.Fatalf("healthz: unexpected %d %q", code, body)
+	}
+	if code, _ := get("/login"); code != http.StatusTeapot {
+		t.Fatalf("expected other path passed to handler, got %d", code)
+	}
+	code, body := get(READYZ_PATH)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready before start, got %d", code)
+	}
+	for _, line := range []string{"[-]lifecycle not ready", "[+]db ok", "[-]registry failed: context deadline exceeded", "readyz check failed"} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected %q in %q", line, body)
+		}
+	}
+
+	probe.SetReady(true)
+	// 同名的检查被替换
+	probe.AddCheck("registry", func(ctx context.Context) error {
+		return nil
+	})
+	if code, body := get(READYZ_PATH); code != http.StatusOK || body != "[+]lifecycle ok\n[+]db ok\n[+]registry ok\nreadyz check passed\n" {
+		t.Fatalf("readyz: unexpected %d %q", code, body)
+	}
+	dbErr = errors.New("connection refused")
+	if code, body := get(READYZ_PATH); code != http.StatusServiceUnavailable || !strings.Contains(body, "[-]db failed: connection refused") {
+		t.Fatalf("expected failed check not ready, got %d %q", code, body)
+	}
+	dbErr = nil
+	// 排空时不再就绪
+	probe.SetReady(false)
+	if code, _ := get(READYZ_PATH); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready after draining, got %d", code)
+	}
+	if code, _ := get(HEALTHZ_PATH); code != http.StatusOK {
+		t.Fatalf("expected alive after draining, got %d", code)
+	}
+}
@@ -4,17 +4,20 @@ package gins
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/Lyndon-Zhang/gira"
 	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/graceful"
 )
 
 type HttpServer struct {
 	config     gira.HttpConfig
 	Handler    http.Handler
 	server     *http.Server
+	listener   net.Listener
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
@@ -42,7 +45,23 @@ func (self *HttpServer) Stop() error {
 	return nil
 }
 
+// 绑定端口, 没有调用时由Serve绑定
+func (self *HttpServer) Listen() error {
+	if self.listener != nil {
+		return nil
+	}
+	listener, err := graceful.Listen("http", "tcp", self.config.Addr)
+	if err != nil {
+		return err
+	}
+	self.listener = listener
+	return nil
+}
+
 func (self *HttpServer) Serve() error {
+	if err := self.Listen(); err != nil {
+		return err
+	}
 	log.Debugw("http server started", "addr", self.config.Addr)
 	go func() {
 		<-self.ctx.Done()
@@ -50,11 +69,11 @@ func (self *HttpServer) Serve() error {
 	}()
 	var err error
 	if self.config.Ssl && len(self.config.CertFile) > 0 && len(self.config.KeyFile) > 0 {
-		if err = self.server.ListenAndServeTLS(self.config.CertFile, self.config.KeyFile); err == http.ErrServerClosed {
+		if err = self.server.ServeTLS(self.listener, self.config.CertFile, self.config.KeyFile); err == http.ErrServerClosed {
 			err = nil
 		}
 	} else {
-		if err = self.server.ListenAndServe(); err == http.ErrServerClosed {
+		if err = self.server.Serve(self.listener); err == http.ErrServerClosed {
 			err = nil
 		}
 	}
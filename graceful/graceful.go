@@ -0,0 +1,333 @@
+package graceful
+
+///
+/// 零停机重启, 新旧进程交接listener
+///
+/// 旧进程:
+///   - 创建一对unix socket, 一端作为fd 3传给新进程, 启动同一个程序
+///   - 通过socket把全部的listener发给新进程(SCM_RIGHTS)
+///   - 等待新进程就绪, 超时或者新进程退出时kill掉新进程, 继续运行
+///   - 新进程就绪后停止accept, 新的连接全部由新进程接收, listener关闭前Accept一直阻塞
+/// 新进程:
+///   - 启动时调用Inherit, 从socket中收到listener
+///   - Listen时优先使用同名的listener, 端口不会出现没有绑定的时刻
+///   - 启动完成后调用Ready通知旧进程
+///
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+const (
+	ENV_GRACEFUL_FD = "GIRA_GRACEFUL_FD"
+	ready_message   = "ready\n"
+	max_listeners   = 16
+)
+
+var (
+	mu        sync.Mutex
+	listeners = make(map[string]*listener)    // 当前进程的listener
+	inherited = make(map[string]net.Listener) // 从旧进程继承的, 还没有使用
+	parent    *net.UnixConn
+	// 由旧进程启动
+	isInherited bool
+	// 启动时的程序路径, runtime会修改工作目录, 所以要提前保存
+	executable = func() string {
+		if strings.ContainsRune(os.Args[0], filepath.Separator) {
+			if v, err := filepath.Abs(os.Args[0]); err == nil {
+				return v
+			}
+		} else if v, err := exec.LookPath(os.Args[0]); err == nil {
+			return v
+		}
+		return os.Args[0]
+	}()
+)
+
+// 可以停止accept的listener, 和新进程共用同一个socket
+type listener struct {
+	net.Listener
+	stopped   chan struct{}
+	closed    chan struct{}
+	stopOnce  sync.Once
+	closeOnce sync.Once
+}
+
+func newListener(l net.Listener) *listener {
+	return &listener{
+		Listener: l,
+		stopped:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// 停止accept后一直阻塞到Close, 服务器不会因为Accept出错而退出
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		select {
+		case <-l.stopped:
+			<-l.closed
+			return nil, net.ErrClosed
+		default:
+		}
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case <-l.stopped:
+				// stop设置的deadline唤醒了Accept
+				continue
+			default:
+			}
+		}
+		return conn, err
+	}
+}
+
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return l.Listener.Close()
+}
+
+// 停止accept, 已经阻塞在Accept中的调用通过deadline唤醒
+func (l *listener) stop() {
+	l.stopOnce.Do(func() {
+		close(l.stopped)
+		if v, ok := l.Listener.(interface{ SetDeadline(time.Time) error }); ok {
+			v.SetDeadline(time.Now())
+		}
+	})
+}
+
+// 按名字创建listener, 有从旧进程继承的同名listener时直接使用
+func Listen(name string, network string, address string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if l, ok := inherited[name]; ok {
+		delete(inherited, name)
+		listeners[name] = newListener(l)
+		log.Infow("graceful inherit listener", "name", name, "address", l.Addr())
+		return listeners[name], nil
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	listeners[name] = newListener(l)
+	return listeners[name], nil
+}
+
+// 停止全部listener的accept, 新进程就绪后调用
+func StopAccept() {
+	mu.Lock()
+	defer mu.Unlock()
+	for name, l := range listeners {
+		log.Infow("graceful stop accept", "name", name, "address", l.Addr())
+		l.stop()
+	}
+}
+
+// 是否由旧进程启动
+func IsInherited() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return isInherited
+}
+
+// 从旧进程接收listener, 在创建listener之前调用
+func Inherit() error {
+	v := os.Getenv(ENV_GRACEFUL_FD)
+	if len(v) <= 0 {
+		return nil
+	}
+	os.Unsetenv(ENV_GRACEFUL_FD)
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), "graceful")
+	conn, err := net.FileConn(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	uconn, ok := conn.(*net.UnixConn)
+	if !ok {
+		conn.Close()
+		return fmt.Errorf("graceful fd %d is not a unix socket", fd)
+	}
+	if err := receive(uconn); err != nil {
+		uconn.Close()
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	parent = uconn
+	isInherited = true
+	return nil
+}
+
+// 从socket中接收listener, 保存到inherited
+func receive(uconn *net.UnixConn) error {
+	buf := make([]byte, 4096)
+	oob := make([]byte, syscall.CmsgSpace(max_listeners*4))
+	n, oobn, _, _, err := uconn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if err := json.Unmarshal(buf[:n], &names); err != nil {
+		return err
+	}
+	var fds []int
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if v, err := syscall.ParseUnixRights(&msg); err == nil {
+				fds = append(fds, v...)
+			}
+		}
+	}
+	if len(fds) != len(names) {
+		return fmt.Errorf("graceful expect %d listeners, got %d", len(names), len(fds))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, fd := range fds {
+		f := os.NewFile(uintptr(fd), names[i])
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		inherited[names[i]] = l
+	}
+	log.Infow("graceful inherit", "listeners", names)
+	return nil
+}
+
+// 通过socket发送当前进程全部的listener
+func send(uconn *net.UnixConn) ([]string, error) {
+	mu.Lock()
+	names := make([]string, 0, len(listeners))
+	files := make([]*os.File, 0, len(listeners))
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for name, l := range listeners {
+		filer, ok := l.Listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			mu.Unlock()
+			return nil, fmt.Errorf("graceful listener %s not support", name)
+		}
+		f, err := filer.File()
+		if err != nil {
+			mu.Unlock()
+			return nil, err
+		}
+		names = append(names, name)
+		files = append(files, f)
+	}
+	mu.Unlock()
+	if len(files) > max_listeners {
+		return nil, fmt.Errorf("graceful too many listeners %d", len(files))
+	}
+	header, _ := json.Marshal(names)
+	rights := make([]int, 0, len(files))
+	for _, f := range files {
+		rights = append(rights, int(f.Fd()))
+	}
+	if _, _, err := uconn.WriteMsgUnix(header, syscall.UnixRights(rights...), nil); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// 通知旧进程已经就绪, 关闭没有使用的listener
+func Ready() error {
+	mu.Lock()
+	defer mu.Unlock()
+	for name, l := range inherited {
+		log.Infow("graceful close unused listener", "name", name, "address", l.Addr())
+		l.Close()
+		delete(inherited, name)
+	}
+	if parent == nil {
+		return nil
+	}
+	_, err := parent.Write([]byte(ready_message))
+	parent.Close()
+	parent = nil
+	return err
+}
+
+// 启动新的进程并交接listener, 新进程就绪后返回
+// 新进程启动失败时返回错误, 旧进程继续运行
+func Restart(timeout time.Duration) (int, error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return 0, err
+	}
+	parentFile := os.NewFile(uintptr(fds[0]), "graceful-parent")
+	childFile := os.NewFile(uintptr(fds[1]), "graceful-child")
+	conn, err := net.FileConn(parentFile)
+	parentFile.Close()
+	if err != nil {
+		childFile.Close()
+		return 0, err
+	}
+	defer conn.Close()
+	uconn := conn.(*net.UnixConn)
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// ExtraFiles[0]在新进程中是fd 3
+	cmd.ExtraFiles = []*os.File{childFile}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=3", ENV_GRACEFUL_FD))
+	err = cmd.Start()
+	childFile.Close()
+	if err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	names, err := send(uconn)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return 0, err
+	}
+	log.Infow("graceful restart", "pid", pid, "executable", executable, "listeners", names)
+	// 新进程退出时socket会关闭, Read返回EOF
+	uconn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, len(ready_message))
+	if _, err := uconn.Read(buf); err != nil || string(buf) != ready_message {
+		log.Errorw("graceful restart fail", "pid", pid, "error", err)
+		cmd.Process.Kill()
+		cmd.Wait()
+		return 0, errors.ErrGracefulRestartFail
+	}
+	// 旧进程退出后由init接管
+	go cmd.Wait()
+	log.Infow("graceful restart ready", "pid", pid)
+	StopAccept()
+	return pid, nil
+}
@@ -0,0 +1,92 @@
+package graceful
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func socketpair(t *testing.T) (*net.UnixConn, *net.UnixConn) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := make([]*net.UnixConn, 0, 2)
+	for _, fd := range fds {
+		f := os.NewFile(uintptr(fd), "socketpair")
+		conn, err := net.FileConn(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn.(*net.UnixConn))
+	}
+	return conns[0], conns[1]
+}
+
+func TestHandoff(t *testing.T) {
+	old, err := Listen("test", "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+	a, b := socketpair(t)
+	defer a.Close()
+	defer b.Close()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := send(a)
+		errs <- err
+	}()
+	if err := receive(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	l, err := Listen("test", "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if l.Addr().String() != old.Addr().String() {
+		t.Fatalf("expected inherited listener on %v, got %v", old.Addr(), l.Addr())
+	}
+
+	// 旧的listener阻塞在Accept中时停止accept, 新的连接由新的listener接收
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := old.Accept()
+		if conn != nil {
+			conn.Close()
+		}
+		accepted <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	old.(*listener).stop()
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", old.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+		l.(*listener).Listener.(*net.TCPListener).SetDeadline(time.Now().Add(time.Second))
+		if conn, err := l.Accept(); err != nil {
+			t.Fatal(err)
+		} else {
+			conn.Close()
+		}
+	}
+	select {
+	case err := <-accepted:
+		t.Fatalf("stopped listener accepted, %v", err)
+	default:
+	}
+	old.Close()
+	if err := <-accepted; !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected closed, got %v", err)
+	}
+}
@@ -1,10 +1,53 @@
 package gira
 
-import "google.golang.org/grpc"
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
 
 const GRPC_PATH_KEY = "girapath"
 
 type GrpcServer interface {
 	RegisterService(desc *grpc.ServiceDesc, impl interface{})
 	GetServer(name string) (svr interface{}, ok bool)
+	// 注册拦截器, 在OnCreate中调用
+	UseUnaryInterceptor(interceptor ...grpc.UnaryServerInterceptor)
+	UseStreamInterceptor(interceptor ...grpc.StreamServerInterceptor)
+	// 方法统计
+	MethodStats() []GrpcMethodStat
+	// 设置grpc.health.v1中服务的状态
+	SetServiceStatus(serviceName string, serving bool)
+}
+
+type GrpcMethodStat struct {
+	Method   string
+	Calls    int64
+	Errors   int64
+	Panics   int64
+	Slows    int64
+	Duration time.Duration // 累计耗时
+	Max      time.Duration
+}
+
+// grpc客户端连接管理
+type GrpcConnManager interface {
+	// 返回地址对应的连接, 每个地址共用一个连接
+	GetConn(address string) (*grpc.ClientConn, error)
+	Stats() GrpcConnStats
+}
+
+type GrpcConnStat struct {
+	Address    string
+	State      string
+	CreateTime int64
+	LastUsed   int64
+	Calls      int64 // 获取连接的次数
+	Reconnects int64 // 主动重连的次数
+	Active     int64 // 进行中的请求和流
+}
+
+type GrpcConnStats struct {
+	Conns     []GrpcConnStat
+	Evictions int64 // 节点下线关闭的连接数
 }
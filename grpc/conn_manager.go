@@ -0,0 +1,285 @@
+package grpc
+
+///
+/// grpc连接管理
+///
+/// 每个地址只保持一个连接, 所有的客户端共用(http2多路复用)
+///   - 连接断开后由grpc自动重连, 定时检查连接状态, 失败的连接立即重试
+///   - 超过idle-timeout没有请求的连接会被关闭, 有进行中的请求或者流时不会关闭
+///   - 节点下线时关闭对应的连接, 地址和url两种形式都会关闭
+///   - 配置了token时, 每个请求都会带上token
+///   - 配置了tls时, 使用和服务端相同的证书
+///   - ctx中有trace时, 通过metadata传给下游
+///
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/grpc/policy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/stats"
+)
+
+const (
+	DEFAULT_CONN_IDLE_TIMEOUT      = 300 // 秒
+	DEFAULT_CONN_CHECK_INTERVAL    = 5   // 秒
+	DEFAULT_CONN_KEEPALIVE_TIME    = 30  // 秒
+	DEFAULT_CONN_KEEPALIVE_TIMEOUT = 10  // 秒
+)
+
+type managed_conn struct {
+	address    string
+	conn       *grpc.ClientConn
+	createTime int64
+	lastUsed   int64 // unix nano
+	calls      int64
+	reconnects int64
+	active     int64 // 进行中的请求和流
+}
+
+// 实现接口 stats.Handler, 统计进行中的请求, 流结束时才算结束
+func (c *managed_conn) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (c *managed_conn) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	switch s.(type) {
+	case *stats.Begin:
+		atomic.AddInt64(&c.active, 1)
+	case *stats.End:
+		atomic.StoreInt64(&c.lastUsed, time.Now().UnixNano())
+		atomic.AddInt64(&c.active, -1)
+	}
+}
+
+func (c *managed_conn) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (c *managed_conn) HandleConn(ctx context.Context, s stats.ConnStats) {
+}
+
+func (c *managed_conn) isIdle(now int64, idleTimeout time.Duration) bool {
+	return atomic.LoadInt64(&c.active) == 0 && time.Duration(now-atomic.LoadInt64(&c.lastUsed)) > idleTimeout
+}
+
+// 节点对应的连接地址
+type peer_target struct {
+	address string
+	url     string
+}
+
+type ConnManager struct {
+	config     gira.GrpcConnConfig
+	mu         sync.Mutex
+	conns      map[string]*managed_conn
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	dialOpts   []grpc.DialOption
+	evictions  int64
+	peers      sync.Map // 节点的连接地址 map[string]peer_target, 节点删除时地址已经被清空
+}
+
+// creds为空时使用明文
+func NewConfigConnManager(ctx context.Context, grpcConfig gira.GrpcConfig, creds *Credentials) *ConnManager {
+	config := grpcConfig.Conn
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = DEFAULT_CONN_IDLE_TIMEOUT
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = DEFAULT_CONN_CHECK_INTERVAL
+	}
+	if config.KeepaliveTime <= 0 {
+		config.KeepaliveTime = DEFAULT_CONN_KEEPALIVE_TIME
+	}
+	if config.KeepaliveTimeout <= 0 {
+		config.KeepaliveTimeout = DEFAULT_CONN_KEEPALIVE_TIMEOUT
+	}
+	self := &ConnManager{
+		config: config,
+		conns:  make(map[string]*managed_conn),
+		dialOpts: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithKeepaliveParams(keepalive.ClientParameters{
+				Time:                time.Duration(config.KeepaliveTime) * time.Second,
+				Timeout:             time.Duration(config.KeepaliveTimeout) * time.Second,
+				PermitWithoutStream: true,
+			}),
+			grpc.WithChainUnaryInterceptor(traceUnaryClientInterceptor),
+			grpc.WithChainStreamInterceptor(traceStreamClientInterceptor),
+		},
+	}
+	if creds != nil {
+		self.dialOpts[0] = grpc.WithTransportCredentials(creds.ClientCredentials())
+	}
+	if len(grpcConfig.Token) > 0 {
+		self.dialOpts = append(self.dialOpts,
+			grpc.WithChainUnaryInterceptor(tokenUnaryClientInterceptor(grpcConfig.Token)),
+			grpc.WithChainStreamInterceptor(tokenStreamClientInterceptor(grpcConfig.Token)),
+		)
+	}
+	self.ctx, self.cancelFunc = context.WithCancel(ctx)
+	return self
+}
+
+// 返回地址对应的连接, 不存在时创建
+// 协程安全
+func (self *ConnManager) GetConn(address string) (*grpc.ClientConn, error) {
+	now := time.Now().UnixNano()
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if c, ok := self.conns[address]; ok {
+		if c.conn.GetState() != connectivity.Shutdown {
+			atomic.StoreInt64(&c.lastUsed, now)
+			atomic.AddInt64(&c.calls, 1)
+			return c.conn, nil
+		}
+		delete(self.conns, address)
+	}
+	c := &managed_conn{
+		address:    address,
+		createTime: time.Now().Unix(),
+		lastUsed:   now,
+		calls:      1,
+	}
+	dialOpts := append([]grpc.DialOption{grpc.WithStatsHandler(c)}, self.dialOpts...)
+	conn, err := grpc.DialContext(self.ctx, address, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	self.conns[address] = c
+	log.Debugw("grpc conn create", "address", address)
+	return conn, nil
+}
+
+// 关闭地址对应的连接
+func (self *ConnManager) Evict(address string) {
+	self.mu.Lock()
+	c, ok := self.conns[address]
+	if ok {
+		delete(self.conns, address)
+	}
+	self.mu.Unlock()
+	if ok {
+		atomic.AddInt64(&self.evictions, 1)
+		log.Debugw("grpc conn evict", "address", address)
+		c.conn.Close()
+	}
+}
+
+func (self *ConnManager) Stats() gira.GrpcConnStats {
+	self.mu.Lock()
+	conns := make([]gira.GrpcConnStat, 0, len(self.conns))
+	for _, c := range self.conns {
+		conns = append(conns, gira.GrpcConnStat{
+			Address:    c.address,
+			State:      c.conn.GetState().String(),
+			CreateTime: c.createTime,
+			LastUsed:   atomic.LoadInt64(&c.lastUsed) / int64(time.Second),
+			Calls:      atomic.LoadInt64(&c.calls),
+			Reconnects: atomic.LoadInt64(&c.reconnects),
+			Active:     atomic.LoadInt64(&c.active),
+		})
+	}
+	self.mu.Unlock()
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].Address < conns[j].Address
+	})
+	return gira.GrpcConnStats{
+		Conns:     conns,
+		Evictions: atomic.LoadInt64(&self.evictions),
+	}
+}
+
+// 定时检查连接状态, 关闭空闲的连接
+func (self *ConnManager) Serve() error {
+	ticker := time.NewTicker(time.Duration(self.config.CheckInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case <-ticker.C:
+			self.check()
+		}
+	}
+}
+
+func (self *ConnManager) check() {
+	idleTimeout := time.Duration(self.config.IdleTimeout) * time.Second
+	now := time.Now().UnixNano()
+	idles := make([]*managed_conn, 0)
+	self.mu.Lock()
+	for address, c := range self.conns {
+		switch c.conn.GetState() {
+		case connectivity.Shutdown:
+			delete(self.conns, address)
+		case connectivity.TransientFailure:
+			// 跳过退避时间, 立即重连
+			atomic.AddInt64(&c.reconnects, 1)
+			c.conn.ResetConnectBackoff()
+		default:
+			if c.isIdle(now, idleTimeout) {
+				delete(self.conns, address)
+				idles = append(idles, c)
+			}
+		}
+	}
+	self.mu.Unlock()
+	for _, c := range idles {
+		log.Debugw("grpc conn idle timeout", "address", c.address)
+		c.conn.Close()
+	}
+}
+
+// 关闭全部的连接
+func (self *ConnManager) Stop() {
+	self.cancelFunc()
+	self.mu.Lock()
+	conns := self.conns
+	self.conns = make(map[string]*managed_conn)
+	self.mu.Unlock()
+	for _, c := range conns {
+		c.conn.Close()
+	}
+}
+
+// 实现接口 gira.PeerWatchHandler
+func (self *ConnManager) OnPeerAdd(peer *gira.Peer) {
+	self.peers.Store(peer.FullName, peer_target{address: peer.Address, url: peer.Url})
+}
+
+// 节点下线时关闭连接, 客户端可能使用地址或者url连接
+func (self *ConnManager) OnPeerDelete(peer *gira.Peer) {
+	if v, ok := self.peers.LoadAndDelete(peer.FullName); ok {
+		self.evictTarget(v.(peer_target))
+	}
+}
+
+// 地址变化时关闭旧的连接
+func (self *ConnManager) OnPeerUpdate(peer *gira.Peer) {
+	target := peer_target{address: peer.Address, url: peer.Url}
+	v, ok := self.peers.Load(peer.FullName)
+	self.peers.Store(peer.FullName, target)
+	if ok && v.(peer_target) != target {
+		self.evictTarget(v.(peer_target))
+	}
+}
+
+func (self *ConnManager) evictTarget(target peer_target) {
+	for _, address := range []string{target.address, target.url} {
+		if len(address) > 0 {
+			self.Evict(address)
+			policy.RemoveBreaker(address)
+		}
+	}
+}
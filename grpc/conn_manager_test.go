@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"google.golang.org/grpc/stats"
+)
+
+func TestConnIdle(t *testing.T) {
+	c := &managed_conn{lastUsed: time.Now().Add(-time.Hour).UnixNano()}
+	ctx := context.Background()
+	c.HandleRPC(ctx, &stats.Begin{})
+	if c.isIdle(time.Now().UnixNano(), time.Minute) {
+		t.Fatal("conn with active stream should not be idle")
+	}
+	c.HandleRPC(ctx, &stats.End{})
+	if c.isIdle(time.Now().UnixNano(), time.Minute) {
+		t.Fatal("conn just used should not be idle")
+	}
+	if !c.isIdle(time.Now().Add(2*time.Minute).UnixNano(), time.Minute) {
+		t.Fatal("expected idle")
+	}
+}
+
+func TestPeerDeleteEvictUrl(t *testing.T) {
+	m := NewConfigConnManager(context.Background(), gira.GrpcConfig{}, nil)
+	defer m.Stop()
+	peer := &gira.Peer{FullName: "hall_1", Address: "127.0.0.1:1", Url: "peer:///hall_1"}
+	m.OnPeerAdd(peer)
+	for _, address := range []string{peer.Address, peer.Url} {
+		if _, err := m.GetConn(address); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 注册表删除节点时已经清空了地址
+	peer.Address = ""
+	peer.Url = ""
+	m.OnPeerDelete(peer)
+	if stats := m.Stats(); len(stats.Conns) != 0 || stats.Evictions != 2 {
+		t.Fatalf("expected all conns evicted, got %+v", stats)
+	}
+}
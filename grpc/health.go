@@ -0,0 +1,73 @@
+package grpc
+
+///
+/// 标准的grpc.health.v1服务, 可以用grpc_health_probe等工具检查
+///
+/// 整体状态(service为空)跟随runtime的生命周期
+///   starting: NOT_SERVING
+///   serving:  SERVING
+///   draining: NOT_SERVING
+///   stopped:  NOT_SERVING, 之后不再修改
+/// 注册的服务默认跟随整体状态, 也可以用SetServiceStatus单独设置
+///
+import (
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	LIFECYCLE_STARTING = iota
+	LIFECYCLE_SERVING
+	LIFECYCLE_DRAINING
+	LIFECYCLE_STOPPED
+)
+
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// 修改生命周期, 同时修改整体和各个服务的状态
+func (self *Server) SetLifecycle(lifecycle int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.lifecycle == LIFECYCLE_STOPPED {
+		return
+	}
+	self.lifecycle = lifecycle
+	if lifecycle == LIFECYCLE_STOPPED {
+		self.health.Shutdown()
+		return
+	}
+	status := self.lifecycleStatus()
+	self.health.SetServingStatus("", status)
+	for name := range self.servers {
+		if _, ok := self.downServices[name]; !ok {
+			self.health.SetServingStatus(name, status)
+		}
+	}
+}
+
+// 设置单个服务的状态, 在整体状态为serving时才生效
+func (self *Server) SetServiceStatus(serviceName string, serving bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.lifecycle == LIFECYCLE_STOPPED {
+		return
+	}
+	if serving {
+		delete(self.downServices, serviceName)
+		self.health.SetServingStatus(serviceName, self.lifecycleStatus())
+	} else {
+		self.downServices[serviceName] = struct{}{}
+		self.health.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+func (self *Server) lifecycleStatus() grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if self.lifecycle == LIFECYCLE_SERVING {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
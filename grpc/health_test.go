@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealth(t *testing.T) {
+	server, err := NewConfigServer(gira.GrpcConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.RegisterService(&grpc.ServiceDesc{ServiceName: "hall.Hall", HandlerType: (*interface{})(nil)}, struct{}{})
+	const (
+		serving    = grpc_health_v1.HealthCheckResponse_SERVING
+		notServing = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	)
+	check := func(step string, overall grpc_health_v1.HealthCheckResponse_ServingStatus, hall grpc_health_v1.HealthCheckResponse_ServingStatus) {
+		for service, expected := range map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": overall, "hall.Hall": hall} {
+			resp, err := server.health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("%s: check %q fail, %v", step, service, err)
+			}
+			if resp.Status != expected {
+				t.Errorf("%s: expected %q %v, got %v", step, service, expected, resp.Status)
+			}
+		}
+	}
+	check("starting", notServing, notServing)
+	server.SetLifecycle(LIFECYCLE_SERVING)
+	check("serving", serving, serving)
+	server.SetServiceStatus("hall.Hall", false)
+	check("service down", serving, notServing)
+	server.SetLifecycle(LIFECYCLE_DRAINING)
+	check("draining", notServing, notServing)
+	// 单独设置的状态在生命周期变化后保留
+	server.SetLifecycle(LIFECYCLE_SERVING)
+	check("serving again", serving, notServing)
+	server.SetServiceStatus("hall.Hall", true)
+	check("service up", serving, serving)
+	server.SetLifecycle(LIFECYCLE_STOPPED)
+	check("stopped", notServing, notServing)
+	// 停止后不再修改
+	server.SetLifecycle(LIFECYCLE_SERVING)
+	server.SetServiceStatus("hall.Hall", true)
+	check("after stopped", notServing, notServing)
+
+	if _, err := server.health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown.Unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected unknown service not found, got %v", err)
+	}
+}
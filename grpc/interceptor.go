@@ -0,0 +1,357 @@
+package grpc
+
+///
+/// 服务端拦截器
+///
+/// 执行顺序: recovery => auth => metrics(包括慢调用日志) => trace => 应用注册的拦截器 => handler
+/// 应用可以在OnCreate中调用UseUnaryInterceptor, UseStreamInterceptor注册拦截器
+///
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// 节点间认证的token
+	GRPC_TOKEN_KEY = "gira-token"
+)
+
+// 不需要认证的方法
+var authSkipPrefixs = []string{
+	"/grpc.health.v1.",
+	"/grpc.reflection.",
+}
+
+type method_metric struct {
+	calls    int64
+	errors   int64
+	panics   int64
+	slows    int64
+	duration int64 // 累计耗时, 纳秒
+	max      int64
+}
+
+// 注册unary拦截器, 在Serve之前调用
+func (self *Server) UseUnaryInterceptor(interceptor ...grpc.UnaryServerInterceptor) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	interceptors, _ := self.unaryInterceptors.Load().([]grpc.UnaryServerInterceptor)
+	self.unaryInterceptors.Store(append(append([]grpc.UnaryServerInterceptor{}, interceptors...), interceptor...))
+}
+
+// 注册stream拦截器, 在Serve之前调用
+func (self *Server) UseStreamInterceptor(interceptor ...grpc.StreamServerInterceptor) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	interceptors, _ := self.streamInterceptors.Load().([]grpc.StreamServerInterceptor)
+	self.streamInterceptors.Store(append(append([]grpc.StreamServerInterceptor{}, interceptors...), interceptor...))
+}
+
+// 方法统计
+func (self *Server) MethodStats() []gira.GrpcMethodStat {
+	stats := make([]gira.GrpcMethodStat, 0)
+	self.metrics.Range(func(key, value any) bool {
+		m := value.(*method_metric)
+		stats = append(stats, gira.GrpcMethodStat{
+			Method:   key.(string),
+			Calls:    atomic.LoadInt64(&m.calls),
+			Errors:   atomic.LoadInt64(&m.errors),
+			Panics:   atomic.LoadInt64(&m.panics),
+			Slows:    atomic.LoadInt64(&m.slows),
+			Duration: time.Duration(atomic.LoadInt64(&m.duration)),
+			Max:      time.Duration(atomic.LoadInt64(&m.max)),
+		})
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+func (self *Server) getMetric(method string) *method_metric {
+	if v, ok := self.metrics.Load(method); ok {
+		return v.(*method_metric)
+	}
+	v, _ := self.metrics.LoadOrStore(method, &method_metric{})
+	return v.(*method_metric)
+}
+
+func (self *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	begin := time.Now()
+	metric := self.getMetric(info.FullMethod)
+	defer func() {
+		if e := recover(); e != nil {
+			err = self.onPanic(ctx, info.FullMethod, metric, e)
+		}
+		self.onDone(ctx, info.FullMethod, metric, begin, err)
+	}()
+	if err = self.auth(ctx, info.FullMethod); err != nil {
+		return
+	}
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	if span != nil {
+		defer func() {
+			span.SetError(err)
+			span.End()
+		}()
+	}
+	interceptors, _ := self.unaryInterceptors.Load().([]grpc.UnaryServerInterceptor)
+	return chainUnary(interceptors, 0, ctx, req, info, handler)
+}
+
+func (self *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	begin := time.Now()
+	metric := self.getMetric(info.FullMethod)
+	ctx := ss.Context()
+	defer func() {
+		if e := recover(); e != nil {
+			err = self.onPanic(ctx, info.FullMethod, metric, e)
+		}
+		self.onDone(ctx, info.FullMethod, metric, begin, err)
+	}()
+	if err = self.auth(ctx, info.FullMethod); err != nil {
+		return
+	}
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	if span != nil {
+		defer func() {
+			span.SetError(err)
+			span.End()
+		}()
+		ss = &traced_server_stream{ServerStream: ss, ctx: ctx}
+	}
+	interceptors, _ := self.streamInterceptors.Load().([]grpc.StreamServerInterceptor)
+	return chainStream(interceptors, 0, srv, ss, info, handler)
+}
+
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, index int, ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if index >= len(interceptors) {
+		return handler(ctx, req)
+	}
+	return interceptors[index](ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return chainUnary(interceptors, index+1, ctx, req, info, handler)
+	})
+}
+
+func chainStream(interceptors []grpc.StreamServerInterceptor, index int, srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if index >= len(interceptors) {
+		return handler(srv, ss)
+	}
+	return interceptors[index](srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return chainStream(interceptors, index+1, srv, ss, info, handler)
+	})
+}
+
+// 校验节点间的token
+func (self *Server) auth(ctx context.Context, fullMethod string) error {
+	if len(self.config.Token) <= 0 {
+		return nil
+	}
+	for _, prefix := range authSkipPrefixs {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return nil
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "token not found")
+	} else if tokens := md.Get(GRPC_TOKEN_KEY); len(tokens) <= 0 {
+		return status.Error(codes.Unauthenticated, "token not found")
+	} else if subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(self.config.Token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return nil
+}
+
+func (self *Server) onPanic(ctx context.Context, fullMethod string, metric *method_metric, e interface{}) error {
+	atomic.AddInt64(&metric.panics, 1)
+	log.Errorw("grpc handler panic", "method", fullMethod, "peer", peerAddr(ctx), "error", e, "stack", string(debug.Stack()))
+	return status.Errorf(codes.Internal, "panic: %v", e)
+}
+
+func (self *Server) onDone(ctx context.Context, fullMethod string, metric *method_metric, begin time.Time, err error) {
+	duration := time.Since(begin)
+	atomic.AddInt64(&metric.calls, 1)
+	atomic.AddInt64(&metric.duration, int64(duration))
+	for {
+		max := atomic.LoadInt64(&metric.max)
+		if int64(duration) <= max || atomic.CompareAndSwapInt64(&metric.max, max, int64(duration)) {
+			break
+		}
+	}
+	if err != nil {
+		atomic.AddInt64(&metric.errors, 1)
+	}
+	if self.config.SlowThreshold > 0 && duration > time.Duration(self.config.SlowThreshold)*time.Millisecond {
+		atomic.AddInt64(&metric.slows, 1)
+		log.Warnw("grpc slow call", "method", fullMethod, "peer", peerAddr(ctx), "duration", duration, "error", err)
+	}
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// 替换stream的ctx, 带上span
+type traced_server_stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *traced_server_stream) Context() context.Context {
+	return s.ctx
+}
+
+// 上游带了trace时才生成span
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, *trace.Span) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	traceIds := md.Get(trace.TRACE_ID_KEY)
+	if len(traceIds) <= 0 {
+		return ctx, nil
+	}
+	var spanId string
+	if spanIds := md.Get(trace.SPAN_ID_KEY); len(spanIds) > 0 {
+		spanId = spanIds[0]
+	}
+	sampled := false
+	if sampleds := md.Get(trace.SAMPLED_KEY); len(sampleds) > 0 {
+		sampled = sampleds[0] == "1"
+	}
+	ctx = trace.ContextWithRemote(ctx, traceIds[0], spanId, sampled)
+	ctx, span := trace.StartSpan(ctx, fullMethod, trace.SPAN_KIND_SERVER)
+	span.SetAttribute("peer", peerAddr(ctx))
+	return ctx, span
+}
+
+// ctx中有span时生成client span, 并通过metadata传给下游
+func startClientSpan(ctx context.Context, method string, cc *grpc.ClientConn) (context.Context, *trace.Span) {
+	if trace.FromContext(ctx) == nil {
+		return ctx, nil
+	}
+	ctx, span := trace.StartSpan(ctx, method, trace.SPAN_KIND_CLIENT)
+	span.SetAttribute("address", cc.Target())
+	sampled := "0"
+	if span.Sampled {
+		sampled = "1"
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, trace.TRACE_ID_KEY, span.TraceId, trace.SPAN_ID_KEY, span.SpanId, trace.SAMPLED_KEY, sampled)
+	return ctx, span
+}
+
+func traceUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := startClientSpan(ctx, method, cc)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if span != nil {
+		span.SetError(err)
+		span.End()
+	}
+	return err
+}
+
+// stream的span在stream结束后结束, 见traced_client_stream
+func traceStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := startClientSpan(ctx, method, cc)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if span == nil {
+		return stream, err
+	}
+	if err != nil {
+		span.SetError(err)
+		span.End()
+		return stream, err
+	}
+	s := &traced_client_stream{ClientStream: stream, desc: desc, span: span, done: make(chan struct{})}
+	// 调用方没有读完stream时, 在ctx结束后结束span
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.end(ctx.Err())
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// RecvMsg返回错误(包括io.EOF), 或者服务端不是stream时收到响应, stream就结束了
+type traced_client_stream struct {
+	grpc.ClientStream
+	desc *grpc.StreamDesc
+	span *trace.Span
+	once sync.Once
+	done chan struct{}
+}
+
+func (s *traced_client_stream) end(err error) {
+	s.once.Do(func() {
+		s.span.SetError(err)
+		s.span.End()
+		close(s.done)
+	})
+}
+
+func (s *traced_client_stream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	// io.EOF时真正的错误由RecvMsg返回
+	if err != nil && err != io.EOF {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *traced_client_stream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		s.end(nil)
+	} else if err != nil {
+		s.end(err)
+	} else if !s.desc.ServerStreams {
+		s.end(nil)
+	}
+	return err
+}
+
+func (s *traced_client_stream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.end(err)
+	}
+	return md, err
+}
+
+// 客户端拦截器, 带上节点间认证的token
+func tokenUnaryClientInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, GRPC_TOKEN_KEY, token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func tokenStreamClientInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, GRPC_TOKEN_KEY, token)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
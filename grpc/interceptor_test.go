@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	server := &Server{config: gira.GrpcConfig{Token: "secret"}}
+	calls := make([]string, 0)
+	server.UseUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		calls = append(calls, "interceptor")
+		return handler(ctx, req)
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	}
+	withToken := func(token ...string) context.Context {
+		md := metadata.MD{}
+		if len(token) > 0 {
+			md.Set(GRPC_TOKEN_KEY, token...)
+		}
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{"valid token", withToken("secret"), "/hall.Hall/Login", codes.OK},
+		{"no metadata", context.Background(), "/hall.Hall/Login", codes.Unauthenticated},
+		{"no token", withToken(), "/hall.Hall/Login", codes.Unauthenticated},
+		{"invalid token", withToken("secreT"), "/hall.Hall/Login", codes.Unauthenticated},
+		{"token prefix", withToken("secret1"), "/hall.Hall/Login", codes.Unauthenticated},
+		{"health skips auth", context.Background(), "/grpc.health.v1.Health/Check", codes.OK},
+	}
+	for _, tt := range tests {
+		calls = calls[:0]
+		resp, err := server.unaryInterceptor(tt.ctx, "req", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.code, err)
+			continue
+		}
+		if tt.code != codes.OK {
+			// 认证失败时不能调用到应用的拦截器和handler
+			if len(calls) != 0 {
+				t.Errorf("%s: unexpected calls %v", tt.name, calls)
+			}
+		} else if resp != "req" || len(calls) != 2 || calls[0] != "interceptor" || calls[1] != "handler" {
+			t.Errorf("%s: unexpected calls %v, resp %v", tt.name, calls, resp)
+		}
+	}
+	stats := server.MethodStats()
+	if len(stats) != 2 || stats[1].Method != "/hall.Hall/Login" || stats[1].Calls != 5 || stats[1].Errors != 4 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+// 没有配置token时不认证
+func TestAuthDisabled(t *testing.T) {
+	server := &Server{}
+	if err := server.auth(context.Background(), "/hall.Hall/Login"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type test_client_stream struct {
+	grpc.ClientStream
+	recvs []error
+}
+
+func (s *test_client_stream) RecvMsg(m interface{}) error {
+	err := s.recvs[0]
+	s.recvs = s.recvs[1:]
+	return err
+}
+
+// stream的span在读到io.EOF后才结束
+func TestTraceStreamClient(t *testing.T) {
+	cc, err := grpc.Dial("passthrough:///127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	ctx, parent := trace.StartSpan(context.Background(), "test", trace.SPAN_KIND_INTERNAL)
+	newStream := func(ctx context.Context, desc *grpc.StreamDesc, recvs ...error) *traced_client_stream {
+		stream, err := traceStreamClientInterceptor(ctx, desc, cc, "/hall.Hall/ClientStream", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &test_client_stream{recvs: recvs}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stream.(*traced_client_stream)
+	}
+	ended := func(s *traced_client_stream) bool {
+		select {
+		case <-s.done:
+			return true
+		default:
+			return false
+		}
+	}
+	s := newStream(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, io.EOF)
+	if s.span.ParentSpanId != parent.SpanId {
+		t.Fatalf("unexpected span %+v", s.span)
+	}
+	if s.RecvMsg(nil); ended(s) {
+		t.Fatal("span ended before stream finished")
+	}
+	if s.RecvMsg(nil); !ended(s) || s.span.Error != "" {
+		t.Fatalf("span not ended after io.EOF, %v", s.span.Error)
+	}
+	// 服务端不是stream时收到响应就结束
+	s = newStream(ctx, &grpc.StreamDesc{ClientStreams: true}, nil)
+	if s.RecvMsg(nil); !ended(s) {
+		t.Fatal("span not ended after response")
+	}
+	s = newStream(ctx, &grpc.StreamDesc{ServerStreams: true}, status.Error(codes.Unavailable, "down"))
+	if s.RecvMsg(nil); !ended(s) || s.span.Error == "" {
+		t.Fatal("span not ended with error")
+	}
+	// 没有读完stream, ctx结束后结束
+	cancelCtx, cancelFunc := context.WithCancel(ctx)
+	s = newStream(cancelCtx, &grpc.StreamDesc{ServerStreams: true})
+	cancelFunc()
+	select {
+	case <-s.done:
+	case <-time.After(time.Second):
+		t.Fatal("span not ended after ctx canceled")
+	}
+}
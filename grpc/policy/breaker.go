@@ -0,0 +1,113 @@
+package policy
+
+///
+/// 熔断器, 每个地址一个
+///   closed:    正常调用, 连续失败threshold次后打开
+///   open:      直接返回ErrCircuitOpen, open-timeout后进入half-open
+///   half-open: 只放行一个探测请求, 成功后关闭, 失败后重新打开
+///
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_BREAKER_THRESHOLD    = 5
+	DEFAULT_BREAKER_OPEN_TIMEOUT = 10 * time.Second
+)
+
+const (
+	breaker_state_closed = iota
+	breaker_state_open
+	breaker_state_half_open
+)
+
+type Breaker struct {
+	mu        sync.Mutex
+	state     int
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+var breakers sync.Map
+var breakerThreshold = DEFAULT_BREAKER_THRESHOLD
+var breakerOpenTimeout = DEFAULT_BREAKER_OPEN_TIMEOUT
+
+// 修改熔断参数, 在启动时调用
+func ConfigBreaker(threshold int, openTimeout time.Duration) {
+	if threshold > 0 {
+		breakerThreshold = threshold
+	}
+	if openTimeout > 0 {
+		breakerOpenTimeout = openTimeout
+	}
+}
+
+// 返回地址对应的熔断器
+func GetBreaker(address string) *Breaker {
+	if v, ok := breakers.Load(address); ok {
+		return v.(*Breaker)
+	}
+	v, _ := breakers.LoadOrStore(address, &Breaker{})
+	return v.(*Breaker)
+}
+
+// 节点下线时删除
+func RemoveBreaker(address string) {
+	breakers.Delete(address)
+}
+
+// 是否放行请求
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breaker_state_open:
+		if time.Now().Before(b.openUntil) {
+			return false
+		}
+		b.state = breaker_state_half_open
+		b.probing = true
+		return true
+	case breaker_state_half_open:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// 报告请求结果
+func (b *Breaker) Done(failure bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failure {
+		b.state = breaker_state_closed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+	b.failures++
+	if b.state == breaker_state_half_open || b.failures >= breakerThreshold {
+		b.state = breaker_state_open
+		b.openUntil = time.Now().Add(breakerOpenTimeout)
+		b.probing = false
+	}
+}
+
+// 请求被取消, 结果未知, 不计入成功或者失败, 只释放探测名额
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *Breaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breaker_state_open && time.Now().Before(b.openUntil)
+}
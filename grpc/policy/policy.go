@@ -0,0 +1,206 @@
+package policy
+
+///
+/// grpc调用策略
+///   - 超时: 整个调用的超时时间, 包括重试
+///   - 重试: 只对幂等的方法生效, 失败后等待backoff再重试, 每次加倍
+///   - 对冲: 只对幂等的方法生效, 超过hedge时间没有返回时再发一个请求, 使用先返回的结果
+///   - 熔断: 每个地址一个熔断器, 连续失败后一段时间内直接返回错误
+///
+/// 方法在proto中用 option idempotency_level = IDEMPOTENT 标记为幂等
+///
+import (
+	"context"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DEFAULT_BACKOFF     = 50 * time.Millisecond
+	DEFAULT_MAX_BACKOFF = time.Second
+)
+
+type Policy struct {
+	Timeout    time.Duration // 整个调用的超时, 0表示不限制
+	Retry      int           // 最多重试次数
+	Backoff    time.Duration // 第一次重试前的等待时间
+	MaxBackoff time.Duration
+	Hedge      time.Duration // 多久没有返回时发起对冲请求, 0表示不对冲
+	Idempotent bool          // 方法是否幂等
+	Breaker    bool          // 是否使用熔断
+}
+
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// 按策略调用f, 返回的错误会映射成gira/errors中的错误, 并带上节点名
+func Invoke[T any](ctx context.Context, p Policy, peer string, address string, f func(ctx context.Context) (T, error)) (T, error) {
+	if p.Timeout > 0 {
+		var cancelFunc context.CancelFunc
+		ctx, cancelFunc = context.WithTimeout(ctx, p.Timeout)
+		defer cancelFunc()
+	}
+	var breaker *Breaker
+	if p.Breaker {
+		breaker = GetBreaker(address)
+	}
+	attempts := 1
+	if p.Idempotent && p.Retry > 0 {
+		attempts += p.Retry
+	}
+	var r result[T]
+	if p.Idempotent && p.Hedge > 0 {
+		r = hedge(ctx, p, attempts, breaker, f)
+	} else {
+		r = retry(ctx, p, attempts, breaker, f)
+	}
+	if r.err != nil {
+		r.err = Wrap(peer, r.err)
+	}
+	return r.value, r.err
+}
+
+// 将错误映射成gira/errors中的错误, 并带上节点名
+func Wrap(peer string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*errors.RpcError); ok {
+		return err
+	}
+	e := &errors.RpcError{
+		Peer:  peer,
+		Err:   err,
+		Cause: err,
+	}
+	if err == errors.ErrCircuitOpen {
+		return e
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		e.Err = errors.ErrRpcTimeout
+	case codes.Canceled:
+		e.Err = errors.ErrRpcCanceled
+	case codes.Unavailable:
+		e.Err = errors.ErrRpcUnavailable
+	}
+	return e
+}
+
+func call[T any](ctx context.Context, breaker *Breaker, f func(ctx context.Context) (T, error)) result[T] {
+	if breaker != nil && !breaker.Allow() {
+		return result[T]{err: errors.ErrCircuitOpen}
+	}
+	v, err := f(ctx)
+	switch {
+	case breaker == nil:
+	case isCanceled(err):
+		// 对冲输掉的请求和调用方取消的请求不知道结果, 不计入熔断
+		breaker.Release()
+	default:
+		breaker.Done(isFailure(err))
+	}
+	return result[T]{value: v, err: err}
+}
+
+func retry[T any](ctx context.Context, p Policy, attempts int, breaker *Breaker, f func(ctx context.Context) (T, error)) result[T] {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DEFAULT_BACKOFF
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DEFAULT_MAX_BACKOFF
+	}
+	var r result[T]
+	for i := 0; i < attempts; i++ {
+		if r = call(ctx, breaker, f); r.err == nil || !isRetryable(r.err) {
+			return r
+		}
+		if i+1 >= attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return r
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	return r
+}
+
+func hedge[T any](ctx context.Context, p Policy, attempts int, breaker *Breaker, f func(ctx context.Context) (T, error)) result[T] {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// 返回后取消还在进行中的请求
+	defer cancelFunc()
+	ch := make(chan result[T], attempts)
+	launch := func() {
+		go func() {
+			ch <- call(ctx, breaker, f)
+		}()
+	}
+	launch()
+	launched, finished := 1, 0
+	timer := time.NewTimer(p.Hedge)
+	defer timer.Stop()
+	var last result[T]
+	for {
+		select {
+		case <-timer.C:
+			if launched < attempts {
+				launch()
+				launched++
+				timer.Reset(p.Hedge)
+			}
+		case r := <-ch:
+			finished++
+			if r.err == nil || !isRetryable(r.err) {
+				return r
+			}
+			last = r
+			if launched < attempts {
+				launch()
+				launched++
+			} else if finished >= launched {
+				return last
+			}
+		}
+	}
+}
+
+// 是否可以重试
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+func isCanceled(err error) bool {
+	return err != nil && (status.Code(err) == codes.Canceled || errors.Is(err, context.Canceled))
+}
+
+// 是否计入熔断的失败次数, 业务错误不计入
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	case codes.Unknown:
+		// 非grpc错误, 例如连接失败
+		_, ok := status.FromError(err)
+		return !ok
+	}
+	return false
+}
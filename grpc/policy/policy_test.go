@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryIdempotent(t *testing.T) {
+	calls := 0
+	f := func(ctx context.Context) (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, status.Error(codes.Unavailable, "unavailable")
+		}
+		return calls, nil
+	}
+	p := Policy{Retry: 2, Backoff: time.Millisecond, Idempotent: true}
+	if v, err := Invoke(context.Background(), p, "hall_1", "127.0.0.1:1", f); err != nil || v != 3 {
+		t.Fatalf("unexpected result %d %v", v, err)
+	}
+	// 非幂等的方法不重试
+	calls = 0
+	p.Idempotent = false
+	_, err := Invoke(context.Background(), p, "hall_1", "127.0.0.1:1", f)
+	if calls != 1 || !errors.Is(err, errors.ErrRpcUnavailable) {
+		t.Fatalf("unexpected result %d %v", calls, err)
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("status code should be kept, got %v", status.Code(err))
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	ConfigBreaker(2, time.Hour)
+	defer ConfigBreaker(DEFAULT_BREAKER_THRESHOLD, DEFAULT_BREAKER_OPEN_TIMEOUT)
+	address := "127.0.0.1:2"
+	defer RemoveBreaker(address)
+	calls := 0
+	f := func(ctx context.Context) (int, error) {
+		calls++
+		return 0, status.Error(codes.Unavailable, "unavailable")
+	}
+	p := Policy{Breaker: true}
+	for i := 0; i < 3; i++ {
+		Invoke(context.Background(), p, "hall_1", address, f)
+	}
+	if calls != 2 {
+		t.Fatalf("breaker should open after 2 failures, calls %d", calls)
+	}
+	if _, err := Invoke(context.Background(), p, "hall_1", address, f); !errors.Is(err, errors.ErrCircuitOpen) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// 对冲输掉被取消的请求不能关闭半开的熔断器
+func TestBreakerIgnoreCanceled(t *testing.T) {
+	b := &Breaker{state: breaker_state_open, openUntil: time.Now().Add(-time.Second)}
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	call(ctx, b, func(ctx context.Context) (int, error) {
+		return 0, status.Error(codes.Canceled, ctx.Err().Error())
+	})
+	if b.state != breaker_state_half_open {
+		t.Fatalf("canceled request should not change state, got %d", b.state)
+	}
+	if !b.Allow() {
+		t.Fatal("probe should be released")
+	}
+	b.Done(true)
+	if !b.IsOpen() {
+		t.Fatal("expected breaker reopened")
+	}
+}
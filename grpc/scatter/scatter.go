@@ -0,0 +1,126 @@
+package scatter
+
+///
+/// scatter-gather, 同时调用多个节点, 按返回的顺序处理结果
+///   - 并发: 最多同时调用concurrency个节点, 0表示不限制
+///   - 超时: 由调用方在call中设置每个节点的超时
+///   - quorum: 成功的数量达到quorum后立即返回, 取消剩下的调用, 0表示等待全部节点
+///
+import (
+	"context"
+	"sync"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+type Options struct {
+	Concurrency int // 最多同时调用的节点数
+	Quorum      int // 成功多少个后返回
+}
+
+type Result[T any] struct {
+	Peer     *gira.Peer
+	Response T
+	Err      error
+}
+
+// 按channel返回结果, C关闭后可以调用Err
+type Stream[T any] struct {
+	C   <-chan *Result[T]
+	err error
+}
+
+func (s *Stream[T]) Err() error {
+	return s.err
+}
+
+// 返回已经结束的stream, 用于查找节点失败时
+func NewErrorStream[T any](err error) *Stream[T] {
+	ch := make(chan *Result[T])
+	close(ch)
+	return &Stream[T]{C: ch, err: err}
+}
+
+// 调用全部节点, 每个节点返回时调用f, f在同一个协程中调用
+// 达不到quorum时返回ErrQuorumNotReached
+func Gather[T any](ctx context.Context, peers []*gira.Peer, opts Options, call func(ctx context.Context, peer *gira.Peer) (T, error), f func(result *Result[T])) error {
+	if len(peers) <= 0 {
+		if opts.Quorum > 0 {
+			return errors.ErrQuorumNotReached
+		}
+		return nil
+	}
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+	concurrency := opts.Concurrency
+	if concurrency <= 0 || concurrency > len(peers) {
+		concurrency = len(peers)
+	}
+	ch := make(chan *Result[T], len(peers))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	go func() {
+		defer func() {
+			wg.Wait()
+			close(ch)
+		}()
+		for _, peer := range peers {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(peer *gira.Peer) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				resp, err := call(ctx, peer)
+				ch <- &Result[T]{Peer: peer, Response: resp, Err: err}
+			}(peer)
+		}
+	}()
+	var success, failure int
+	for result := range ch {
+		if result.Err != nil {
+			failure++
+		} else {
+			success++
+		}
+		if f != nil {
+			f(result)
+		}
+		if opts.Quorum <= 0 {
+			continue
+		}
+		if success >= opts.Quorum {
+			return nil
+		}
+		// 剩下的全部成功也达不到quorum
+		if len(peers)-failure < opts.Quorum {
+			return errors.ErrQuorumNotReached
+		}
+	}
+	if err := ctx.Err(); err != nil && success+failure < len(peers) {
+		return err
+	}
+	if opts.Quorum > 0 && success < opts.Quorum {
+		return errors.ErrQuorumNotReached
+	}
+	return nil
+}
+
+// 和Gather一样, 结果通过channel返回
+func GatherChan[T any](ctx context.Context, peers []*gira.Peer, opts Options, call func(ctx context.Context, peer *gira.Peer) (T, error)) *Stream[T] {
+	ch := make(chan *Result[T], len(peers))
+	s := &Stream[T]{C: ch}
+	go func() {
+		defer close(ch)
+		s.err = Gather(ctx, peers, opts, call, func(result *Result[T]) {
+			ch <- result
+		})
+	}()
+	return s
+}
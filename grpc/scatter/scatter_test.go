@@ -0,0 +1,66 @@
+package scatter
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+func newPeers(n int) []*gira.Peer {
+	peers := make([]*gira.Peer, 0, n)
+	for i := 0; i < n; i++ {
+		peers = append(peers, &gira.Peer{FullName: fmt.Sprintf("hall_%d", i)})
+	}
+	return peers
+}
+
+func TestGatherConcurrency(t *testing.T) {
+	var running, max int32
+	call := func(ctx context.Context, peer *gira.Peer) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return peer.FullName, nil
+	}
+	count := 0
+	err := Gather(context.Background(), newPeers(10), Options{Concurrency: 3}, call, func(result *Result[string]) {
+		count++
+	})
+	if err != nil || count != 10 || max > 3 {
+		t.Fatalf("unexpected result err=%v count=%d max=%d", err, count, max)
+	}
+}
+
+func TestGatherQuorum(t *testing.T) {
+	call := func(ctx context.Context, peer *gira.Peer) (string, error) {
+		if peer.FullName == "hall_0" {
+			// 达到quorum后被取消
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return peer.FullName, nil
+	}
+	if err := Gather(context.Background(), newPeers(3), Options{Quorum: 2}, call, nil); err != nil {
+		t.Fatalf("quorum should be reached, %v", err)
+	}
+	fail := func(ctx context.Context, peer *gira.Peer) (string, error) {
+		return "", errors.ErrRpcUnavailable
+	}
+	stream := GatherChan(context.Background(), newPeers(3), Options{Quorum: 2}, fail)
+	for range stream.C {
+	}
+	if !errors.Is(stream.Err(), errors.ErrQuorumNotReached) {
+		t.Fatalf("unexpected error %v", stream.Err())
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	log "github.com/Lyndon-Zhang/gira/corelog"
 
 	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/graceful"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
 // http://wzmmmmj.com/2020/09/06/grpc-stream/
@@ -21,14 +28,47 @@ type Server struct {
 	servers    map[string]interface{}
 	listener   net.Listener
 	mu         sync.Mutex
+	// 应用注册的拦截器, 每次调用都要读取, 注册时整体替换
+	unaryInterceptors  atomic.Value // []grpc.UnaryServerInterceptor
+	streamInterceptors atomic.Value // []grpc.StreamServerInterceptor
+	metrics            sync.Map     // 方法统计 map[string]*method_metric
+	// grpc.health.v1
+	health       *health.Server
+	lifecycle    int
+	downServices map[string]struct{}
 }
 
-func NewConfigServer(config gira.GrpcConfig) (*Server, error) {
-	opts := []grpc.ServerOption{grpc.NumStreamWorkers(config.Workers)}
+// creds为空时使用明文
+func NewConfigServer(config gira.GrpcConfig, creds *Credentials) (*Server, error) {
 	self := &Server{
-		config:  config,
-		server:  grpc.NewServer(opts...),
-		servers: make(map[string]interface{}),
+		config:       config,
+		servers:      make(map[string]interface{}),
+		health:       newHealthServer(),
+		lifecycle:    LIFECYCLE_STARTING,
+		downServices: make(map[string]struct{}),
+	}
+	// 客户端没有请求时也会按keepalive-time发送ping, 默认的MinTime是5分钟, 会被当成过多的ping断开
+	keepaliveTime := config.Conn.KeepaliveTime
+	if keepaliveTime <= 0 {
+		keepaliveTime = DEFAULT_CONN_KEEPALIVE_TIME
+	}
+	opts := []grpc.ServerOption{
+		grpc.NumStreamWorkers(config.Workers),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(keepaliveTime) * time.Second / 2,
+			PermitWithoutStream: true,
+		}),
+		grpc.ChainUnaryInterceptor(self.unaryInterceptor),
+		grpc.ChainStreamInterceptor(self.streamInterceptor),
+	}
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds.ServerCredentials()))
+	}
+	self.server = grpc.NewServer(opts...)
+	// 不保存到servers中
+	grpc_health_v1.RegisterHealthServer(self.server, self.health)
+	if config.Reflection {
+		reflection.Register(self.server)
 	}
 	if config.EnabledTrace {
 		grpc.EnableTracing = true
@@ -42,6 +82,9 @@ func (self *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
 	self.server.RegisterService(desc, impl)
 	self.mu.Lock()
 	self.servers[desc.ServiceName] = impl
+	if self.lifecycle != LIFECYCLE_STOPPED {
+		self.health.SetServingStatus(desc.ServiceName, self.lifecycleStatus())
+	}
 	self.mu.Unlock()
 }
 
@@ -53,7 +96,7 @@ func (self *Server) GetServer(name string) (svr interface{}, ok bool) {
 }
 
 func (self *Server) Listen() error {
-	if listener, err := net.Listen("tcp", self.config.Address); err != nil {
+	if listener, err := graceful.Listen("grpc", "tcp", self.config.Address); err != nil {
 		return err
 	} else {
 		self.listener = listener
@@ -79,6 +122,7 @@ func (self *Server) Stop() error {
 		return nil
 	}
 	log.Debugw("gpc server on stop1")
+	self.SetLifecycle(LIFECYCLE_STOPPED)
 	self.cancelFunc()
 	return nil
 }
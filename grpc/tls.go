@@ -0,0 +1,240 @@
+package grpc
+
+///
+/// 节点间的mTLS
+///
+/// 服务端和客户端使用同一套ca, cert, key
+///   - 双方都要求对方出示由ca签发的证书
+///   - 证书的CommonName或者DNSNames中要有一个是节点全名(例如hall_qq_dev_1)
+///   - 服务端要求客户端是已注册的节点, 客户端要求服务端正好是拨号的那个节点
+///   - 定时检查证书文件, 修改后重新加载, 新的连接使用新的证书
+///
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	DEFAULT_TLS_RELOAD_INTERVAL = 10 // 秒
+)
+
+type Credentials struct {
+	config   gira.GrpcTlsConfig
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+	verifier func(name string) bool
+	resolver func(target string) (string, bool)
+}
+
+func NewConfigCredentials(config gira.GrpcTlsConfig) (*Credentials, error) {
+	if config.ReloadInterval <= 0 {
+		config.ReloadInterval = DEFAULT_TLS_RELOAD_INTERVAL
+	}
+	self := &Credentials{
+		config:   config,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := self.load(); err != nil {
+		return nil, err
+	}
+	return self, nil
+}
+
+// 设置节点校验函数, 返回false时拒绝连接, 没有设置时只校验证书链
+func (self *Credentials) SetPeerVerifier(f func(name string) bool) {
+	self.mu.Lock()
+	self.verifier = f
+	self.mu.Unlock()
+}
+
+// 设置拨号目标(节点url中的全名或者地址)到节点全名的转换, 客户端只接受证书中有这个全名的节点
+// 没有设置时客户端和服务端一样, 只要求是已注册的节点
+func (self *Credentials) SetTargetResolver(f func(target string) (string, bool)) {
+	self.mu.Lock()
+	self.resolver = f
+	self.mu.Unlock()
+}
+
+// 服务端的证书
+func (self *Credentials) ServerCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// 证书链由verifyConnection校验, 这样才能使用重新加载后的ca
+		ClientAuth: tls.RequireAnyClientCert,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return self.getCert(), nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return self.verifyConnection(cs, "")
+		},
+	})
+}
+
+// 客户端的证书
+func (self *Credentials) ClientCredentials() credentials.TransportCredentials {
+	return &client_credentials{
+		TransportCredentials: credentials.NewTLS(self.clientConfig("")),
+		creds:                self,
+	}
+}
+
+func (self *Credentials) clientConfig(target string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// 按地址拨号, 不校验域名, 证书链和节点身份由verifyConnection校验
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return self.getCert(), nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return self.verifyConnection(cs, target)
+		},
+	}
+}
+
+// 每次握手按拨号的目标校验对方的身份
+type client_credentials struct {
+	credentials.TransportCredentials
+	creds *Credentials
+}
+
+func (c *client_credentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.creds.clientConfig(authority)).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *client_credentials) Clone() credentials.TransportCredentials {
+	return &client_credentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		creds:                c.creds,
+	}
+}
+
+// 定时检查证书文件是否修改
+func (self *Credentials) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(self.config.ReloadInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !self.isModified() {
+				continue
+			}
+			// 加载失败时继续使用旧的证书
+			if err := self.load(); err != nil {
+				log.Errorw("grpc tls reload fail", "error", err)
+			} else {
+				log.Infow("grpc tls reload")
+			}
+		}
+	}
+}
+
+func (self *Credentials) getCert() *tls.Certificate {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	return self.cert
+}
+
+func (self *Credentials) isModified() bool {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	for _, name := range []string{self.config.Ca, self.config.Cert, self.config.Key} {
+		if info, err := os.Stat(name); err == nil && !info.ModTime().Equal(self.modTimes[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *Credentials) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, name := range []string{self.config.Ca, self.config.Cert, self.config.Key} {
+		if info, err := os.Stat(name); err != nil {
+			return err
+		} else {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	cert, err := tls.LoadX509KeyPair(self.config.Cert, self.config.Key)
+	if err != nil {
+		return err
+	}
+	ca, err := os.ReadFile(self.config.Ca)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return fmt.Errorf("invalid ca file %s", self.config.Ca)
+	}
+	self.mu.Lock()
+	self.cert = &cert
+	self.pool = pool
+	self.modTimes = modTimes
+	self.mu.Unlock()
+	return nil
+}
+
+// 校验对方的证书链和节点身份, target为空时是服务端
+func (self *Credentials) verifyConnection(cs tls.ConnectionState, target string) error {
+	if len(cs.PeerCertificates) <= 0 {
+		return errors.ErrTlsCertificateRequired
+	}
+	self.mu.RLock()
+	pool := self.pool
+	verifier := self.verifier
+	resolver := self.resolver
+	self.mu.RUnlock()
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	leaf := cs.PeerCertificates[0]
+	if _, err := leaf.Verify(opts); err != nil {
+		return err
+	}
+	names := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+	if len(target) > 0 && resolver != nil {
+		fullName, ok := resolver(target)
+		if !ok {
+			log.Warnw("grpc tls target not registered", "target", target)
+			return errors.ErrTlsPeerNotRegistered
+		}
+		for _, name := range names {
+			if name == fullName {
+				return nil
+			}
+		}
+		log.Warnw("grpc tls peer mismatch", "target", target, "full_name", fullName, "common_name", leaf.Subject.CommonName, "dns_names", leaf.DNSNames)
+		return errors.ErrTlsPeerMismatch
+	}
+	if verifier == nil {
+		return nil
+	}
+	for _, name := range names {
+		if len(name) > 0 && verifier(name) {
+			return nil
+		}
+	}
+	log.Warnw("grpc tls peer not registered", "common_name", leaf.Subject.CommonName, "dns_names", leaf.DNSNames)
+	return errors.ErrTlsPeerNotRegistered
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+type test_ca struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCa(t *testing.T) *test_ca {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &test_ca{cert: cert, key: key}
+}
+
+func (ca *test_ca) issue(t *testing.T, name string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifyConnection(t *testing.T) {
+	ca := newTestCa(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	peers := map[string]string{
+		"hall_qq_dev_1": "127.0.0.1:1001",
+		"hall_qq_dev_2": "127.0.0.1:1002",
+	}
+	creds := &Credentials{pool: pool}
+	creds.SetPeerVerifier(func(name string) bool {
+		_, ok := peers[name]
+		return ok
+	})
+	creds.SetTargetResolver(func(target string) (string, bool) {
+		for fullName, address := range peers {
+			if target == fullName || target == address {
+				return fullName, true
+			}
+		}
+		return "", false
+	})
+	state := func(cert *x509.Certificate) tls.ConnectionState {
+		return tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+	hall1 := ca.issue(t, "hall_qq_dev_1")
+	hall2 := ca.issue(t, "hall_qq_dev_2")
+	unknown := ca.issue(t, "hall_qq_dev_3")
+	forged := newTestCa(t).issue(t, "hall_qq_dev_1")
+	tests := []struct {
+		name   string
+		state  tls.ConnectionState
+		target string
+		err    error
+	}{
+		{"server accepts registered peer", state(hall2), "", nil},
+		{"server rejects unregistered peer", state(unknown), "", errors.ErrTlsPeerNotRegistered},
+		{"client accepts dialed peer by url", state(hall1), "hall_qq_dev_1", nil},
+		{"client accepts dialed peer by address", state(hall1), "127.0.0.1:1001", nil},
+		{"client rejects other registered peer", state(hall2), "hall_qq_dev_1", errors.ErrTlsPeerMismatch},
+		{"client rejects unknown target", state(hall1), "127.0.0.1:9999", errors.ErrTlsPeerNotRegistered},
+		{"no certificate", tls.ConnectionState{}, "hall_qq_dev_1", errors.ErrTlsCertificateRequired},
+	}
+	for _, tt := range tests {
+		if err := creds.verifyConnection(tt.state, tt.target); err != tt.err {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+	// 其他ca签发的证书
+	if err := creds.verifyConnection(state(forged), "hall_qq_dev_1"); err == nil {
+		t.Error("expected certificate from other ca rejected")
+	}
+}
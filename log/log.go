@@ -15,6 +15,23 @@ func GetDefaultLogger() gira.Logger {
 	return defaultLogger
 }
 
+// 重新打开日志文件
+func Rotate() error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.Rotate()
+	}
+	return nil
+}
+
+// 切换debug级别, 返回切换后的状态
+func ToggleDebug() bool {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		l.SetDebug(!l.IsDebug())
+		return l.IsDebug()
+	}
+	return false
+}
+
 func Config(config gira.LogConfig) error {
 	var err error
 	if defaultLogger, err = logger.NewConfigLogger(config); err != nil {
@@ -24,6 +41,21 @@ func Config(config gira.LogConfig) error {
 	}
 }
 
+// 修改日志级别, 不重新创建输出
+func CheckLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.CheckLevels(config)
+	}
+	return nil
+}
+
+func SetLevels(config gira.LogConfig) error {
+	if l, ok := defaultLogger.(*logger.Logger); ok {
+		return l.SetLevels(config)
+	}
+	return nil
+}
+
 func ConfigAsCli(logDir string) error {
 	var err error
 	if defaultLogger, err = logger.NewDefaultCliLogger(logDir); err != nil {
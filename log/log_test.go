@@ -0,0 +1,75 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"gopkg.in/yaml.v3"
+)
+
+// 信号动作toggle-debug和reopen-log
+func TestToggleDebugAndRotate(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.log")
+	var config gira.LogConfig
+	text := fmt.Sprintf("level: info\nfiles:\n  - path: %s\n    format: console\n", filePath)
+	if err := yaml.Unmarshal([]byte(text), &config); err != nil {
+		t.Fatal(err)
+	}
+	if err := Config(config); err != nil {
+		t.Fatal(err)
+	}
+	read := func() string {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	Debugw("debug before toggle")
+	Infow("info before toggle")
+	if v := read(); strings.Contains(v, "debug before toggle") || !strings.Contains(v, "info before toggle") {
+		t.Fatalf("unexpected output %q", v)
+	}
+	if !ToggleDebug() {
+		t.Fatal("expected debug enabled")
+	}
+	Debugw("debug after toggle")
+	if v := read(); !strings.Contains(v, "debug after toggle") {
+		t.Fatalf("expected debug output, got %q", v)
+	}
+	if ToggleDebug() {
+		t.Fatal("expected debug disabled")
+	}
+	Debugw("debug after toggle back")
+	if v := read(); strings.Contains(v, "debug after toggle back") {
+		t.Fatalf("unexpected debug output %q", v)
+	}
+
+	// 旧的文件按滚动规则改名保存, 重新打开新的文件
+	if err := Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	Infow("info after rotate")
+	if v := read(); strings.Contains(v, "before toggle") || !strings.Contains(v, "info after rotate") {
+		t.Fatalf("expected new file, got %q", v)
+	}
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %v", backups)
+	}
+	data, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "info before toggle") {
+		t.Fatalf("unexpected backup %q", string(data))
+	}
+}
@@ -3,8 +3,10 @@ package logger
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/errors"
@@ -17,8 +19,108 @@ import (
 )
 
 type Logger struct {
-	logger *zap.Logger
-	sugar  *zap.SugaredLogger
+	logger  *zap.Logger
+	sugar   *zap.SugaredLogger
+	debug   *int32               // 临时打开debug级别
+	rotates []*lumberjack.Logger // 滚动文件
+	levels  []*level_binding     // 可以动态修改的级别
+	fields  []string             // 每个输出的级别由哪个配置字段决定, 见levelFields
+}
+
+// 级别和配置字段的对应关系
+type level_binding struct {
+	level zap.AtomicLevel
+	field func(config gira.LogConfig) string
+}
+
+// 每个输出的级别由哪个配置字段决定, 顺序和NewConfigLogger中创建输出的顺序一样, 没有级别的输出为空
+func levelFields(config gira.LogConfig) []string {
+	fields := make([]string, 0)
+	if config.Console {
+		fields = append(fields, "level")
+	}
+	for index, file := range config.Files {
+		if file.Filter != "" {
+			fields = append(fields, fmt.Sprintf("files.%d.filter", index))
+		} else if file.Level != "" {
+			fields = append(fields, fmt.Sprintf("files.%d.level", index))
+		} else if config.Level != "" {
+			fields = append(fields, "level")
+		} else {
+			fields = append(fields, "")
+		}
+	}
+	if config.Db {
+		fields = append(fields, "db-level")
+	}
+	return fields
+}
+
+// 检查新的配置能不能直接生效, 输出的数量和顺序, 以及决定级别的字段都要和创建时一样
+func (l *Logger) CheckLevels(config gira.LogConfig) error {
+	if l.fields == nil {
+		return nil
+	}
+	fields := levelFields(config)
+	if len(fields) != len(l.fields) {
+		return errors.ErrLogLevelFieldChanged
+	}
+	for i, field := range fields {
+		if field != l.fields[i] {
+			return errors.ErrLogLevelFieldChanged
+		}
+	}
+	for _, binding := range l.levels {
+		var level zapcore.Level
+		text := binding.field(config)
+		// 空字符串会被解析成info
+		if len(text) <= 0 {
+			return errors.ErrInvalidLogLevel
+		}
+		if err := level.UnmarshalText([]byte(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 按新的配置修改各个输出的级别, 先调用CheckLevels检查
+func (l *Logger) SetLevels(config gira.LogConfig) error {
+	if err := l.CheckLevels(config); err != nil {
+		return err
+	}
+	for _, binding := range l.levels {
+		var level zapcore.Level
+		level.UnmarshalText([]byte(binding.field(config)))
+		binding.level.SetLevel(level)
+	}
+	return nil
+}
+
+// 关闭并重新打开日志文件, 旧的文件按滚动规则保存
+func (l *Logger) Rotate() error {
+	for _, r := range l.rotates {
+		if err := r.Rotate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 临时打开debug级别, 不影响按filter输出的文件
+func (l *Logger) SetDebug(enabled bool) {
+	if l.debug == nil {
+		return
+	}
+	if enabled {
+		atomic.StoreInt32(l.debug, 1)
+	} else {
+		atomic.StoreInt32(l.debug, 0)
+	}
+}
+
+func (l *Logger) IsDebug() bool {
+	return l.debug != nil && atomic.LoadInt32(l.debug) == 1
 }
 
 func (l *Logger) Infow(msg string, kvs ...interface{}) {
@@ -85,8 +187,12 @@ func (l *Logger) Named(s string) gira.Logger {
 	logger := l.logger.Named(s)
 	sugar := logger.Sugar()
 	return &Logger{
-		sugar:  sugar,
-		logger: logger,
+		sugar:   sugar,
+		logger:  logger,
+		debug:   l.debug,
+		rotates: l.rotates,
+		levels:  l.levels,
+		fields:  l.fields,
 	}
 }
 
@@ -185,6 +291,9 @@ func NewDefaultCliLogger(logDir string) (*Logger, error) {
 
 func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 	cores := make([]zapcore.Core, 0)
+	debug := new(int32)
+	rotates := make([]*lumberjack.Logger, 0)
+	levels := make([]*level_binding, 0)
 	// 1.控制台输出
 	if config.Console {
 		var level zap.AtomicLevel
@@ -192,8 +301,9 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 		if err != nil {
 			return nil, err
 		}
+		levels = append(levels, &level_binding{level: level, field: func(c gira.LogConfig) string { return c.Level }})
 		enabler := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-			return lvl >= level.Level()
+			return lvl >= level.Level() || atomic.LoadInt32(debug) == 1
 		})
 		encoderConfig := zapcore.EncoderConfig{
 			TimeKey:        EncoderKey_time,
@@ -217,7 +327,8 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 	}
 
 	// 2.滚动文件输出
-	for _, file := range config.Files {
+	for index, file := range config.Files {
+		index := index
 		var enabler zap.LevelEnablerFunc
 		if file.Filter != "" {
 			var level zap.AtomicLevel
@@ -225,6 +336,7 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 			if err != nil {
 				return nil, err
 			}
+			levels = append(levels, &level_binding{level: level, field: func(c gira.LogConfig) string { return c.Files[index].Filter }})
 			enabler = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 				return lvl == level.Level()
 			})
@@ -234,8 +346,9 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 			if err != nil {
 				return nil, err
 			}
+			levels = append(levels, &level_binding{level: level, field: func(c gira.LogConfig) string { return c.Files[index].Level }})
 			enabler = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= level.Level()
+				return lvl >= level.Level() || atomic.LoadInt32(debug) == 1
 			})
 		} else if config.Level != "" {
 			var level zap.AtomicLevel
@@ -243,8 +356,9 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 			if err != nil {
 				return nil, err
 			}
+			levels = append(levels, &level_binding{level: level, field: func(c gira.LogConfig) string { return c.Level }})
 			enabler = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= level.Level()
+				return lvl >= level.Level() || atomic.LoadInt32(debug) == 1
 			})
 		}
 		encoderCfg := zapcore.EncoderConfig{
@@ -266,15 +380,17 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 		} else {
 			format = zapcore.NewJSONEncoder(encoderCfg)
 		}
+		rotate := &lumberjack.Logger{
+			Filename:   file.Path,       // 日志文件路径
+			MaxSize:    file.MaxSize,    // 每个日志文件的最大大小，单位为 MB
+			MaxBackups: file.MaxBackups, // 保留的旧日志文件的最大个数
+			MaxAge:     file.MaxAge,     // 保留的旧日志文件的最大天数
+			Compress:   file.Compress,   // 是否压缩旧日志文件
+		}
+		rotates = append(rotates, rotate)
 		rollingCore := zapcore.NewCore(
 			format, // 滚动日志输出格式
-			zapcore.AddSync(&lumberjack.Logger{
-				Filename:   file.Path,       // 日志文件路径
-				MaxSize:    file.MaxSize,    // 每个日志文件的最大大小，单位为 MB
-				MaxBackups: file.MaxBackups, // 保留的旧日志文件的最大个数
-				MaxAge:     file.MaxAge,     // 保留的旧日志文件的最大天数
-				Compress:   file.Compress,   // 是否压缩旧日志文件
-			}),
+			zapcore.AddSync(rotate),
 			enabler,
 		)
 		cores = append(cores, rollingCore)
@@ -286,6 +402,7 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 		if err != nil {
 			return nil, err
 		}
+		levels = append(levels, &level_binding{level: level, field: func(c gira.LogConfig) string { return c.DbLevel }})
 		enabler := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 			return lvl >= level.Level()
 		})
@@ -319,8 +436,12 @@ func NewConfigLogger(config gira.LogConfig) (*Logger, error) {
 	logger = logger.WithOptions(zap.WithCaller(true), zap.AddCallerSkip(2))
 	sugar := logger.Sugar()
 	l := &Logger{
-		logger: logger,
-		sugar:  sugar,
+		logger:  logger,
+		sugar:   sugar,
+		debug:   debug,
+		rotates: rotates,
+		levels:  levels,
+		fields:  levelFields(config),
 	}
 	return l, nil
 }
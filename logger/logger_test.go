@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetLevels(t *testing.T) {
+	dir := t.TempDir()
+	text := `
+console: true
+level: %s
+files:
+  - path: %s
+    level: info
+  - path: %s
+    filter: %s
+`
+	config := func(level string, filter string) gira.LogConfig {
+		var config gira.LogConfig
+		text := fmt.Sprintf(text, level, filepath.Join(dir, "all.log"), filepath.Join(dir, "filter.log"), filter)
+		if err := yaml.Unmarshal([]byte(text), &config); err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+	l, err := NewConfigLogger(config("info", "error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(levels ...zapcore.Level) {
+		for i, level := range levels {
+			if l.levels[i].level.Level() != level {
+				t.Fatalf("output %d: expected %v, got %v", i, level, l.levels[i].level.Level())
+			}
+		}
+	}
+	if err := l.SetLevels(config("warn", "fatal")); err != nil {
+		t.Fatal(err)
+	}
+	expect(zapcore.WarnLevel, zapcore.InfoLevel, zapcore.FatalLevel)
+	// 空字符串不能当成info
+	if err := l.SetLevels(config(`""`, "fatal")); err != errors.ErrInvalidLogLevel {
+		t.Fatalf("expected invalid level, got %v", err)
+	}
+	if err := l.SetLevels(config("warn", "xxx")); err == nil {
+		t.Fatal("expected invalid level")
+	}
+	// 第二个文件原来由filter决定, 去掉filter后改由log.level决定, 需要重启
+	if err := l.SetLevels(config("debug", `""`)); err != errors.ErrLogLevelFieldChanged {
+		t.Fatalf("expected field changed, got %v", err)
+	}
+	// 检查失败时不修改任何输出
+	expect(zapcore.WarnLevel, zapcore.InfoLevel, zapcore.FatalLevel)
+}
@@ -0,0 +1,54 @@
+package cron_options
+
+import "time"
+
+// ====== add options ===================
+
+// 任务使用的时区, 表达式中的 TZ= 优先
+func WithLocationOption(location *time.Location) LocationOption {
+	return LocationOption{
+		location: location,
+	}
+}
+
+// 同类型的节点中只有一个执行
+func WithSingletonOption() SingletonOption {
+	return SingletonOption{}
+}
+
+// 启动时如果错过了执行时间, 马上补执行一次
+func WithCatchUpOption() CatchUpOption {
+	return CatchUpOption{}
+}
+
+type AddOptions struct {
+	Location  *time.Location
+	Singleton bool
+	CatchUp   bool
+}
+
+type AddOption interface {
+	ConfigAddOption(opts *AddOptions)
+}
+
+type LocationOption struct {
+	location *time.Location
+}
+
+func (opt LocationOption) ConfigAddOption(opts *AddOptions) {
+	opts.Location = opt.location
+}
+
+type SingletonOption struct {
+}
+
+func (opt SingletonOption) ConfigAddOption(opts *AddOptions) {
+	opts.Singleton = true
+}
+
+type CatchUpOption struct {
+}
+
+func (opt CatchUpOption) ConfigAddOption(opts *AddOptions) {
+	opts.CatchUp = true
+}
@@ -1,5 +1,7 @@
 package service_options
 
+import "time"
+
 // ====== register options ===================
 
 // app内唯一的服务
@@ -30,12 +32,34 @@ func WithWhereCatalogOption() WhereCatalogOption {
 	return WhereCatalogOption{}
 }
 
+// 按key一致性hash查找, 从目录下的服务构建hash环
+func WithWhereKeyOption(key string) WhereKeyOption {
+	return WhereKeyOption{
+		key: key,
+	}
+}
+
+// 在指定的区查找
+func WithWhereZoneOption(zone string) WhereZoneOption {
+	return WhereZoneOption{
+		zone: zone,
+	}
+}
+
+// 在全部区查找, 本区的优先
+func WithWhereAllZoneOption() WhereAllZoneOption {
+	return WhereAllZoneOption{}
+}
+
 // ====== where options ===================
 type WhereOptions struct {
 	MaxCount int
 	Regex    bool
 	Prefix   bool
 	Catalog  bool
+	Key      string
+	Zone     string
+	AllZone  bool
 }
 
 type WhereOption interface {
@@ -71,6 +95,29 @@ func (opt WhereCatalogOption) ConfigWhereOption(opts *WhereOptions) {
 	opts.Catalog = true
 }
 
+type WhereKeyOption struct {
+	key string
+}
+
+func (opt WhereKeyOption) ConfigWhereOption(opts *WhereOptions) {
+	opts.Key = opt.key
+}
+
+type WhereZoneOption struct {
+	zone string
+}
+
+func (opt WhereZoneOption) ConfigWhereOption(opts *WhereOptions) {
+	opts.Zone = opt.zone
+}
+
+type WhereAllZoneOption struct {
+}
+
+func (opt WhereAllZoneOption) ConfigWhereOption(opts *WhereOptions) {
+	opts.AllZone = true
+}
+
 // ====== register options ===================
 
 type RegisterOptions struct {
@@ -88,3 +135,74 @@ type AsAppServiceOption struct {
 func (opt AsAppServiceOption) ConfigRegisterOption(opts *RegisterOptions) {
 	opts.AsAppService = true
 }
+
+// ====== start options ===================
+// 服务的重启策略
+const (
+	RESTART_NEVER      = iota // 不重启, 出错时只停止这个服务
+	RESTART_ON_FAILURE        // 返回错误或者panic时重启
+	RESTART_ALWAYS            // 正常返回也重启
+)
+
+// 重启策略, 第一次重启等待backoff, 之后每次翻倍, 不超过maxBackoff
+func WithStartRestartOption(policy int, backoff time.Duration, maxBackoff time.Duration) StartRestartOption {
+	return StartRestartOption{
+		policy:     policy,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// 最多重启的次数, 超过后服务变成failed, 0表示不限制
+func WithStartMaxRestartsOption(count int) StartMaxRestartsOption {
+	return StartMaxRestartsOption{
+		count: count,
+	}
+}
+
+// 停止服务时等待OnStop的超时时间
+func WithStartStopTimeoutOption(timeout time.Duration) StartStopTimeoutOption {
+	return StartStopTimeoutOption{
+		timeout: timeout,
+	}
+}
+
+type StartOptions struct {
+	RestartPolicy int
+	Backoff       time.Duration
+	MaxBackoff    time.Duration
+	MaxRestarts   int
+	StopTimeout   time.Duration
+}
+
+type StartOption interface {
+	ConfigStartOption(opts *StartOptions)
+}
+
+type StartRestartOption struct {
+	policy     int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func (opt StartRestartOption) ConfigStartOption(opts *StartOptions) {
+	opts.RestartPolicy = opt.policy
+	opts.Backoff = opt.backoff
+	opts.MaxBackoff = opt.maxBackoff
+}
+
+type StartMaxRestartsOption struct {
+	count int
+}
+
+func (opt StartMaxRestartsOption) ConfigStartOption(opts *StartOptions) {
+	opts.MaxRestarts = opt.count
+}
+
+type StartStopTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (opt StartStopTimeoutOption) ConfigStartOption(opts *StartOptions) {
+	opts.StopTimeout = opt.timeout
+}
@@ -3,15 +3,37 @@ package proj
 import (
 	"fmt"
 	"path"
+	"sync/atomic"
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/config"
 )
 
 var (
-	Config *gira.Config
+	// 启动时读取的配置, 重新加载时不会修改, 运行中使用GetConfig
+	Config  *gira.Config
+	current atomic.Value // *gira.Config
 )
 
+func setConfig(c *gira.Config) {
+	Config = c
+	current.Store(c)
+}
+
+// 返回当前生效的配置, 重新加载后返回新的配置
+// 协程安全
+func GetConfig() *gira.Config {
+	if v := current.Load(); v != nil {
+		return v.(*gira.Config)
+	}
+	return nil
+}
+
+// 重新加载配置后发布新的配置
+func PublishConfig(c *gira.Config) {
+	current.Store(c)
+}
+
 // 读取应该配置
 func LoadCliConfig() (*gira.Config, error) {
 	configFilePath := path.Join(Dir.ConfigDir, "cli.yaml")
@@ -19,7 +41,7 @@ func LoadCliConfig() (*gira.Config, error) {
 	if c, err := config.Load(Dir.ProjectDir, configFilePath, dotEnvFilePath, "cli", 0); err != nil {
 		return nil, err
 	} else {
-		Config = c
+		setConfig(c)
 		return c, nil
 	}
 }
@@ -31,19 +53,24 @@ func LoadTestConfig() (*gira.Config, error) {
 	if c, err := config.Load(Dir.ProjectDir, configFilePath, dotEnvFilePath, "test", 0); err != nil {
 		return nil, err
 	} else {
-		Config = c
+		setConfig(c)
 		return c, nil
 	}
 }
 
 // 读取应用配置
 func LoadApplicationConfig(appType string, appId int32) (*gira.Config, error) {
-	configFilePath := path.Join(Dir.ConfigDir, fmt.Sprintf("%s.yaml", appType))
-	dotEnvFilePath := path.Join(Dir.EnvDir, ".env")
-	if c, err := config.Load(Dir.ProjectDir, configFilePath, dotEnvFilePath, appType, appId); err != nil {
+	if c, err := ReadApplicationConfig(appType, appId); err != nil {
 		return nil, err
 	} else {
-		Config = c
+		setConfig(c)
 		return c, nil
 	}
 }
+
+// 读取应用配置, 不修改当前的配置, 用于重新加载
+func ReadApplicationConfig(appType string, appId int32) (*gira.Config, error) {
+	configFilePath := path.Join(Dir.ConfigDir, fmt.Sprintf("%s.yaml", appType))
+	dotEnvFilePath := path.Join(Dir.EnvDir, ".env")
+	return config.Load(Dir.ProjectDir, configFilePath, dotEnvFilePath, appType, appId)
+}
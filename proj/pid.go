@@ -0,0 +1,33 @@
+package proj
+
+///
+/// 服务进程的pid文件, 在run目录下, 按服务名和id命名, 例如 run/hall_1.pid
+///
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Lyndon-Zhang/gira/util/process"
+)
+
+func PidFilePath(appType string, appId int32) string {
+	return filepath.Join(Dir.RunDir, fmt.Sprintf("%s_%d.pid", appType, appId))
+}
+
+func WritePidFile(appType string, appId int32, pid int) error {
+	return process.WritePidFile(PidFilePath(appType, appId), pid)
+}
+
+func ReadPidFile(appType string, appId int32) (int, error) {
+	return process.ReadPidFile(PidFilePath(appType, appId))
+}
+
+// 文件中的pid和参数一致才删除, 零停机重启时新进程已经写入了自己的pid
+func RemovePidFile(appType string, appId int32, pid int) error {
+	return process.RemovePidFile(PidFilePath(appType, appId), pid)
+}
+
+// 进程是否存在
+func IsProcessAlive(pid int) bool {
+	return process.IsAlive(pid)
+}
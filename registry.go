@@ -22,6 +22,14 @@ type Registry interface {
 	WhereIsPeer(appFullName string) (*Peer, error)
 	// 自身节点
 	SelfPeer() *Peer
+	// 玩家位置缓存统计
+	UserCacheStats() UserCacheStats
+	// 配置中心的值
+	GetConfigValue(key string) (string, bool)
+	// 侦听配置中心的变化
+	WatchConfig(prefix string, f func(change *ConfigChange))
+	// 覆盖了配置中心的值后的配置
+	GetEffectiveConfig() *Config
 }
 
 type RegistryClient interface {
@@ -34,6 +42,8 @@ type RegistryClient interface {
 	ListServiceKvs() (services map[string][]string, err error)
 	// 查找节点
 	WhereIsPeer(appFullName string) (*Peer, error)
+	// 玩家位置缓存统计
+	UserCacheStats() UserCacheStats
 }
 
 // 伙伴节点
@@ -41,6 +51,7 @@ type Peer struct {
 	Name     string // 服务类型
 	Id       int32  // 服务id
 	FullName string // 服务全名
+	Zone     string // 所在的区
 	Address  string // grpc地址
 	Url      string
 	Metadata map[string]string // /server/account_1/ 下的键
@@ -53,6 +64,25 @@ type LocalPlayer struct {
 	CreateRevision int64
 }
 
+// 玩家位置缓存统计
+type UserCacheStats struct {
+	Enabled   bool
+	Watching  bool  // 是否正在侦听
+	Size      int   // 当前缓存数量
+	Hits      int64 // 命中次数
+	Misses    int64 // 未命中次数
+	Evictions int64 // lru淘汰次数
+}
+
+// 配置中心的变化
+type ConfigChange struct {
+	Key       string
+	Value     string
+	PrevValue string
+	Deleted   bool
+	Revision  int64
+}
+
 // 服务名
 type ServiceName struct {
 	// <<GroupName>>/<<ShortName>>
@@ -83,3 +113,8 @@ type ServiceWatchHandler interface {
 	OnServiceDelete(service *ServiceName)
 	OnServiceUpdate(service *ServiceName)
 }
+
+// 侦听配置中心的变化
+type ConfigChangeHandler interface {
+	OnConfigChange(change *ConfigChange)
+}
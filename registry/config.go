@@ -0,0 +1,260 @@
+package registry
+
+///
+/// 配置中心
+///
+/// 注册表结构:
+///   /config/<<Key>> => <<Value>>
+///
+/// key为配置文件中的yaml路径, 用.分隔, 例如 module.behavior.sync-interval
+/// 值会覆盖到配置文件上, 通过GetEffectiveConfig读取
+///
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/config"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/facade"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	config_retry_interval = 3 * time.Second
+)
+
+type config_watcher struct {
+	prefix string
+	f      func(change *gira.ConfigChange)
+}
+
+type config_registry struct {
+	prefix             string // /config/
+	mu                 sync.Mutex
+	values             map[string]string
+	watchers           []*config_watcher
+	effectiveConfig    *gira.Config
+	effectiveBase      *gira.Config // effectiveConfig是基于哪个配置计算的, 配置文件重新加载后会变化
+	ctx                context.Context
+	cancelFunc         context.CancelFunc
+	watchStartRevision int64
+}
+
+func newConfigConfigRegistry(r *Registry) (*config_registry, error) {
+	ctx, cancelFunc := context.WithCancel(r.ctx)
+	self := &config_registry{
+		prefix:     "/config/",
+		values:     make(map[string]string),
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+	}
+	return self, nil
+}
+
+func (self *config_registry) stop(r *Registry) error {
+	log.Debug("config registry on stop")
+	self.cancelFunc()
+	return nil
+}
+
+// 加载全部的配置
+func (self *config_registry) initConfigs(r *Registry) error {
+	getResp, err := self.list(r)
+	if err != nil {
+		return err
+	}
+	self.replaceValues(getResp)
+	self.watchStartRevision = getResp.Header.Revision + 1
+	log.Debugw("config registry init", "size", len(self.values), "revision", getResp.Header.Revision)
+	return nil
+}
+
+func (self *config_registry) list(r *Registry) (*clientv3.GetResponse, error) {
+	return r.client.Get(self.ctx, self.prefix, clientv3.WithPrefix())
+}
+
+// 重新拉取全部的配置, 通知侦听期间丢失的修改, 返回开始侦听的版本
+func (self *config_registry) resyncConfigs(r *Registry) (int64, error) {
+	getResp, err := self.list(r)
+	if err != nil {
+		return 0, err
+	}
+	changes := self.replaceValues(getResp)
+	self.notify(changes)
+	return getResp.Header.Revision + 1, nil
+}
+
+// 用全量数据替换本地的配置, 返回有变化的配置
+func (self *config_registry) replaceValues(getResp *clientv3.GetResponse) []*gira.ConfigChange {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	values, changes := diffConfigValues(self.prefix, self.values, getResp.Kvs, getResp.Header.Revision)
+	self.values = values
+	self.rebuildEffectiveConfig()
+	return changes
+}
+
+// 比较全量数据和本地的配置, 返回新的配置和有变化的配置
+// 被删除的配置没有修改版本, 使用rev
+func diffConfigValues(prefix string, prev map[string]string, kvs []*mvccpb.KeyValue, rev int64) (map[string]string, []*gira.ConfigChange) {
+	values := make(map[string]string, len(kvs))
+	changes := make([]*gira.ConfigChange, 0)
+	for _, kv := range kvs {
+		if len(kv.Key) <= len(prefix) {
+			continue
+		}
+		key := string(kv.Key[len(prefix):])
+		value := string(kv.Value)
+		values[key] = value
+		if v, ok := prev[key]; !ok || v != value {
+			changes = append(changes, &gira.ConfigChange{
+				Key:       key,
+				Value:     value,
+				PrevValue: v,
+				Revision:  kv.ModRevision,
+			})
+		}
+	}
+	for key, v := range prev {
+		if _, ok := values[key]; !ok {
+			changes = append(changes, &gira.ConfigChange{
+				Key:       key,
+				PrevValue: v,
+				Deleted:   true,
+				Revision:  rev,
+			})
+		}
+	}
+	return values, changes
+}
+
+// 侦听配置, 出错, 被取消或者版本已经被压缩时, 重新拉取全量数据, 从新的版本继续侦听
+func (self *config_registry) watchConfigs(r *Registry) error {
+	rev := self.watchStartRevision
+	for {
+		if rev == 0 {
+			var err error
+			if rev, err = self.resyncConfigs(r); err != nil {
+				log.Warnw("config registry resync fail", "prefix", self.prefix, "error", err)
+				select {
+				case <-self.ctx.Done():
+					return nil
+				case <-time.After(config_retry_interval):
+				}
+				continue
+			}
+		}
+		ctx, cancelFunc := context.WithCancel(self.ctx)
+		watchRespChan := r.client.Watch(ctx, self.prefix, clientv3.WithRev(rev), clientv3.WithPrefix(), clientv3.WithPrevKV())
+		log.Debugw("config registry started", "prefix", self.prefix, "watch_start_revision", rev)
+		for watchResp := range watchRespChan {
+			if err := watchResp.Err(); err != nil {
+				log.Warnw("config registry watch fail", "prefix", self.prefix, "compact_revision", watchResp.CompactRevision, "error", err)
+				break
+			}
+			for _, event := range watchResp.Events {
+				self.onKvEvent(r, event)
+			}
+		}
+		cancelFunc()
+		if self.ctx.Err() != nil {
+			log.Debugw("config registry watch exit", "prefix", self.prefix)
+			return nil
+		}
+		// 中间的事件可能已经丢失, 重新拉取全量数据
+		rev = 0
+	}
+}
+
+func (self *config_registry) onKvEvent(r *Registry, event *clientv3.Event) {
+	if len(event.Kv.Key) <= len(self.prefix) {
+		return
+	}
+	change := &gira.ConfigChange{
+		Key:      string(event.Kv.Key[len(self.prefix):]),
+		Revision: event.Kv.ModRevision,
+	}
+	self.mu.Lock()
+	change.PrevValue = self.values[change.Key]
+	switch event.Type {
+	case mvccpb.PUT:
+		change.Value = string(event.Kv.Value)
+		self.values[change.Key] = change.Value
+	case mvccpb.DELETE:
+		change.Deleted = true
+		delete(self.values, change.Key)
+	}
+	self.rebuildEffectiveConfig()
+	self.mu.Unlock()
+	self.notify([]*gira.ConfigChange{change})
+}
+
+// 通知侦听者, 不需要持有锁
+func (self *config_registry) notify(changes []*gira.ConfigChange) {
+	if len(changes) <= 0 {
+		return
+	}
+	self.mu.Lock()
+	watchers := self.watchers
+	self.mu.Unlock()
+	for _, change := range changes {
+		log.Infow("config change", "key", change.Key, "value", change.Value, "prev_value", change.PrevValue, "deleted", change.Deleted)
+		for _, w := range watchers {
+			if strings.HasPrefix(change.Key, w.prefix) {
+				w.f(change)
+			}
+		}
+	}
+}
+
+// 重新计算覆盖后的配置, 需要持有锁
+func (self *config_registry) rebuildEffectiveConfig() {
+	c := facade.GetConfig()
+	if c == nil {
+		return
+	}
+	self.effectiveBase = c
+	if len(self.values) <= 0 {
+		self.effectiveConfig = c
+		return
+	}
+	if v, err := config.Overlay(c.Raw, self.values); err != nil {
+		log.Warnw("config overlay fail", "error", err)
+	} else {
+		self.effectiveConfig = v
+	}
+}
+
+func (self *config_registry) getValue(r *Registry, key string) (string, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	v, ok := self.values[key]
+	return v, ok
+}
+
+func (self *config_registry) watch(r *Registry, prefix string, f func(change *gira.ConfigChange)) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	// 修改时替换成新的数组, 通知时不需要持有锁
+	watchers := make([]*config_watcher, 0, len(self.watchers)+1)
+	watchers = append(watchers, self.watchers...)
+	watchers = append(watchers, &config_watcher{prefix: prefix, f: f})
+	self.watchers = watchers
+}
+
+func (self *config_registry) getEffectiveConfig(r *Registry) *gira.Config {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	// 配置文件重新加载过, 重新覆盖
+	if c := facade.GetConfig(); c != self.effectiveBase {
+		self.rebuildEffectiveConfig()
+	}
+	if self.effectiveConfig == nil {
+		return facade.GetConfig()
+	}
+	return self.effectiveConfig
+}
@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/config"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// 侦听中断后用全量数据补发丢失的修改
+func TestDiffConfigValues(t *testing.T) {
+	prev := map[string]string{
+		"log.level":            "info",
+		"module.gateway.debug": "false",
+		"cron.daily":           "0 0 * * *",
+	}
+	kvs := []*mvccpb.KeyValue{
+		{Key: []byte("/config/log.level"), Value: []byte("info"), ModRevision: 3},
+		{Key: []byte("/config/module.gateway.debug"), Value: []byte("true"), ModRevision: 8},
+		{Key: []byte("/config/module.trace.sample-ratio"), Value: []byte("0.5"), ModRevision: 9},
+		{Key: []byte("/config/"), Value: []byte("ignored"), ModRevision: 9},
+	}
+	values, changes := diffConfigValues("/config/", prev, kvs, 10)
+	if len(values) != 3 || values["module.gateway.debug"] != "true" || values["module.trace.sample-ratio"] != "0.5" {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if _, ok := values["cron.daily"]; ok {
+		t.Fatal("expected deleted value removed")
+	}
+	expected := map[string]struct {
+		value     string
+		prevValue string
+		deleted   bool
+		revision  int64
+	}{
+		"module.gateway.debug":      {"true", "false", false, 8},
+		"module.trace.sample-ratio": {"0.5", "", false, 9},
+		"cron.daily":                {"", "0 0 * * *", true, 10},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d", len(expected), len(changes))
+	}
+	for _, change := range changes {
+		e, ok := expected[change.Key]
+		if !ok {
+			t.Fatalf("unexpected change %v", change)
+		}
+		if change.Value != e.value || change.PrevValue != e.prevValue || change.Deleted != e.deleted || change.Revision != e.revision {
+			t.Errorf("%s: unexpected change %v", change.Key, change)
+		}
+	}
+}
+
+type test_runtime struct {
+	gira.Runtime
+	config *gira.Config
+}
+
+func (r *test_runtime) GetConfig() *gira.Config {
+	return r.config
+}
+
+// 配置文件重新加载后, 覆盖后的配置基于新的配置
+func TestEffectiveConfigReload(t *testing.T) {
+	load := func(raw string) *gira.Config {
+		c, err := config.Overlay([]byte(raw), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	runtime := &test_runtime{config: load("env: dev\nlog:\n  level: info\n  console: false\n")}
+	gira.OnApplicationCreate(runtime)
+	defer gira.OnApplicationCreate(nil)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	self := &config_registry{prefix: "/config/", values: make(map[string]string), ctx: ctx, cancelFunc: cancelFunc}
+	self.rebuildEffectiveConfig()
+	if c := self.getEffectiveConfig(nil); c != runtime.config {
+		t.Fatal("expected file config without overrides")
+	}
+	// 没有覆盖时直接返回新的配置
+	runtime.config = load("env: dev\nlog:\n  level: debug\n  console: false\n")
+	if c := self.getEffectiveConfig(nil); c != runtime.config {
+		t.Fatal("expected reloaded config without overrides")
+	}
+	self.onKvEvent(nil, &clientv3.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("/config/log.console"), Value: []byte("true")}})
+	if c := self.getEffectiveConfig(nil); c.Log.Level != "debug" || !c.Log.Console {
+		t.Fatalf("expected overlay, got %v", c.Log)
+	}
+	runtime.config = load("env: dev\nlog:\n  level: warn\n  console: false\n")
+	if c := self.getEffectiveConfig(nil); c.Log.Level != "warn" || !c.Log.Console {
+		t.Fatalf("expected overlay on reloaded config, got %v", c.Log)
+	}
+}
@@ -0,0 +1,101 @@
+package registry
+
+///
+/// 基于租约的分布式锁
+///
+/// 锁的key绑定在一个会话的租约上, 持有者崩溃后租约过期, 锁自动释放
+/// 每次TryLock都会到etcd确认锁还在, 租约丢失后重新创建会话
+///
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+const (
+	lock_prefix      = "/lock/"
+	lock_session_ttl = 10 // 秒
+	lock_timeout     = 3 * time.Second
+)
+
+type lock_registry struct {
+	mu      sync.Mutex
+	session *concurrency.Session
+	mutexes map[string]*concurrency.Mutex
+}
+
+// 返回可用的会话, 租约过期时重新创建, 旧会话上的锁全部失效
+func (self *lock_registry) getSession(r *Registry) (*concurrency.Session, error) {
+	if self.session != nil {
+		select {
+		case <-self.session.Done():
+			log.Warnw("lock session expired", "lease", self.session.Lease())
+			self.session = nil
+			self.mutexes = nil
+		default:
+			return self.session, nil
+		}
+	}
+	session, err := concurrency.NewSession(r.client, concurrency.WithTTL(lock_session_ttl), concurrency.WithContext(r.ctx))
+	if err != nil {
+		return nil, err
+	}
+	self.session = session
+	self.mutexes = make(map[string]*concurrency.Mutex)
+	return session, nil
+}
+
+func (self *lock_registry) tryLock(r *Registry, name string) (bool, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	session, err := self.getSession(r)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancelFunc := context.WithTimeout(r.ctx, lock_timeout)
+	defer cancelFunc()
+	if m, ok := self.mutexes[name]; ok {
+		// 确认锁还在
+		if resp, err := r.client.Get(ctx, m.Key()); err != nil {
+			return false, err
+		} else if len(resp.Kvs) > 0 && resp.Kvs[0].Lease == int64(session.Lease()) {
+			return true, nil
+		}
+		delete(self.mutexes, name)
+	}
+	m := concurrency.NewMutex(session, lock_prefix+name)
+	if err := m.TryLock(ctx); err == concurrency.ErrLocked {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	self.mutexes[name] = m
+	return true, nil
+}
+
+func (self *lock_registry) unlock(r *Registry, name string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	m, ok := self.mutexes[name]
+	if !ok {
+		return nil
+	}
+	delete(self.mutexes, name)
+	ctx, cancelFunc := context.WithTimeout(r.ctx, lock_timeout)
+	defer cancelFunc()
+	return m.Unlock(ctx)
+}
+
+// 关闭会话, 释放全部的锁
+func (self *lock_registry) stop(r *Registry) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.session != nil {
+		self.session.Close()
+		self.session = nil
+		self.mutexes = nil
+	}
+}
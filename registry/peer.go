@@ -85,6 +85,9 @@ func (self *peer_registry) getPeer(r *Registry, fullName string) *gira.Peer {
 
 func (self *peer_registry) stop(r *Registry) error {
 	log.Debug("peer registry on stop")
+	if r.isHandoff {
+		return nil
+	}
 	if err := self.unregisterSelf(r); err != nil {
 		return err
 	}
@@ -170,6 +173,7 @@ func (self *peer_registry) onKvPut(r *Registry, kv *mvccpb.KeyValue) error {
 		log.Errorw("peer registry got a invalid key", "full_name", fullName)
 		return err
 	}
+	zone, _ := gira.ParseAppZone(fullName)
 	attrValue := string(kv.Value)
 	if lastValue, ok := self.peers.Load(fullName); ok {
 		lastPeer := lastValue.(*gira.Peer)
@@ -208,6 +212,7 @@ func (self *peer_registry) onKvPut(r *Registry, kv *mvccpb.KeyValue) error {
 			Id:       serverId,
 			Name:     name,
 			FullName: fullName,
+			Zone:     zone,
 			Metadata: make(map[string]string),
 		}
 		self.peers.Store(fullName, peer)
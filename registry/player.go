@@ -20,6 +20,7 @@ import (
 	"github.com/Lyndon-Zhang/gira"
 	log "github.com/Lyndon-Zhang/gira/corelog"
 	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/registry/usercache"
 	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -32,6 +33,7 @@ type player_registry struct {
 	ctx                context.Context
 	cancelFunc         context.CancelFunc
 	watchStartRevision int64
+	userCache          *usercache.Cache // 玩家位置缓存, 没开启时为nil
 }
 
 func newConfigPlayerRegistry(r *Registry) (*player_registry, error) {
@@ -43,6 +45,9 @@ func newConfigPlayerRegistry(r *Registry) (*player_registry, error) {
 		ctx:            ctx,
 		cancelFunc:     cancelFunc,
 	}
+	if r.config.UserCache.Enabled {
+		self.userCache = usercache.NewConfigCache(ctx, r.client, self.userPrefix, r.config.UserCache)
+	}
 	return self, nil
 }
 
@@ -51,9 +56,35 @@ func (self *player_registry) stop(r *Registry) error {
 	if err := self.unregisterLocalPlayers(r); err != nil {
 		log.Info(err)
 	}
+	if self.userCache != nil {
+		self.userCache.Stop()
+	}
 	return nil
 }
 
+// 加载玩家位置缓存
+func (self *player_registry) initUserCache(r *Registry) error {
+	if self.userCache == nil {
+		return nil
+	}
+	return self.userCache.Start()
+}
+
+// 侦听玩家位置变化
+func (self *player_registry) watchUsers(r *Registry) error {
+	if self.userCache == nil {
+		return nil
+	}
+	return self.userCache.Serve()
+}
+
+func (self *player_registry) UserCacheStats(r *Registry) gira.UserCacheStats {
+	if self.userCache == nil {
+		return gira.UserCacheStats{}
+	}
+	return self.userCache.Stats()
+}
+
 func (self *player_registry) notify(r *Registry) error {
 	self.localPlayers.Range(func(k any, v any) bool {
 		player := v.(*gira.LocalPlayer)
@@ -318,6 +349,18 @@ func (self *player_registry) WhereIsUser(r *Registry, userId string) (*gira.Peer
 	if _, ok := self.localPlayers.Load(userId); ok {
 		return r.peerRegistry.SelfPeer, nil
 	}
+	if self.userCache != nil {
+		fullName, err := self.userCache.Get(self.ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		peer := r.GetPeer(fullName)
+		if peer == nil {
+			return nil, errors.ErrPeerNotFound
+		} else {
+			return peer, nil
+		}
+	}
 	client := r.client
 	// 到etcd抢占localKey
 	userKey := fmt.Sprintf("%s%s", self.userPrefix, userId)
@@ -18,6 +18,7 @@ package registry
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Lyndon-Zhang/gira"
@@ -40,6 +41,7 @@ type Registry struct {
 	appId       int32
 	appFullName string // 节点全名
 	name        string // 节点名
+	zone        string // 所在的区
 	client      *clientv3.Client
 	ctx         context.Context
 	cancelFunc  context.CancelFunc
@@ -47,13 +49,19 @@ type Registry struct {
 	localPlayerWatchHandlers []gira.LocalPlayerWatchHandler
 	peerWatchHandlers        []gira.PeerWatchHandler
 	serviceWatchHandlers     []gira.ServiceWatchHandler
+	configChangeHandlers     []gira.ConfigChangeHandler
 
 	peerRegistry    *peer_registry
 	playerRegistry  *player_registry
 	serviceRegistry *service_registry
+	configRegistry  *config_registry
+	lockRegistry    lock_registry
+	zoneRegistries  map[string]*zone_registry // 其他区的注册表
+	zoneNames       []string
 	errCtx          context.Context
 	errGroup        *errgroup.Group
 	isNotify        int32
+	isHandoff       bool // 交接给新的进程, 停止时保留节点和服务的注册信息
 	peerResolver    *peer_resolver_builder
 }
 
@@ -63,9 +71,19 @@ func (r *Registry) Stop() error {
 	r.playerRegistry.stop(r)
 	r.serviceRegistry.stop(r)
 	r.peerRegistry.stop(r)
+	r.configRegistry.stop(r)
+	r.lockRegistry.stop(r)
+	for _, zone := range r.zoneRegistries {
+		zone.stop(r)
+	}
 	return nil
 }
 
+// 零停机重启时调用, 节点和服务已经由新的进程接管, 停止时不再删除
+func (r *Registry) Handoff() {
+	r.isHandoff = true
+}
+
 func (r *Registry) notify() {
 	r.isNotify = 1
 	r.peerRegistry.notify(r)
@@ -89,13 +107,26 @@ func (r *Registry) StartAsMember() error {
 	if err := r.serviceRegistry.initServices(r); err != nil {
 		return err
 	}
+	if err := r.playerRegistry.initUserCache(r); err != nil {
+		return err
+	}
+	if err := r.configRegistry.initConfigs(r); err != nil {
+		return err
+	}
+	for _, zone := range r.zoneRegistries {
+		zone.init(r)
+	}
 	return nil
 }
 
-func (r *Registry) Watch(peerWatchHandlers []gira.PeerWatchHandler, localPlayerWatchHandlers []gira.LocalPlayerWatchHandler, serviceWatchHandlers []gira.ServiceWatchHandler) error {
+func (r *Registry) Watch(peerWatchHandlers []gira.PeerWatchHandler, localPlayerWatchHandlers []gira.LocalPlayerWatchHandler, serviceWatchHandlers []gira.ServiceWatchHandler, configChangeHandlers []gira.ConfigChangeHandler) error {
 	r.peerWatchHandlers = peerWatchHandlers
 	r.localPlayerWatchHandlers = localPlayerWatchHandlers
 	r.serviceWatchHandlers = serviceWatchHandlers
+	r.configChangeHandlers = configChangeHandlers
+	for _, handler := range configChangeHandlers {
+		r.configRegistry.watch(r, "", handler.OnConfigChange)
+	}
 	r.errGroup, r.errCtx = errgroup.WithContext(r.ctx)
 	r.errGroup.Go(func() error {
 		// return r.peerRegistry.Serve(r)
@@ -113,6 +144,21 @@ func (r *Registry) Watch(peerWatchHandlers []gira.PeerWatchHandler, localPlayerW
 		// return r.serviceRegistry.Serve(r)
 		return r.serviceRegistry.watchServices(r)
 	})
+	r.errGroup.Go(func() error {
+		return r.playerRegistry.watchUsers(r)
+	})
+	r.errGroup.Go(func() error {
+		return r.configRegistry.watchConfigs(r)
+	})
+	for _, v := range r.zoneRegistries {
+		zone := v
+		r.errGroup.Go(func() error {
+			return zone.watchPeers(r)
+		})
+		r.errGroup.Go(func() error {
+			return zone.watchServices(r)
+		})
+	}
 	r.notify()
 	return r.errGroup.Wait()
 }
@@ -137,6 +183,7 @@ func NewConfigRegistry(ctx context.Context, config *gira.EtcdConfig) (*Registry,
 		appFullName: facade.GetAppFullName(),
 		appId:       facade.GetAppId(),
 		name:        facade.GetAppType(),
+		zone:        facade.GetZone(),
 	}
 	r.ctx, r.cancelFunc = context.WithCancel(ctx)
 	// 配置endpoints
@@ -176,6 +223,24 @@ func NewConfigRegistry(ctx context.Context, config *gira.EtcdConfig) (*Registry,
 	} else {
 		r.serviceRegistry = v
 	}
+	if v, err := newConfigConfigRegistry(r); err != nil {
+		return nil, err
+	} else {
+		r.configRegistry = v
+	}
+	r.zoneRegistries = make(map[string]*zone_registry)
+	for _, zoneConfig := range r.config.Zones {
+		if zoneConfig.Zone == r.zone {
+			continue
+		}
+		if v, err := newConfigZoneRegistry(r, zoneConfig); err != nil {
+			return nil, err
+		} else {
+			r.zoneRegistries[zoneConfig.Zone] = v
+			r.zoneNames = append(r.zoneNames, zoneConfig.Zone)
+		}
+	}
+	sort.Strings(r.zoneNames)
 	return r, nil
 }
 
@@ -198,10 +263,36 @@ func (r *Registry) WhereIsUser(userId string) (*gira.Peer, error) {
 	return r.playerRegistry.WhereIsUser(r, userId)
 }
 
-// 查找节点
+// 玩家位置缓存统计
+func (r *Registry) UserCacheStats() gira.UserCacheStats {
+	return r.playerRegistry.UserCacheStats(r)
+}
+
+// 配置中心的值
+func (r *Registry) GetConfigValue(key string) (string, bool) {
+	return r.configRegistry.getValue(r, key)
+}
+
+// 侦听配置中心的变化, prefix为空时侦听全部的key
+func (r *Registry) WatchConfig(prefix string, f func(change *gira.ConfigChange)) {
+	r.configRegistry.watch(r, prefix, f)
+}
+
+// 覆盖了配置中心的值后的配置
+func (r *Registry) GetEffectiveConfig() *gira.Config {
+	return r.configRegistry.getEffectiveConfig(r)
+}
+
+// 查找节点, 本区找不到时到节点所在的区查找
 func (r *Registry) WhereIsPeer(appFullName string) (*gira.Peer, error) {
 	if p := r.peerRegistry.getPeer(r, appFullName); p != nil {
 		return p, nil
+	} else if zoneName, err := gira.ParseAppZone(appFullName); err != nil || zoneName == r.zone {
+		return nil, errors.ErrPeerNotFound
+	} else if zone, ok := r.zoneRegistries[zoneName]; !ok {
+		return nil, errors.ErrZoneNotFound
+	} else if p := zone.getPeer(r, appFullName); p != nil {
+		return p, nil
 	} else {
 		return nil, errors.ErrPeerNotFound
 	}
@@ -234,3 +325,13 @@ func (r *Registry) StartReslover() error {
 	resolver.Register(r.peerResolver)
 	return nil
 }
+
+// 尝试获取分布式锁, 锁绑定在租约上, 节点崩溃后自动释放
+func (r *Registry) TryLock(name string) (bool, error) {
+	return r.lockRegistry.tryLock(r, name)
+}
+
+// 释放分布式锁
+func (r *Registry) Unlock(name string) error {
+	return r.lockRegistry.unlock(r, name)
+}
@@ -18,7 +18,9 @@ import (
 	"github.com/Lyndon-Zhang/gira"
 	log "github.com/Lyndon-Zhang/gira/corelog"
 	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/graceful"
 	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"github.com/Lyndon-Zhang/gira/util/hashring"
 	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -278,12 +280,15 @@ type service_registry struct {
 	cancelFunc         context.CancelFunc
 	watchStartRevision int64
 	prefixIndex        *word_trie
+	ringMu             sync.Mutex
+	rings              map[string]*hashring.Ring // 一致性hash环, 按目录名索引, 服务变化时重建
 }
 
 func newConfigServiceRegistry(r *Registry) (*service_registry, error) {
 	ctx, cancelFunc := context.WithCancel(r.ctx)
 	self := &service_registry{
 		prefixIndex:       newWordTrie(),
+		rings:             make(map[string]*hashring.Ring),
 		servicePrefix:     "/service/",
 		peerServicePrefix: fmt.Sprintf("/peer/service/%s/", r.appFullName),
 		ctx:               ctx,
@@ -294,6 +299,9 @@ func newConfigServiceRegistry(r *Registry) (*service_registry, error) {
 
 func (self *service_registry) stop(r *Registry) error {
 	log.Debug("service registry on stop")
+	if r.isHandoff {
+		return nil
+	}
 	if err := self.unregisterServices(r); err != nil {
 		log.Info(err)
 	}
@@ -311,6 +319,7 @@ func (self *service_registry) notify(r *Registry) error {
 
 // on service add callback
 func (self *service_registry) onServiceAdd(r *Registry, service *gira.ServiceName) {
+	self.rebuildRing(r, service.ServiceTypeName)
 	if r.isNotify == 0 {
 		return
 	}
@@ -322,12 +331,39 @@ func (self *service_registry) onServiceAdd(r *Registry, service *gira.ServiceNam
 
 // on service delete callback
 func (self *service_registry) onServiceDelete(r *Registry, service *gira.ServiceName) {
+	self.rebuildRing(r, service.ServiceTypeName)
 	log.Debugw("service registry on service delete", "service_full_name", service.ServiceFullName, "peer", service.Peer.FullName)
 	for _, handler := range r.serviceWatchHandlers {
 		handler.OnServiceDelete(service)
 	}
 }
 
+// 重建目录对应的hash环, 只重建已经被查询过的目录
+func (self *service_registry) rebuildRing(r *Registry, catalog string) {
+	if len(catalog) <= 0 {
+		return
+	}
+	self.ringMu.Lock()
+	defer self.ringMu.Unlock()
+	if _, ok := self.rings[catalog]; !ok {
+		return
+	}
+	self.rings[catalog] = hashring.New(0, self.prefixIndex.search(catalog)...)
+	log.Debugw("service registry rebuild ring", "catalog", catalog)
+}
+
+// 查找目录对应的hash环, 不存在时创建
+func (self *service_registry) getRing(r *Registry, catalog string) *hashring.Ring {
+	self.ringMu.Lock()
+	defer self.ringMu.Unlock()
+	if ring, ok := self.rings[catalog]; ok {
+		return ring
+	}
+	ring := hashring.New(0, self.prefixIndex.search(catalog)...)
+	self.rings[catalog] = ring
+	return ring
+}
+
 func (self *service_registry) onKvAdd(r *Registry, kv *mvccpb.KeyValue) error {
 	words := strings.Split(string(kv.Key), "/")
 	var serviceTypeName string
@@ -566,6 +602,22 @@ func (self *service_registry) RegisterService(r *Registry, serviceFullName strin
 		self.selfServices.Store(serviceFullName, service)
 		self.onServiceAdd(r, service)
 		return nil, nil
+	} else if kv := txnResp.Responses[0].GetResponseRange().Kvs[0]; string(kv.Value) == value && graceful.IsInherited() {
+		// 零停机重启时, 服务由旧的进程注册, 直接接管
+		peer := r.GetPeer(value)
+		service := &gira.ServiceName{
+			IsSelf:          true,
+			ServiceFullName: serviceFullName,
+			ServiceTypeName: serviceTypeName,
+			Peer:            peer,
+			CreateRevision:  kv.CreateRevision,
+		}
+		self.services.LoadOrStore(serviceFullName, service)
+		self.prefixIndex.add(serviceFullName)
+		self.selfServices.Store(serviceFullName, service)
+		self.onServiceAdd(r, service)
+		log.Infow("service registry take over", "service_name", serviceFullName, "create_revision", kv.CreateRevision)
+		return nil, nil
 	} else {
 		log.Warnw("service registry register fail", "service_name", serviceFullName, "locked_by", string(txnResp.Responses[0].GetResponseRange().Kvs[0].Value))
 		appFullName := string(txnResp.Responses[0].GetResponseRange().Kvs[0].Value)
@@ -617,7 +669,47 @@ func (self *service_registry) WhereIsService(r *Registry, serviceName string, op
 	for _, v := range opt {
 		v.ConfigWhereOption(&opts)
 	}
-	if opts.Catalog || opts.Prefix {
+	// 指定其他区
+	if len(opts.Zone) > 0 && opts.Zone != r.zone {
+		if zone, ok := r.zoneRegistries[opts.Zone]; !ok {
+			return nil, errors.ErrZoneNotFound
+		} else {
+			return zone.whereIsService(r, serviceName, opts), nil
+		}
+	}
+	if peers, err = self.whereIsLocalService(r, serviceName, opts); err != nil || !opts.AllZone {
+		return
+	}
+	// 本区优先, 再按区名顺序查找其他区
+	multicast := len(opts.Key) <= 0 && (opts.Catalog || opts.Prefix)
+	for _, zoneName := range r.zoneNames {
+		if !multicast && len(peers) > 0 {
+			break
+		}
+		if multicast && opts.MaxCount > 0 && len(peers) >= opts.MaxCount {
+			break
+		}
+		peers = append(peers, r.zoneRegistries[zoneName].whereIsService(r, serviceName, opts)...)
+	}
+	if multicast && opts.MaxCount > 0 && len(peers) > opts.MaxCount {
+		peers = peers[:opts.MaxCount]
+	}
+	return
+}
+
+func (self *service_registry) whereIsLocalService(r *Registry, serviceName string, opts service_options.WhereOptions) (peers []*gira.Peer, err error) {
+	if len(opts.Key) > 0 {
+		// 一致性hash
+		peers = make([]*gira.Peer, 0)
+		catalog := strings.TrimSuffix(serviceName, "/")
+		if name, ok := self.getRing(r, catalog).Get(opts.Key); ok {
+			if value, ok := self.services.Load(name); ok {
+				service := value.(*gira.ServiceName)
+				peers = append(peers, service.Peer)
+			}
+		}
+		return
+	} else if opts.Catalog || opts.Prefix {
 		arr := self.prefixIndex.search(serviceName)
 		peers = make([]*gira.Peer, 0)
 		multicastCount := opts.MaxCount
@@ -0,0 +1,339 @@
+package usercache
+
+///
+/// 玩家位置缓存
+///
+/// 侦听玩家的key来更新缓存, 减少WhereIsUser对etcd的访问
+///   full: 启动时加载全部玩家, 侦听 /user/ 前缀, 未命中即表示玩家不在线
+///   lru:  只缓存查找过的玩家, 每个玩家单独侦听, 超过数量时淘汰最久未使用的并停止侦听, 未命中时查询etcd
+///
+/// 一致性:
+///   eventual: 一直使用缓存, watch断开期间可能读到旧值
+///   strong:   watch断开期间不使用缓存, 直接查询etcd, 重新连接后重建缓存, 追上最新的版本后再使用缓存
+///
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	MODE_FULL            = "full"
+	MODE_LRU             = "lru"
+	CONSISTENCY_EVENTUAL = "eventual"
+	CONSISTENCY_STRONG   = "strong"
+	DEFAULT_SIZE         = 10000
+)
+
+type cache_entry struct {
+	userId      string
+	appFullName string
+	revision    int64 // key的mod revision
+	elem        *list.Element
+	cancelFunc  context.CancelFunc // lru模式下停止侦听这个玩家
+}
+
+type Cache struct {
+	config     gira.UserCacheConfig
+	client     *clientv3.Client
+	prefix     string
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	mu         sync.Mutex
+	entries    map[string]*cache_entry
+	lru        *list.List       // 只在lru模式下使用
+	watcher    clientv3.Watcher // lru模式下侦听缓存的玩家
+	revision   int64            // 已经同步到的版本
+	watching   int32
+	hits       int64
+	misses     int64
+	evictions  int64
+}
+
+func NewConfigCache(ctx context.Context, client *clientv3.Client, prefix string, config gira.UserCacheConfig) *Cache {
+	if config.Mode != MODE_LRU {
+		config.Mode = MODE_FULL
+	}
+	if config.Consistency != CONSISTENCY_STRONG {
+		config.Consistency = CONSISTENCY_EVENTUAL
+	}
+	if config.Size <= 0 {
+		config.Size = DEFAULT_SIZE
+	}
+	c := &Cache{
+		config:  config,
+		client:  client,
+		prefix:  prefix,
+		entries: make(map[string]*cache_entry),
+	}
+	if config.Mode == MODE_LRU {
+		c.lru = list.New()
+		if client != nil {
+			c.watcher = clientv3.NewWatcher(client)
+		}
+	}
+	c.ctx, c.cancelFunc = context.WithCancel(ctx)
+	return c
+}
+
+// 加载缓存, 在Serve之前调用
+func (c *Cache) Start() error {
+	return c.sync()
+}
+
+// 侦听玩家位置变化, 断开后自动重连
+func (c *Cache) Serve() error {
+	// lru模式下在查找时侦听每个玩家
+	if c.lru != nil {
+		atomic.StoreInt32(&c.watching, 1)
+		<-c.ctx.Done()
+		atomic.StoreInt32(&c.watching, 0)
+		if c.watcher != nil {
+			c.watcher.Close()
+		}
+		log.Debugw("user cache watch exit", "prefix", c.prefix)
+		return nil
+	}
+	for {
+		if err := c.watch(); err != nil {
+			log.Warnw("user cache watch fail", "prefix", c.prefix, "error", err)
+		}
+		atomic.StoreInt32(&c.watching, 0)
+		select {
+		case <-c.ctx.Done():
+			log.Debugw("user cache watch exit", "prefix", c.prefix)
+			return nil
+		case <-time.After(time.Second):
+		}
+		// 断开期间的事件已经丢失, 重建缓存
+		if err := c.sync(); err != nil {
+			log.Warnw("user cache sync fail", "prefix", c.prefix, "error", err)
+		}
+	}
+}
+
+func (c *Cache) Stop() {
+	c.cancelFunc()
+}
+
+// 查找玩家所在的节点全名
+func (c *Cache) Get(ctx context.Context, userId string) (string, error) {
+	watching := atomic.LoadInt32(&c.watching) == 1
+	if watching || c.config.Consistency == CONSISTENCY_EVENTUAL {
+		c.mu.Lock()
+		if e, ok := c.entries[userId]; ok {
+			if c.lru != nil {
+				c.lru.MoveToFront(e.elem)
+			}
+			c.mu.Unlock()
+			atomic.AddInt64(&c.hits, 1)
+			return e.appFullName, nil
+		}
+		c.mu.Unlock()
+		// full模式下缓存了全部玩家
+		if c.config.Mode == MODE_FULL && watching {
+			atomic.AddInt64(&c.hits, 1)
+			return "", errors.ErrUserNotFound
+		}
+	}
+	atomic.AddInt64(&c.misses, 1)
+	userKey := fmt.Sprintf("%s%s", c.prefix, userId)
+	kv := clientv3.NewKV(c.client)
+	getResp, err := kv.Get(ctx, userKey)
+	if err != nil {
+		return "", err
+	}
+	if len(getResp.Kvs) <= 0 {
+		return "", errors.ErrUserNotFound
+	}
+	appFullName := string(getResp.Kvs[0].Value)
+	c.mu.Lock()
+	// 比watch同步的版本旧, 结果可能已经过期, 不放入缓存
+	if getResp.Header.Revision >= c.revision {
+		if e := c.store(userId, appFullName, getResp.Kvs[0].ModRevision); e != nil && c.watcher != nil && e.cancelFunc == nil {
+			c.watchUser(e, getResp.Header.Revision+1)
+		}
+	}
+	c.mu.Unlock()
+	return appFullName, nil
+}
+
+func (c *Cache) Stats() gira.UserCacheStats {
+	c.mu.Lock()
+	size := len(c.entries)
+	c.mu.Unlock()
+	return gira.UserCacheStats{
+		Enabled:   true,
+		Watching:  atomic.LoadInt32(&c.watching) == 1,
+		Size:      size,
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
+
+// 重建缓存
+func (c *Cache) sync() error {
+	kv := clientv3.NewKV(c.client)
+	var getResp *clientv3.GetResponse
+	var err error
+	if c.config.Mode == MODE_FULL {
+		getResp, err = kv.Get(c.ctx, c.prefix, clientv3.WithPrefix())
+	} else {
+		getResp, err = kv.Get(c.ctx, c.prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	}
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*cache_entry)
+	if c.lru != nil {
+		c.lru.Init()
+	}
+	for _, kv := range getResp.Kvs {
+		c.store(string(kv.Key[len(c.prefix):]), string(kv.Value), kv.ModRevision)
+	}
+	c.revision = getResp.Header.Revision
+	log.Debugw("user cache sync", "prefix", c.prefix, "mode", c.config.Mode, "size", len(c.entries), "revision", c.revision)
+	return nil
+}
+
+func (c *Cache) watch() error {
+	watcher := clientv3.NewWatcher(c.client)
+	defer watcher.Close()
+	c.mu.Lock()
+	watchStartRevision := c.revision + 1
+	c.mu.Unlock()
+	watchRespChan := watcher.Watch(c.ctx, c.prefix, clientv3.WithRev(watchStartRevision), clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+	log.Debugw("user cache watch started", "prefix", c.prefix, "watch_start_revision", watchStartRevision)
+	// 创建watch时的版本, 之前的事件补发完成后才能使用缓存
+	var readyRevision int64
+	for watchResp := range watchRespChan {
+		if err := watchResp.Err(); err != nil {
+			return err
+		}
+		if watchResp.Created {
+			readyRevision = watchResp.Header.Revision
+		}
+		if !c.onWatchResp(&watchResp, readyRevision) {
+			// 补发完成后etcd才会返回进度通知, 前缀下没有事件时也能追上
+			if err := watcher.RequestProgress(c.ctx); err != nil {
+				log.Debugw("user cache request progress fail", "prefix", c.prefix, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// 应用watch的响应, 同步到readyRevision后开始使用缓存, 返回是否已经同步到readyRevision
+func (c *Cache) onWatchResp(watchResp *clientv3.WatchResponse, readyRevision int64) bool {
+	c.mu.Lock()
+	// 创建通知的版本是当前的版本, 不代表已经同步到这个版本
+	if !watchResp.Created {
+		for _, event := range watchResp.Events {
+			c.apply(event.Type, event.Kv)
+		}
+		if watchResp.Header.Revision > c.revision {
+			c.revision = watchResp.Header.Revision
+		}
+	}
+	ready := readyRevision > 0 && c.revision >= readyRevision
+	c.mu.Unlock()
+	if ready {
+		atomic.StoreInt32(&c.watching, 1)
+	}
+	return ready
+}
+
+// lru模式下侦听一个玩家, 从rev开始不会漏掉事件, 侦听出错时移出缓存, 需要持有锁
+func (c *Cache) watchUser(e *cache_entry, rev int64) {
+	ctx, cancelFunc := context.WithCancel(c.ctx)
+	e.cancelFunc = cancelFunc
+	watchRespChan := c.watcher.Watch(ctx, c.prefix+e.userId, clientv3.WithRev(rev))
+	go func() {
+		for watchResp := range watchRespChan {
+			c.mu.Lock()
+			if err := watchResp.Err(); err != nil {
+				log.Debugw("user cache watch user fail", "user_id", e.userId, "error", err)
+				if c.entries[e.userId] == e {
+					c.remove(e)
+				}
+				c.mu.Unlock()
+				return
+			}
+			for _, event := range watchResp.Events {
+				c.apply(event.Type, event.Kv)
+			}
+			c.mu.Unlock()
+		}
+	}()
+}
+
+// 应用watch事件, 需要持有锁
+func (c *Cache) apply(typ mvccpb.Event_EventType, kv *mvccpb.KeyValue) {
+	if len(kv.Key) <= len(c.prefix) {
+		return
+	}
+	userId := string(kv.Key[len(c.prefix):])
+	switch typ {
+	case mvccpb.PUT:
+		// lru模式下只更新已经缓存的玩家
+		if _, ok := c.entries[userId]; ok || c.lru == nil {
+			c.store(userId, string(kv.Value), kv.ModRevision)
+		}
+	case mvccpb.DELETE:
+		if e, ok := c.entries[userId]; ok && e.revision < kv.ModRevision {
+			c.remove(e)
+		}
+	}
+}
+
+// 放入缓存, 返回缓存的项, 版本比缓存中的旧时返回nil, 需要持有锁
+func (c *Cache) store(userId string, appFullName string, revision int64) *cache_entry {
+	if e, ok := c.entries[userId]; ok {
+		if e.revision > revision {
+			return nil
+		}
+		e.appFullName = appFullName
+		e.revision = revision
+		if c.lru != nil {
+			c.lru.MoveToFront(e.elem)
+		}
+		return e
+	}
+	e := &cache_entry{
+		userId:      userId,
+		appFullName: appFullName,
+		revision:    revision,
+	}
+	c.entries[userId] = e
+	if c.lru != nil {
+		e.elem = c.lru.PushFront(e)
+		for c.lru.Len() > c.config.Size {
+			c.remove(c.lru.Back().Value.(*cache_entry))
+			atomic.AddInt64(&c.evictions, 1)
+		}
+	}
+	return e
+}
+
+// 移出缓存, 需要持有锁
+func (c *Cache) remove(e *cache_entry) {
+	delete(c.entries, e.userId)
+	if c.lru != nil && e.elem != nil {
+		c.lru.Remove(e.elem)
+	}
+	if e.cancelFunc != nil {
+		e.cancelFunc()
+	}
+}
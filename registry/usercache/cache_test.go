@@ -0,0 +1,111 @@
+package usercache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestLruEvict(t *testing.T) {
+	c := NewConfigCache(context.Background(), nil, "/user/", gira.UserCacheConfig{
+		Enabled: true,
+		Mode:    MODE_LRU,
+		Size:    2,
+	})
+	c.store("u1", "hall_1", 1)
+	c.store("u2", "hall_1", 2)
+	c.store("u3", "hall_2", 3)
+	if _, ok := c.entries["u1"]; ok {
+		t.Fatalf("u1 should be evicted")
+	}
+	if stats := c.Stats(); stats.Size != 2 || stats.Evictions != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	// lru模式下不缓存未查找过的玩家
+	c.apply(mvccpb.PUT, &mvccpb.KeyValue{Key: []byte("/user/u4"), Value: []byte("hall_3"), ModRevision: 4})
+	if _, ok := c.entries["u4"]; ok {
+		t.Fatalf("u4 should not be cached")
+	}
+	c.apply(mvccpb.PUT, &mvccpb.KeyValue{Key: []byte("/user/u2"), Value: []byte("hall_3"), ModRevision: 5})
+	if e := c.entries["u2"]; e.appFullName != "hall_3" {
+		t.Fatalf("u2 should be updated, got %s", e.appFullName)
+	}
+}
+
+func TestApplyRevision(t *testing.T) {
+	c := NewConfigCache(context.Background(), nil, "/user/", gira.UserCacheConfig{
+		Enabled: true,
+	})
+	c.apply(mvccpb.PUT, &mvccpb.KeyValue{Key: []byte("/user/u1"), Value: []byte("hall_1"), ModRevision: 10})
+	// 旧版本的删除事件被忽略
+	c.apply(mvccpb.DELETE, &mvccpb.KeyValue{Key: []byte("/user/u1"), ModRevision: 9})
+	if _, ok := c.entries["u1"]; !ok {
+		t.Fatalf("u1 should not be deleted")
+	}
+	// 旧版本的值不覆盖新值
+	c.store("u1", "hall_2", 8)
+	if e := c.entries["u1"]; e.appFullName != "hall_1" {
+		t.Fatalf("u1 should not be overwritten, got %s", e.appFullName)
+	}
+	c.apply(mvccpb.DELETE, &mvccpb.KeyValue{Key: []byte("/user/u1"), ModRevision: 11})
+	if _, ok := c.entries["u1"]; ok {
+		t.Fatalf("u1 should be deleted")
+	}
+}
+
+// 淘汰和删除时停止侦听玩家
+func TestLruStopWatch(t *testing.T) {
+	c := NewConfigCache(context.Background(), nil, "/user/", gira.UserCacheConfig{
+		Enabled: true,
+		Mode:    MODE_LRU,
+		Size:    1,
+	})
+	watch := func(e *cache_entry) context.Context {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		e.cancelFunc = cancelFunc
+		return ctx
+	}
+	u1 := watch(c.store("u1", "hall_1", 1))
+	u2 := watch(c.store("u2", "hall_1", 2))
+	if u1.Err() == nil {
+		t.Fatal("evicted user still watched")
+	}
+	c.apply(mvccpb.DELETE, &mvccpb.KeyValue{Key: []byte("/user/u2"), ModRevision: 3})
+	if u2.Err() == nil {
+		t.Fatal("deleted user still watched")
+	}
+}
+
+// 补发到创建watch时的版本之前不使用缓存
+func TestWatchReady(t *testing.T) {
+	c := NewConfigCache(context.Background(), nil, "/user/", gira.UserCacheConfig{
+		Enabled:     true,
+		Consistency: CONSISTENCY_STRONG,
+	})
+	c.revision = 10
+	created := &clientv3.WatchResponse{Header: etcdserverpb.ResponseHeader{Revision: 15}, Created: true}
+	if c.onWatchResp(created, 15) || c.Stats().Watching {
+		t.Fatal("watching before replay")
+	}
+	if c.revision != 10 {
+		t.Fatalf("created notify should not move revision, got %d", c.revision)
+	}
+	replay := &clientv3.WatchResponse{
+		Header: etcdserverpb.ResponseHeader{Revision: 12},
+		Events: []*clientv3.Event{{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("/user/u1"), Value: []byte("hall_1"), ModRevision: 12}}},
+	}
+	if c.onWatchResp(replay, 15) || c.Stats().Watching {
+		t.Fatal("watching before replay finished")
+	}
+	progress := &clientv3.WatchResponse{Header: etcdserverpb.ResponseHeader{Revision: 15}}
+	if !c.onWatchResp(progress, 15) || !c.Stats().Watching {
+		t.Fatal("not watching after replay finished")
+	}
+	if e, ok := c.entries["u1"]; !ok || e.appFullName != "hall_1" {
+		t.Fatal("replayed event not applied")
+	}
+}
@@ -0,0 +1,380 @@
+package registry
+
+///
+/// 其他区的注册表
+///
+/// 只读, 侦听其他区的节点和服务, 不会注册自己, 也不会通知watch handler
+/// 其他区不可用时不影响本区, 侦听出错或者版本被压缩后重新拉取全量数据
+/// 注册表结构和本区一样:
+///   /peer/attribute/<<AppFullName>>/<<AttrName>> => <<AttrValue>>
+///   /service/<<ServiceName>> => <<AppFullName>>
+///
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"github.com/Lyndon-Zhang/gira/util/hashring"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	zone_request_timeout = 3 * time.Second
+	zone_retry_interval  = 3 * time.Second
+)
+
+type zone_registry struct {
+	zone                      string
+	client                    *clientv3.Client
+	peerPrefix                string // /peer/attribute/
+	servicePrefix             string // /service/
+	mu                        sync.Mutex
+	peers                     map[string]*gira.Peer // 节点, 修改时替换成新的对象
+	services                  map[string]string     // 服务 => 节点全名
+	prefixIndex               *word_trie
+	rings                     map[string]*hashring.Ring // 一致性hash环, 按目录名索引, 服务变化时删除, 查询时重建
+	ctx                       context.Context
+	cancelFunc                context.CancelFunc
+	peerWatchStartRevision    int64
+	serviceWatchStartRevision int64
+}
+
+func newConfigZoneRegistry(r *Registry, config gira.EtcdZoneConfig) (*zone_registry, error) {
+	endpoints := make([]string, 0)
+	for _, v := range config.Endpoints {
+		endpoints = append(endpoints, fmt.Sprintf("http://%s:%d", v.Host, v.Port))
+	}
+	// 其他区不可用时不阻塞本区启动
+	c := clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: time.Duration(config.DialTimeout) * time.Second,
+		Username:    config.Username,
+		Password:    config.Password,
+		Context:     r.ctx,
+	}
+	client, err := clientv3.New(c)
+	if err != nil {
+		log.Errorw("connect to zone etcd fail", "zone", config.Zone, "error", err)
+		return nil, err
+	}
+	ctx, cancelFunc := context.WithCancel(r.ctx)
+	self := &zone_registry{
+		zone:          config.Zone,
+		client:        client,
+		peerPrefix:    "/peer/attribute/",
+		servicePrefix: "/service/",
+		peers:         make(map[string]*gira.Peer),
+		services:      make(map[string]string),
+		prefixIndex:   newWordTrie(),
+		rings:         make(map[string]*hashring.Ring),
+		ctx:           ctx,
+		cancelFunc:    cancelFunc,
+	}
+	log.Debugw("connect zone registry success", "zone", config.Zone, "endpoints", endpoints)
+	return self, nil
+}
+
+func (self *zone_registry) stop(r *Registry) error {
+	log.Debugw("zone registry on stop", "zone", self.zone)
+	self.cancelFunc()
+	return self.client.Close()
+}
+
+// 拉取其他区的节点和服务, 失败时只打印警告, 由watch重试, 其他区不可用时不影响本区启动
+func (self *zone_registry) init(r *Registry) {
+	if rev, err := self.resyncPeers(r); err != nil {
+		log.Warnw("zone registry init peers fail", "zone", self.zone, "error", err)
+		return
+	} else {
+		self.peerWatchStartRevision = rev
+	}
+	if rev, err := self.resyncServices(r); err != nil {
+		log.Warnw("zone registry init services fail", "zone", self.zone, "error", err)
+	} else {
+		self.serviceWatchStartRevision = rev
+	}
+}
+
+func (self *zone_registry) list(prefix string) (*clientv3.GetResponse, error) {
+	ctx, cancelFunc := context.WithTimeout(self.ctx, zone_request_timeout)
+	defer cancelFunc()
+	return self.client.Get(ctx, prefix, clientv3.WithPrefix())
+}
+
+// 重新拉取全部的节点, 返回开始侦听的版本
+func (self *zone_registry) resyncPeers(r *Registry) (int64, error) {
+	getResp, err := self.list(self.peerPrefix)
+	if err != nil {
+		return 0, err
+	}
+	self.replacePeers(r, getResp.Kvs)
+	return getResp.Header.Revision + 1, nil
+}
+
+// 重新拉取全部的服务, 返回开始侦听的版本
+func (self *zone_registry) resyncServices(r *Registry) (int64, error) {
+	getResp, err := self.list(self.servicePrefix)
+	if err != nil {
+		return 0, err
+	}
+	self.replaceServices(r, getResp.Kvs)
+	return getResp.Header.Revision + 1, nil
+}
+
+// 用全量数据替换本地的节点, 不在kvs中的节点被删除
+func (self *zone_registry) replacePeers(r *Registry, kvs []*mvccpb.KeyValue) {
+	peers := make(map[string]*gira.Peer)
+	for _, kv := range kvs {
+		self.putPeer(peers, kv)
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for fullName := range self.peers {
+		if _, ok := peers[fullName]; !ok {
+			log.Debugw("zone registry remove peer", "zone", self.zone, "full_name", fullName)
+		}
+	}
+	self.peers = peers
+}
+
+// 用全量数据替换本地的服务, 不在kvs中的服务被删除
+func (self *zone_registry) replaceServices(r *Registry, kvs []*mvccpb.KeyValue) {
+	services := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		services[strings.TrimPrefix(string(kv.Key), self.servicePrefix)] = string(kv.Value)
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for serviceFullName := range self.services {
+		if _, ok := services[serviceFullName]; !ok {
+			delete(self.services, serviceFullName)
+			self.prefixIndex.delete(serviceFullName)
+		}
+	}
+	for serviceFullName, fullName := range services {
+		if _, ok := self.services[serviceFullName]; !ok {
+			self.prefixIndex.add(serviceFullName)
+		}
+		self.services[serviceFullName] = fullName
+	}
+	self.rings = make(map[string]*hashring.Ring)
+}
+
+func (self *zone_registry) watchPeers(r *Registry) error {
+	return self.watch(r, self.peerPrefix, self.peerWatchStartRevision, self.resyncPeers, self.onPeerKvPut, self.onPeerKvDelete)
+}
+
+func (self *zone_registry) watchServices(r *Registry) error {
+	return self.watch(r, self.servicePrefix, self.serviceWatchStartRevision, self.resyncServices, self.onServiceKvPut, self.onServiceKvDelete)
+}
+
+// 侦听prefix, 出错, 被取消或者版本已经被压缩时, 重新拉取全量数据, 从新的版本继续侦听
+// rev为0时先拉取全量数据
+func (self *zone_registry) watch(r *Registry, prefix string, rev int64, resync func(r *Registry) (int64, error), onPut func(r *Registry, kv *mvccpb.KeyValue), onDelete func(r *Registry, kv *mvccpb.KeyValue)) error {
+	for {
+		if rev == 0 {
+			var err error
+			if rev, err = resync(r); err != nil {
+				log.Warnw("zone registry resync fail", "zone", self.zone, "prefix", prefix, "error", err)
+				select {
+				case <-self.ctx.Done():
+					return nil
+				case <-time.After(zone_retry_interval):
+				}
+				continue
+			}
+		}
+		ctx, cancelFunc := context.WithCancel(self.ctx)
+		watchRespChan := self.client.Watch(ctx, prefix, clientv3.WithRev(rev), clientv3.WithPrefix())
+		log.Debugw("zone registry watch started", "zone", self.zone, "prefix", prefix, "watch_start_revision", rev)
+		for watchResp := range watchRespChan {
+			if err := watchResp.Err(); err != nil {
+				log.Warnw("zone registry watch fail", "zone", self.zone, "prefix", prefix, "compact_revision", watchResp.CompactRevision, "error", err)
+				break
+			}
+			for _, event := range watchResp.Events {
+				switch event.Type {
+				case mvccpb.PUT:
+					onPut(r, event.Kv)
+				case mvccpb.DELETE:
+					onDelete(r, event.Kv)
+				}
+			}
+		}
+		cancelFunc()
+		if self.ctx.Err() != nil {
+			log.Debugw("zone registry watch exit", "zone", self.zone, "prefix", prefix)
+			return nil
+		}
+		// 中间的事件可能已经丢失, 重新拉取全量数据
+		rev = 0
+	}
+}
+
+func (self *zone_registry) onPeerKvPut(r *Registry, kv *mvccpb.KeyValue) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.putPeer(self.peers, kv)
+}
+
+// 修改peers中的节点, peers是self.peers时需要持有锁
+func (self *zone_registry) putPeer(peers map[string]*gira.Peer, kv *mvccpb.KeyValue) {
+	pats := strings.Split(string(kv.Key), "/")
+	if len(pats) != 5 {
+		log.Warnw("zone registry got a invalid peer key", "zone", self.zone, "key", string(kv.Key))
+		return
+	}
+	fullName := pats[3]
+	attrName := pats[4]
+	name, serverId, err := gira.ParseAppFullName(fullName)
+	if err != nil {
+		log.Warnw("zone registry got a invalid peer key", "zone", self.zone, "full_name", fullName)
+		return
+	}
+	peer := &gira.Peer{
+		Id:       serverId,
+		Name:     name,
+		FullName: fullName,
+		Zone:     self.zone,
+		Metadata: make(map[string]string),
+	}
+	if lastPeer, ok := peers[fullName]; ok {
+		*peer = *lastPeer
+		peer.Metadata = make(map[string]string, len(lastPeer.Metadata))
+		for k, v := range lastPeer.Metadata {
+			peer.Metadata[k] = v
+		}
+	}
+	if attrName == GRPC_KEY {
+		peer.Address = string(kv.Value)
+		peer.Url = formatPeerUrl(fullName)
+	} else {
+		peer.Metadata[attrName] = string(kv.Value)
+	}
+	peers[fullName] = peer
+}
+
+func (self *zone_registry) onPeerKvDelete(r *Registry, kv *mvccpb.KeyValue) {
+	pats := strings.Split(string(kv.Key), "/")
+	if len(pats) != 5 {
+		return
+	}
+	fullName := pats[3]
+	attrName := pats[4]
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if attrName == GRPC_KEY {
+		log.Debugw("zone registry remove peer", "zone", self.zone, "full_name", fullName)
+		delete(self.peers, fullName)
+	} else if lastPeer, ok := self.peers[fullName]; ok {
+		peer := *lastPeer
+		peer.Metadata = make(map[string]string, len(lastPeer.Metadata))
+		for k, v := range lastPeer.Metadata {
+			if k != attrName {
+				peer.Metadata[k] = v
+			}
+		}
+		self.peers[fullName] = &peer
+	}
+}
+
+func (self *zone_registry) onServiceKvPut(r *Registry, kv *mvccpb.KeyValue) {
+	serviceFullName := strings.TrimPrefix(string(kv.Key), self.servicePrefix)
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if _, ok := self.services[serviceFullName]; !ok {
+		self.prefixIndex.add(serviceFullName)
+		self.deleteRings(serviceFullName)
+	}
+	self.services[serviceFullName] = string(kv.Value)
+}
+
+func (self *zone_registry) onServiceKvDelete(r *Registry, kv *mvccpb.KeyValue) {
+	serviceFullName := strings.TrimPrefix(string(kv.Key), self.servicePrefix)
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if _, ok := self.services[serviceFullName]; ok {
+		delete(self.services, serviceFullName)
+		self.prefixIndex.delete(serviceFullName)
+		self.deleteRings(serviceFullName)
+	}
+}
+
+// 删除包含服务的目录的hash环, 需要持有锁
+func (self *zone_registry) deleteRings(serviceFullName string) {
+	words := strings.Split(serviceFullName, "/")
+	for i := 1; i <= len(words); i++ {
+		delete(self.rings, strings.Join(words[:i], "/"))
+	}
+}
+
+// 查找目录对应的hash环, 不存在时创建, 需要持有锁
+func (self *zone_registry) getRing(catalog string) *hashring.Ring {
+	if ring, ok := self.rings[catalog]; ok {
+		return ring
+	}
+	ring := hashring.New(0, self.prefixIndex.search(catalog)...)
+	self.rings[catalog] = ring
+	return ring
+}
+
+// 查找节点, 只返回在线的节点
+func (self *zone_registry) getPeer(r *Registry, fullName string) *gira.Peer {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if peer, ok := self.peers[fullName]; ok && len(peer.Address) > 0 {
+		return peer
+	}
+	return nil
+}
+
+// 查找服务所在的节点, 需要持有锁
+func (self *zone_registry) getServicePeer(serviceFullName string) *gira.Peer {
+	if fullName, ok := self.services[serviceFullName]; !ok {
+		return nil
+	} else if peer, ok := self.peers[fullName]; ok && len(peer.Address) > 0 {
+		return peer
+	}
+	return nil
+}
+
+func (self *zone_registry) whereIsService(r *Registry, serviceName string, opts service_options.WhereOptions) []*gira.Peer {
+	peers := make([]*gira.Peer, 0)
+	if len(opts.Key) > 0 {
+		catalog := strings.TrimSuffix(serviceName, "/")
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		if name, ok := self.getRing(catalog).Get(opts.Key); ok {
+			if peer := self.getServicePeer(name); peer != nil {
+				peers = append(peers, peer)
+			}
+		}
+	} else if opts.Catalog || opts.Prefix {
+		arr := self.prefixIndex.search(serviceName)
+		sort.Strings(arr)
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		for _, name := range arr {
+			if peer := self.getServicePeer(name); peer != nil {
+				peers = append(peers, peer)
+				if opts.MaxCount > 0 && len(peers) >= opts.MaxCount {
+					break
+				}
+			}
+		}
+	} else {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		if peer := self.getServicePeer(serviceName); peer != nil {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
@@ -0,0 +1,137 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"github.com/Lyndon-Zhang/gira/util/hashring"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func newTestZoneRegistry(client *clientv3.Client) *zone_registry {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	return &zone_registry{
+		zone:          "qq",
+		client:        client,
+		peerPrefix:    "/peer/attribute/",
+		servicePrefix: "/service/",
+		peers:         make(map[string]*gira.Peer),
+		services:      make(map[string]string),
+		prefixIndex:   newWordTrie(),
+		rings:         make(map[string]*hashring.Ring),
+		ctx:           ctx,
+		cancelFunc:    cancelFunc,
+	}
+}
+
+func kv(key string, value string) *mvccpb.KeyValue {
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}
+}
+
+func TestZoneResync(t *testing.T) {
+	zone := newTestZoneRegistry(nil)
+	zone.replacePeers(nil, []*mvccpb.KeyValue{
+		kv("/peer/attribute/hall_qq_dev_1/grpc", "127.0.0.1:1001"),
+		kv("/peer/attribute/hall_qq_dev_2/grpc", "127.0.0.1:1002"),
+	})
+	zone.replaceServices(nil, []*mvccpb.KeyValue{
+		kv("/service/hall/1", "hall_qq_dev_1"),
+		kv("/service/hall/2", "hall_qq_dev_2"),
+	})
+	if peers := zone.whereIsService(nil, "hall/", service_options.WhereOptions{Catalog: true}); len(peers) != 2 {
+		t.Fatalf("expected 2 peers, got %v", peers)
+	}
+	// 侦听中断期间hall_qq_dev_2下线了
+	zone.replacePeers(nil, []*mvccpb.KeyValue{
+		kv("/peer/attribute/hall_qq_dev_1/grpc", "127.0.0.1:1001"),
+	})
+	zone.replaceServices(nil, []*mvccpb.KeyValue{
+		kv("/service/hall/1", "hall_qq_dev_1"),
+	})
+	if peer := zone.getPeer(nil, "hall_qq_dev_2"); peer != nil {
+		t.Fatalf("expected peer removed, got %v", peer)
+	}
+	peers := zone.whereIsService(nil, "hall/", service_options.WhereOptions{Catalog: true})
+	if len(peers) != 1 || peers[0].FullName != "hall_qq_dev_1" || peers[0].Zone != "qq" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+	if len(zone.prefixIndex.search("hall")) != 1 {
+		t.Fatal("expected prefix index updated")
+	}
+}
+
+// hash环按目录缓存, 服务变化时重建
+func TestZoneRing(t *testing.T) {
+	zone := newTestZoneRegistry(nil)
+	zone.replacePeers(nil, []*mvccpb.KeyValue{
+		kv("/peer/attribute/hall_qq_dev_1/grpc", "127.0.0.1:1001"),
+		kv("/peer/attribute/hall_qq_dev_2/grpc", "127.0.0.1:1002"),
+	})
+	zone.replaceServices(nil, []*mvccpb.KeyValue{
+		kv("/service/hall/1", "hall_qq_dev_1"),
+	})
+	where := func() []*gira.Peer {
+		return zone.whereIsService(nil, "hall/", service_options.WhereOptions{Key: "user_1"})
+	}
+	if peers := where(); len(peers) != 1 || peers[0].FullName != "hall_qq_dev_1" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+	ring, ok := zone.rings["hall"]
+	if !ok {
+		t.Fatal("expected ring cached")
+	}
+	where()
+	if zone.rings["hall"] != ring {
+		t.Fatal("expected cached ring reused")
+	}
+	zone.onServiceKvPut(nil, kv("/service/hall/2", "hall_qq_dev_2"))
+	if _, ok := zone.rings["hall"]; ok {
+		t.Fatal("expected ring removed after put")
+	}
+	where()
+	if v, ok := zone.rings["hall"]; !ok || v == ring {
+		t.Fatal("expected ring rebuilt")
+	}
+	zone.onServiceKvDelete(nil, kv("/service/hall/1", ""))
+	if _, ok := zone.rings["hall"]; ok {
+		t.Fatal("expected ring removed after delete")
+	}
+	if peers := where(); len(peers) != 1 || peers[0].FullName != "hall_qq_dev_2" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+	zone.replaceServices(nil, []*mvccpb.KeyValue{
+		kv("/service/hall/1", "hall_qq_dev_1"),
+	})
+	if len(zone.rings) != 0 {
+		t.Fatal("expected rings removed after resync")
+	}
+	if peers := where(); len(peers) != 1 || peers[0].FullName != "hall_qq_dev_1" {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+}
+
+// 其他区不可用时init在超时后返回, 不阻塞本区
+func TestZoneInitUnavailable(t *testing.T) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{"http://127.0.0.1:1"},
+		DialTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	zone := newTestZoneRegistry(client)
+	defer zone.cancelFunc()
+	start := time.Now()
+	zone.init(nil)
+	if elapsed := time.Since(start); elapsed > 2*zone_request_timeout {
+		t.Fatalf("init blocked %v", elapsed)
+	}
+	if zone.peerWatchStartRevision != 0 || zone.serviceWatchStartRevision != 0 {
+		t.Fatal("expected watch to resync")
+	}
+}
@@ -3,6 +3,7 @@ package registryclient
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Lyndon-Zhang/gira"
@@ -19,6 +20,7 @@ type RegistryClient struct {
 	config      gira.EtcdClientConfig
 	appId       int32
 	appFullName string // 节点全名
+	zone        string // 所在的区
 	client      *clientv3.Client
 	ctx         context.Context
 	cancelFunc  context.CancelFunc
@@ -26,9 +28,25 @@ type RegistryClient struct {
 	peerRegistry    *peer_registry
 	playerRegistry  *player_registry
 	serviceRegistry *service_registry
+	zoneClients     map[string]*RegistryClient // 其他区的注册表
+	zoneNames       []string
 }
 
 func (r *RegistryClient) StartAsClient() error {
+	if err := r.playerRegistry.startUserCache(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 停止玩家位置缓存和服务的侦听
+func (r *RegistryClient) Stop() error {
+	r.playerRegistry.stop(r)
+	r.serviceRegistry.stop(r)
+	for _, zone := range r.zoneClients {
+		zone.Stop()
+	}
+	r.cancelFunc()
 	return nil
 }
 
@@ -38,6 +56,7 @@ func NewConfigRegistryClient(ctx context.Context, config *gira.EtcdClientConfig,
 		appFullName: appFullName,
 		appId:       appId,
 	}
+	r.zone, _ = gira.ParseAppZone(appFullName)
 	r.ctx, r.cancelFunc = context.WithCancel(ctx)
 	// 配置endpoints
 	endpoints := make([]string, 0)
@@ -75,6 +94,26 @@ func NewConfigRegistryClient(ctx context.Context, config *gira.EtcdClientConfig,
 	} else {
 		r.serviceRegistry = v
 	}
+	r.zoneClients = make(map[string]*RegistryClient)
+	for _, zoneConfig := range r.config.Zones {
+		if zoneConfig.Zone == r.zone {
+			continue
+		}
+		zoneClientConfig := &gira.EtcdClientConfig{
+			Endpoints:   zoneConfig.Endpoints,
+			Username:    zoneConfig.Username,
+			Password:    zoneConfig.Password,
+			DialTimeout: zoneConfig.DialTimeout,
+		}
+		if v, err := NewConfigRegistryClient(r.ctx, zoneClientConfig, appId, appFullName); err != nil {
+			return nil, err
+		} else {
+			v.zone = zoneConfig.Zone
+			r.zoneClients[zoneConfig.Zone] = v
+			r.zoneNames = append(r.zoneNames, zoneConfig.Zone)
+		}
+	}
+	sort.Strings(r.zoneNames)
 	return r, nil
 }
 
@@ -83,6 +122,11 @@ func (r *RegistryClient) WhereIsUser(userId string) (*gira.Peer, error) {
 	return r.playerRegistry.WhereIsUser(r, userId)
 }
 
+// 玩家位置缓存统计
+func (r *RegistryClient) UserCacheStats() gira.UserCacheStats {
+	return r.playerRegistry.UserCacheStats(r)
+}
+
 // 查找服务
 func (r *RegistryClient) WhereIsService(serviceName string, opt ...service_options.WhereOption) ([]*gira.Peer, error) {
 	return r.serviceRegistry.WhereIsService(r, serviceName, opt...)
@@ -111,8 +155,13 @@ func (r *RegistryClient) ListServiceKvs() (services map[string][]string, err err
 	return
 }
 
-// 查找节点
+// 查找节点, 节点在其他区时到对应区查找
 func (r *RegistryClient) WhereIsPeer(appFullName string) (*gira.Peer, error) {
+	if zoneName, err := gira.ParseAppZone(appFullName); err == nil && zoneName != r.zone {
+		if zone, ok := r.zoneClients[zoneName]; ok {
+			return zone.WhereIsPeer(appFullName)
+		}
+	}
 	if p := r.peerRegistry.GetPeer(r, appFullName); p != nil {
 		return p, nil
 	} else {
@@ -0,0 +1,188 @@
+package registryclient
+
+///
+/// 配置中心
+///
+/// 注册表结构:
+///   /config/<<Key>> => <<Value>>
+///   /config_history/<<Key>>/<<Time>> => {"value": <<Value>>, "time": <<Time>>, "deleted": false}
+///
+/// 每次修改都会记录历史, 每个key最多保留CONFIG_HISTORY_MAX条
+///
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	CONFIG_PREFIX         = "/config/"
+	CONFIG_HISTORY_PREFIX = "/config_history/"
+	CONFIG_HISTORY_MAX    = 50
+)
+
+type ConfigHistory struct {
+	Value   string `json:"value"`
+	Time    int64  `json:"time"`
+	Deleted bool   `json:"deleted"`
+}
+
+// 修改配置
+func (r *RegistryClient) SetConfig(key string, value string) error {
+	return r.putConfig(key, value, false)
+}
+
+// 删除配置
+func (r *RegistryClient) DeleteConfig(key string) error {
+	return r.putConfig(key, "", true)
+}
+
+// 查找配置
+func (r *RegistryClient) GetConfig(key string) (string, bool, error) {
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(r.ctx, CONFIG_PREFIX+key)
+	if err != nil {
+		return "", false, err
+	}
+	if len(getResp.Kvs) <= 0 {
+		return "", false, nil
+	}
+	return string(getResp.Kvs[0].Value), true, nil
+}
+
+// 列出前缀匹配的配置
+func (r *RegistryClient) ListConfig(prefix string) (map[string]string, error) {
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(r.ctx, CONFIG_PREFIX+prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(getResp.Kvs))
+	for _, v := range getResp.Kvs {
+		result[strings.TrimPrefix(string(v.Key), CONFIG_PREFIX)] = string(v.Value)
+	}
+	return result, nil
+}
+
+// 修改历史, 按时间从新到旧排序
+func (r *RegistryClient) ConfigHistory(key string) ([]*ConfigHistory, error) {
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(r.ctx, fmt.Sprintf("%s%s/", CONFIG_HISTORY_PREFIX, key), clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ConfigHistory, 0, len(getResp.Kvs))
+	for _, v := range getResp.Kvs {
+		history := &ConfigHistory{}
+		if err := json.Unmarshal(v.Value, history); err != nil {
+			log.Warnw("invalid config history", "key", string(v.Key), "error", err)
+			continue
+		}
+		result = append(result, history)
+	}
+	return result, nil
+}
+
+// 回滚到前steps次修改时的值
+func (r *RegistryClient) RollbackConfig(key string, steps int) (*ConfigHistory, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(r.ctx, CONFIG_PREFIX+key)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := r.ConfigHistory(key)
+	if err != nil {
+		return nil, err
+	}
+	if steps >= len(histories) {
+		return nil, errors.ErrConfigHistoryNotFound
+	}
+	// 第一条必须是当前的值, 否则历史已经不可信
+	if !isCurrentConfig(histories[0], getResp.Kvs) {
+		return nil, errors.ErrConfigHistoryMismatch
+	}
+	// 当前的值在回滚前被修改过时放弃
+	var modRevision int64
+	if len(getResp.Kvs) > 0 {
+		modRevision = getResp.Kvs[0].ModRevision
+	}
+	history := histories[steps]
+	if err := r.putConfig(key, history.Value, history.Deleted, clientv3.Compare(clientv3.ModRevision(CONFIG_PREFIX+key), "=", modRevision)); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// 历史记录是否和当前的值一致
+func isCurrentConfig(history *ConfigHistory, kvs []*mvccpb.KeyValue) bool {
+	if len(kvs) <= 0 {
+		return history.Deleted
+	}
+	return !history.Deleted && history.Value == string(kvs[0].Value)
+}
+
+// 修改配置并记录历史, cmps不满足时返回ErrConfigHistoryMismatch
+func (r *RegistryClient) putConfig(key string, value string, deleted bool, cmps ...clientv3.Cmp) error {
+	if len(key) <= 0 {
+		return errors.ErrInvalidArgs
+	}
+	now := time.Now()
+	history := &ConfigHistory{
+		Value:   value,
+		Time:    now.Unix(),
+		Deleted: deleted,
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	configKey := CONFIG_PREFIX + key
+	historyKey := fmt.Sprintf("%s%s/%019d", CONFIG_HISTORY_PREFIX, key, now.UnixNano())
+	var op clientv3.Op
+	if deleted {
+		op = clientv3.OpDelete(configKey)
+	} else {
+		op = clientv3.OpPut(configKey, value)
+	}
+	kv := clientv3.NewKV(r.client)
+	if txnResp, err := kv.Txn(r.ctx).If(cmps...).Then(op, clientv3.OpPut(historyKey, string(data))).Commit(); err != nil {
+		return err
+	} else if !txnResp.Succeeded {
+		return errors.ErrConfigHistoryMismatch
+	}
+	log.Infow("config set", "key", key, "value", value, "deleted", deleted)
+	return r.trimConfigHistory(key)
+}
+
+// 删除多余的历史
+func (r *RegistryClient) trimConfigHistory(key string) error {
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(r.ctx, fmt.Sprintf("%s%s/", CONFIG_HISTORY_PREFIX, key), clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	if len(getResp.Kvs) <= CONFIG_HISTORY_MAX {
+		return nil
+	}
+	keys := make([]string, 0, len(getResp.Kvs))
+	for _, v := range getResp.Kvs {
+		keys = append(keys, string(v.Key))
+	}
+	sort.Strings(keys)
+	for _, v := range keys[:len(keys)-CONFIG_HISTORY_MAX] {
+		if _, err := kv.Delete(r.ctx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
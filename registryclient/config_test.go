@@ -0,0 +1,28 @@
+package registryclient
+
+import (
+	"testing"
+
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+func TestIsCurrentConfig(t *testing.T) {
+	current := []*mvccpb.KeyValue{{Key: []byte(CONFIG_PREFIX + "log.level"), Value: []byte("debug")}}
+	tests := []struct {
+		name     string
+		history  *ConfigHistory
+		kvs      []*mvccpb.KeyValue
+		expected bool
+	}{
+		{"same value", &ConfigHistory{Value: "debug"}, current, true},
+		{"value changed without history", &ConfigHistory{Value: "info"}, current, false},
+		{"deleted history but value exists", &ConfigHistory{Value: "debug", Deleted: true}, current, false},
+		{"deleted", &ConfigHistory{Deleted: true}, nil, true},
+		{"value deleted without history", &ConfigHistory{Value: "debug"}, nil, false},
+	}
+	for _, tt := range tests {
+		if v := isCurrentConfig(tt.history, tt.kvs); v != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, v)
+		}
+	}
+}
@@ -37,10 +37,12 @@ func (self *peer_registry) GetPeer(r *RegistryClient, appFullName string) *gira.
 	if err != nil {
 		return nil
 	}
+	zone, _ := gira.ParseAppZone(appFullName)
 	peer := &gira.Peer{
 		Id:       appId,
 		Name:     appType,
-		FullName: r.appFullName,
+		FullName: appFullName,
+		Zone:     zone,
 		Metadata: make(map[string]string),
 	}
 	for _, kv := range getResp.Kvs {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/errors"
+	"github.com/Lyndon-Zhang/gira/registry/usercache"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -22,6 +23,7 @@ type player_registry struct {
 	userPrefix string // /user/<<UserId>>/       	 	可以根据user_id查找当前所在的服
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+	userCache  *usercache.Cache // 玩家位置缓存, 没开启时为nil
 }
 
 func newConfigPlayerRegistry(r *RegistryClient) (*player_registry, error) {
@@ -31,11 +33,52 @@ func newConfigPlayerRegistry(r *RegistryClient) (*player_registry, error) {
 		ctx:        ctx,
 		cancelFunc: cancelFunc,
 	}
+	if r.config.UserCache.Enabled {
+		self.userCache = usercache.NewConfigCache(ctx, r.client, self.userPrefix, r.config.UserCache)
+	}
 	return self, nil
 }
 
+func (self *player_registry) stop(r *RegistryClient) {
+	if self.userCache != nil {
+		self.userCache.Stop()
+	}
+	self.cancelFunc()
+}
+
+// 加载玩家位置缓存，并在后台侦听变化
+func (self *player_registry) startUserCache(r *RegistryClient) error {
+	if self.userCache == nil {
+		return nil
+	}
+	if err := self.userCache.Start(); err != nil {
+		return err
+	}
+	go self.userCache.Serve()
+	return nil
+}
+
+func (self *player_registry) UserCacheStats(r *RegistryClient) gira.UserCacheStats {
+	if self.userCache == nil {
+		return gira.UserCacheStats{}
+	}
+	return self.userCache.Stats()
+}
+
 // 查找玩家位置
 func (self *player_registry) WhereIsUser(r *RegistryClient, userId string) (*gira.Peer, error) {
+	if self.userCache != nil {
+		fullName, err := self.userCache.Get(self.ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		peer := r.GetPeer(fullName)
+		if peer == nil {
+			return nil, errors.ErrPeerNotFound
+		} else {
+			return peer, nil
+		}
+	}
 	client := r.client
 	userKey := fmt.Sprintf("%s%s", self.userPrefix, userId)
 	kv := clientv3.NewKV(client)
@@ -12,9 +12,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
 	"github.com/Lyndon-Zhang/gira/options/service_options"
+	"github.com/Lyndon-Zhang/gira/util/hashring"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -22,6 +27,25 @@ type service_registry struct {
 	servicePrefix string // /service/<<ServiceName>>/      							可以根据服务名查找当前所在的服
 	ctx           context.Context
 	cancelFunc    context.CancelFunc
+	ringMu        sync.Mutex
+	rings         map[string]*service_ring // 一致性hash环, 按目录名索引, 第一次查询时加载并侦听变化
+}
+
+// 目录下的服务和对应的hash环, 服务变化时整体替换
+type service_ring struct {
+	ring     *hashring.Ring
+	services map[string]string // 服务全名 => 节点全名
+}
+
+func newServiceRing(services map[string]string) *service_ring {
+	members := make([]string, 0, len(services))
+	for name := range services {
+		members = append(members, name)
+	}
+	return &service_ring{
+		ring:     hashring.New(0, members...),
+		services: services,
+	}
 }
 
 func newConfigServiceRegistry(r *RegistryClient) (*service_registry, error) {
@@ -30,6 +54,7 @@ func newConfigServiceRegistry(r *RegistryClient) (*service_registry, error) {
 		servicePrefix: "/service/",
 		ctx:           ctx,
 		cancelFunc:    cancelFunc,
+		rings:         make(map[string]*service_ring),
 	}
 	return self, nil
 }
@@ -45,13 +70,65 @@ func (self *service_registry) NewServiceName(r *RegistryClient, serviceName stri
 	return serviceName
 }
 
+// 停止hash环的侦听
+func (self *service_registry) stop(r *RegistryClient) {
+	self.cancelFunc()
+}
+
 // 查找服务位置
 func (self *service_registry) WhereIsService(r *RegistryClient, serviceName string, opt ...service_options.WhereOption) (peers []*gira.Peer, err error) {
 	opts := service_options.WhereOptions{}
 	for _, v := range opt {
 		v.ConfigWhereOption(&opts)
 	}
-	if opts.Catalog || opts.Prefix {
+	// 指定其他区
+	if len(opts.Zone) > 0 && opts.Zone != r.zone {
+		if zone, ok := r.zoneClients[opts.Zone]; !ok {
+			return nil, errors.ErrZoneNotFound
+		} else {
+			return zone.serviceRegistry.whereIsLocalService(zone, serviceName, opts)
+		}
+	}
+	if peers, err = self.whereIsLocalService(r, serviceName, opts); err != nil || !opts.AllZone {
+		return
+	}
+	// 本区优先, 再按区名顺序查找其他区
+	multicast := len(opts.Key) <= 0 && (opts.Catalog || opts.Prefix)
+	for _, zoneName := range r.zoneNames {
+		if !multicast && len(peers) > 0 {
+			break
+		}
+		if multicast && opts.MaxCount > 0 && len(peers) >= opts.MaxCount {
+			break
+		}
+		zone := r.zoneClients[zoneName]
+		var zonePeers []*gira.Peer
+		if zonePeers, err = zone.serviceRegistry.whereIsLocalService(zone, serviceName, opts); err != nil {
+			return
+		}
+		peers = append(peers, zonePeers...)
+	}
+	if multicast && opts.MaxCount > 0 && len(peers) > opts.MaxCount {
+		peers = peers[:opts.MaxCount]
+	}
+	return
+}
+
+func (self *service_registry) whereIsLocalService(r *RegistryClient, serviceName string, opts service_options.WhereOptions) (peers []*gira.Peer, err error) {
+	if len(opts.Key) > 0 {
+		// 一致性hash
+		var ring *service_ring
+		if ring, err = self.getRing(r, strings.TrimSuffix(serviceName, "/")); err != nil {
+			return
+		}
+		if name, ok := ring.ring.Get(opts.Key); ok {
+			peer := r.GetPeer(ring.services[name])
+			if peer != nil {
+				peers = append(peers, peer)
+			}
+		}
+		return
+	} else if opts.Catalog || opts.Prefix {
 		var getOpts []clientv3.OpOption
 		getOpts = append(getOpts, clientv3.WithPrefix())
 		client := r.client
@@ -95,6 +172,78 @@ func (self *service_registry) WhereIsService(r *RegistryClient, serviceName stri
 	}
 }
 
+// 应用watch事件, 返回新的hash环
+func (ring *service_ring) apply(servicePrefix string, events []*clientv3.Event) *service_ring {
+	services := make(map[string]string, len(ring.services))
+	for name, value := range ring.services {
+		services[name] = value
+	}
+	for _, event := range events {
+		name := strings.TrimPrefix(string(event.Kv.Key), servicePrefix)
+		switch event.Type {
+		case mvccpb.PUT:
+			services[name] = string(event.Kv.Value)
+		case mvccpb.DELETE:
+			delete(services, name)
+		}
+	}
+	return newServiceRing(services)
+}
+
+// 返回目录对应的hash环, 不存在时加载并侦听目录的变化
+func (self *service_registry) getRing(r *RegistryClient, catalog string) (*service_ring, error) {
+	self.ringMu.Lock()
+	ring, ok := self.rings[catalog]
+	self.ringMu.Unlock()
+	if ok {
+		return ring, nil
+	}
+	prefix := fmt.Sprintf("%s%s/", self.servicePrefix, catalog)
+	kv := clientv3.NewKV(r.client)
+	getResp, err := kv.Get(self.ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]string, len(getResp.Kvs))
+	for _, kv := range getResp.Kvs {
+		services[strings.TrimPrefix(string(kv.Key), self.servicePrefix)] = string(kv.Value)
+	}
+	self.ringMu.Lock()
+	defer self.ringMu.Unlock()
+	// 同时有其他的查询加载完成
+	if ring, ok := self.rings[catalog]; ok {
+		return ring, nil
+	}
+	ring = newServiceRing(services)
+	self.rings[catalog] = ring
+	go self.watchRing(r, catalog, prefix, getResp.Header.Revision+1)
+	return ring, nil
+}
+
+// 侦听目录下服务的变化, 更新hash环, 侦听中断后移除, 下次查询时重新加载
+func (self *service_registry) watchRing(r *RegistryClient, catalog string, prefix string, rev int64) {
+	defer func() {
+		self.ringMu.Lock()
+		delete(self.rings, catalog)
+		self.ringMu.Unlock()
+	}()
+	watcher := clientv3.NewWatcher(r.client)
+	defer watcher.Close()
+	watchRespChan := watcher.Watch(self.ctx, prefix, clientv3.WithRev(rev), clientv3.WithPrefix())
+	for watchResp := range watchRespChan {
+		if err := watchResp.Err(); err != nil {
+			log.Warnw("service ring watch fail", "catalog", catalog, "error", err)
+			return
+		}
+		if len(watchResp.Events) <= 0 {
+			continue
+		}
+		self.ringMu.Lock()
+		self.rings[catalog] = self.rings[catalog].apply(self.servicePrefix, watchResp.Events)
+		self.ringMu.Unlock()
+	}
+}
+
 // 列出全部的服务
 func (self *service_registry) ListServiceKvs(r *RegistryClient) (kvs map[string][]string, err error) {
 	client := r.client
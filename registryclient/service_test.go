@@ -0,0 +1,40 @@
+package registryclient
+
+import (
+	"fmt"
+	"testing"
+
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestServiceRingApply(t *testing.T) {
+	ring := newServiceRing(map[string]string{
+		"hall/1": "hall_qq_dev_1",
+		"hall/2": "hall_qq_dev_2",
+	})
+	keys := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i)
+		keys[key], _ = ring.ring.Get(key)
+	}
+	next := ring.apply("/service/", []*clientv3.Event{
+		{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("/service/hall/2")}},
+		{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("/service/hall/3"), Value: []byte("hall_qq_dev_3")}},
+	})
+	// 旧的环不变, 查询中的调用不受影响
+	if len(ring.services) != 2 || ring.services["hall/2"] != "hall_qq_dev_2" {
+		t.Fatalf("old ring modified %v", ring.services)
+	}
+	if len(next.services) != 2 || next.services["hall/3"] != "hall_qq_dev_3" {
+		t.Fatalf("unexpected services %v", next.services)
+	}
+	// hall/2上的key迁移走, hall/1上的key只会迁移到新加的hall/3
+	for key, name := range keys {
+		if got, _ := next.ring.Get(key); got == "hall/2" {
+			t.Fatalf("key %s still on deleted service", key)
+		} else if name == "hall/1" && got != name && got != "hall/3" {
+			t.Fatalf("key %s moved from %s to %s", key, name, got)
+		}
+	}
+}
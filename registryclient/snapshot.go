@@ -0,0 +1,258 @@
+package registryclient
+
+///
+/// 注册表快照
+///   dump:    导出peer, service, user相关的全部key
+///   diff:    比较两个快照
+///   restore: 将快照中的key写回注册表
+///   gc:      清理所属节点已经不存在的key
+///
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// 快照包括的前缀
+var snapshotPrefixs = []string{
+	"/peer/",
+	"/peer_type/",
+	"/service/",
+	"/user/",
+}
+
+const (
+	SNAPSHOT_DIFF_ADDED   = "added"
+	SNAPSHOT_DIFF_REMOVED = "removed"
+	SNAPSHOT_DIFF_CHANGED = "changed"
+)
+
+type SnapshotKv struct {
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	CreateRevision int64  `json:"create_revision"`
+	ModRevision    int64  `json:"mod_revision"`
+	Version        int64  `json:"version"`
+	Lease          int64  `json:"lease"`
+}
+
+type Snapshot struct {
+	Revision int64         `json:"revision"`
+	Time     int64         `json:"time"`
+	Kvs      []*SnapshotKv `json:"kvs"`
+}
+
+type SnapshotDiff struct {
+	Key  string      `json:"key"`
+	Type string      `json:"type"` // added|removed|changed
+	Old  *SnapshotKv `json:"old,omitempty"`
+	New  *SnapshotKv `json:"new,omitempty"`
+}
+
+// 从文件读取快照
+func ReadSnapshotFile(filePath string) (*Snapshot, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// 写入文件
+func (snapshot *Snapshot) WriteFile(filePath string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// 比较两个快照, 只比较value
+func DiffSnapshot(old *Snapshot, new *Snapshot) []*SnapshotDiff {
+	oldKvs := make(map[string]*SnapshotKv, len(old.Kvs))
+	for _, kv := range old.Kvs {
+		oldKvs[kv.Key] = kv
+	}
+	newKvs := make(map[string]*SnapshotKv, len(new.Kvs))
+	for _, kv := range new.Kvs {
+		newKvs[kv.Key] = kv
+	}
+	diffs := make([]*SnapshotDiff, 0)
+	for key, oldKv := range oldKvs {
+		if newKv, ok := newKvs[key]; !ok {
+			diffs = append(diffs, &SnapshotDiff{Key: key, Type: SNAPSHOT_DIFF_REMOVED, Old: oldKv})
+		} else if newKv.Value != oldKv.Value {
+			diffs = append(diffs, &SnapshotDiff{Key: key, Type: SNAPSHOT_DIFF_CHANGED, Old: oldKv, New: newKv})
+		}
+	}
+	for key, newKv := range newKvs {
+		if _, ok := oldKvs[key]; !ok {
+			diffs = append(diffs, &SnapshotDiff{Key: key, Type: SNAPSHOT_DIFF_ADDED, New: newKv})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+// 导出快照
+func (r *RegistryClient) DumpSnapshot() (*Snapshot, error) {
+	kv := clientv3.NewKV(r.client)
+	snapshot := &Snapshot{
+		Time: time.Now().Unix(),
+		Kvs:  make([]*SnapshotKv, 0),
+	}
+	var getResp *clientv3.GetResponse
+	var err error
+	for _, prefix := range snapshotPrefixs {
+		// 全部前缀在同一个版本上读取
+		opts := []clientv3.OpOption{clientv3.WithPrefix()}
+		if snapshot.Revision > 0 {
+			opts = append(opts, clientv3.WithRev(snapshot.Revision))
+		}
+		if getResp, err = kv.Get(r.ctx, prefix, opts...); err != nil {
+			return nil, err
+		}
+		if snapshot.Revision <= 0 {
+			snapshot.Revision = getResp.Header.Revision
+		}
+		for _, v := range getResp.Kvs {
+			snapshot.Kvs = append(snapshot.Kvs, &SnapshotKv{
+				Key:            string(v.Key),
+				Value:          string(v.Value),
+				CreateRevision: v.CreateRevision,
+				ModRevision:    v.ModRevision,
+				Version:        v.Version,
+				Lease:          v.Lease,
+			})
+		}
+	}
+	return snapshot, nil
+}
+
+// 将快照中的key写回注册表
+// prefixs为空时恢复全部的key, 否则只恢复匹配前缀的key
+// 租约不会恢复, 带租约的key恢复后不会过期
+func (r *RegistryClient) RestoreSnapshot(snapshot *Snapshot, prefixs []string, dryRun bool) (restored []*SnapshotKv, err error) {
+	kv := clientv3.NewKV(r.client)
+	restored = make([]*SnapshotKv, 0)
+	for _, v := range snapshot.Kvs {
+		if len(prefixs) > 0 {
+			matched := false
+			for _, prefix := range prefixs {
+				if strings.HasPrefix(v.Key, prefix) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		var getResp *clientv3.GetResponse
+		if getResp, err = kv.Get(r.ctx, v.Key); err != nil {
+			return
+		}
+		var modRevision int64
+		if len(getResp.Kvs) > 0 {
+			if string(getResp.Kvs[0].Value) == v.Value {
+				continue
+			}
+			modRevision = getResp.Kvs[0].ModRevision
+		}
+		if dryRun {
+			restored = append(restored, v)
+			continue
+		}
+		// 读取后被修改过的话放弃
+		var txnResp *clientv3.TxnResponse
+		if txnResp, err = kv.Txn(r.ctx).
+			If(clientv3.Compare(clientv3.ModRevision(v.Key), "=", modRevision)).
+			Then(clientv3.OpPut(v.Key, v.Value)).
+			Commit(); err != nil {
+			return
+		}
+		if txnResp.Succeeded {
+			log.Infow("registry restore", "key", v.Key, "value", v.Value)
+			restored = append(restored, v)
+		} else {
+			log.Warnw("registry restore fail, key changed", "key", v.Key)
+		}
+	}
+	return
+}
+
+// 清理所属节点已经不存在的key
+func (r *RegistryClient) GcSnapshot(dryRun bool) (deleted []*SnapshotKv, err error) {
+	var snapshot *Snapshot
+	if snapshot, err = r.DumpSnapshot(); err != nil {
+		return
+	}
+	// 在线的节点
+	peers := make(map[string]bool)
+	for _, v := range snapshot.Kvs {
+		words := strings.Split(v.Key, "/")
+		// /peer/attribute/<<AppFullName>>/grpc
+		if len(words) == 5 && words[1] == "peer" && words[2] == "attribute" && words[4] == GRPC_KEY {
+			peers[words[3]] = true
+		}
+	}
+	garbages := make([]*SnapshotKv, 0)
+	for _, v := range snapshot.Kvs {
+		words := strings.Split(v.Key, "/")
+		if len(words) < 3 {
+			continue
+		}
+		var owner string
+		switch words[1] {
+		case "service", "user", "peer_type":
+			// 值为节点全名
+			owner = v.Value
+		case "peer":
+			// /peer/<<Catalog>>/<<AppFullName>>/...
+			if len(words) < 4 {
+				continue
+			}
+			owner = words[3]
+		}
+		if len(owner) > 0 && !peers[owner] {
+			garbages = append(garbages, v)
+		}
+	}
+	deleted = make([]*SnapshotKv, 0)
+	if dryRun {
+		deleted = append(deleted, garbages...)
+		return
+	}
+	kv := clientv3.NewKV(r.client)
+	ctx, cancelFunc := context.WithTimeout(r.ctx, 30*time.Second)
+	defer cancelFunc()
+	for _, v := range garbages {
+		// 读取后被修改过的话放弃
+		var txnResp *clientv3.TxnResponse
+		if txnResp, err = kv.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(v.Key), "=", v.ModRevision)).
+			Then(clientv3.OpDelete(v.Key)).
+			Commit(); err != nil {
+			return
+		}
+		if txnResp.Succeeded {
+			log.Infow("registry gc", "key", v.Key, "value", v.Value)
+			deleted = append(deleted, v)
+		} else {
+			log.Warnw("registry gc fail, key changed", "key", v.Key)
+		}
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package registryclient
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	snapshot := func(kvs ...string) *Snapshot {
+		s := &Snapshot{}
+		for i := 0; i < len(kvs); i += 2 {
+			s.Kvs = append(s.Kvs, &SnapshotKv{Key: kvs[i], Value: kvs[i+1], ModRevision: int64(i)})
+		}
+		return s
+	}
+	tests := []struct {
+		name  string
+		old   *Snapshot
+		new   *Snapshot
+		diffs []string // key:type
+	}{
+		{"both empty", snapshot(), snapshot(), nil},
+		{"same", snapshot("/user/1", "hall_1"), snapshot("/user/1", "hall_1"), nil},
+		// 只比较value, 版本不同不算修改
+		{"revision only", snapshot("/user/1", "hall_1"), &Snapshot{Kvs: []*SnapshotKv{{Key: "/user/1", Value: "hall_1", ModRevision: 9}}}, nil},
+		{"added", snapshot(), snapshot("/user/1", "hall_1"), []string{"/user/1:added"}},
+		{"removed", snapshot("/user/1", "hall_1"), snapshot(), []string{"/user/1:removed"}},
+		{"changed", snapshot("/user/1", "hall_1"), snapshot("/user/1", "hall_2"), []string{"/user/1:changed"}},
+		{
+			"sorted by key",
+			snapshot("/user/2", "hall_1", "/service/hall/1", "hall_1", "/peer/hall_1/grpc", "127.0.0.1:1001"),
+			snapshot("/user/2", "hall_2", "/user/1", "hall_1", "/peer/hall_1/grpc", "127.0.0.1:1001"),
+			[]string{"/service/hall/1:removed", "/user/1:added", "/user/2:changed"},
+		},
+	}
+	for _, tt := range tests {
+		diffs := DiffSnapshot(tt.old, tt.new)
+		if len(diffs) != len(tt.diffs) {
+			t.Errorf("%s: expected %v, got %d diffs", tt.name, tt.diffs, len(diffs))
+			continue
+		}
+		for i, diff := range diffs {
+			if got := diff.Key + ":" + diff.Type; got != tt.diffs[i] {
+				t.Errorf("%s: expected %s, got %s", tt.name, tt.diffs[i], got)
+			}
+			switch diff.Type {
+			case SNAPSHOT_DIFF_ADDED:
+				if diff.Old != nil || diff.New == nil {
+					t.Errorf("%s: unexpected added diff %+v", tt.name, diff)
+				}
+			case SNAPSHOT_DIFF_REMOVED:
+				if diff.Old == nil || diff.New != nil {
+					t.Errorf("%s: unexpected removed diff %+v", tt.name, diff)
+				}
+			case SNAPSHOT_DIFF_CHANGED:
+				if diff.Old == nil || diff.New == nil || diff.Old.Value == diff.New.Value {
+					t.Errorf("%s: unexpected changed diff %+v", tt.name, diff)
+				}
+			}
+		}
+	}
+}
+
+func TestSnapshotFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshot := &Snapshot{Revision: 10, Time: 1700000000, Kvs: []*SnapshotKv{
+		{Key: "/user/1", Value: "hall_1", CreateRevision: 3, ModRevision: 5, Version: 2, Lease: 7},
+	}}
+	if err := snapshot.WriteFile(filePath); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSnapshotFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Revision != 10 || len(read.Kvs) != 1 || *read.Kvs[0] != *snapshot.Kvs[0] {
+		t.Fatalf("unexpected snapshot %+v", read)
+	}
+	if diffs := DiffSnapshot(snapshot, read); len(diffs) != 0 {
+		t.Fatalf("unexpected diffs %v", diffs)
+	}
+}
@@ -1,6 +1,18 @@
 package gira
 
-import "context"
+import (
+	"context"
+
+	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
+)
+
+// 服务的状态
+const (
+	SERVICE_STATE_RUNNING    = "running"
+	SERVICE_STATE_RESTARTING = "restarting" // 等待重启
+	SERVICE_STATE_FAILED     = "failed"     // 出错后不再重启
+	SERVICE_STATE_STOPPED    = "stopped"
+)
 
 // 服务
 type Service interface {
@@ -12,10 +24,20 @@ type Service interface {
 	OnStop() error
 }
 
+type ServiceState struct {
+	Name         string
+	State        string
+	RestartCount int
+	LastError    string
+	StartTime    int64 // 最后一次启动的时间
+}
+
 // 服务容器
 type ServiceContainer interface {
-	// 启动服务
-	StartService(name string, service Service) error
-	// 停止服务
+	// 启动服务, 默认不重启, 出错时只停止这个服务
+	StartService(name string, service Service, opt ...service_options.StartOption) error
+	// 停止服务, 等待OnStop返回
 	StopService(service Service) error
+	// 全部服务的状态, 按名字排序
+	ServiceStates() []*ServiceState
 }
@@ -12,12 +12,11 @@ import (
 	gira "github.com/Lyndon-Zhang/gira"
 	errors "github.com/Lyndon-Zhang/gira/errors"
 	facade "github.com/Lyndon-Zhang/gira/facade"
+	policy "github.com/Lyndon-Zhang/gira/grpc/policy"
+	scatter "github.com/Lyndon-Zhang/gira/grpc/scatter"
 	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
 	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
 	metadata "google.golang.org/grpc/metadata"
-	status "google.golang.org/grpc/status"
-	sync "sync"
 	time "time"
 )
 
@@ -170,6 +169,150 @@ func (r *ReloadResourceResponse2_MulticastResult) Errors(index int) error {
 	return r.errors[index]
 }
 
+type ReloadConfigResponse_MulticastResult struct {
+	errors       []error
+	peerCount    int
+	successPeers []*gira.Peer
+	errorPeers   []*gira.Peer
+	responses    []*ReloadConfigResponse
+}
+
+func (r *ReloadConfigResponse_MulticastResult) Error() error {
+	if len(r.errors) <= 0 {
+		return nil
+	}
+	return r.errors[0]
+}
+func (r *ReloadConfigResponse_MulticastResult) Response(index int) *ReloadConfigResponse {
+	if index < 0 || index >= len(r.responses) {
+		return nil
+	}
+	return r.responses[index]
+}
+func (r *ReloadConfigResponse_MulticastResult) SuccessPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.successPeers) {
+		return nil
+	}
+	return r.successPeers[index]
+}
+func (r *ReloadConfigResponse_MulticastResult) ErrorPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.errorPeers) {
+		return nil
+	}
+	return r.errorPeers[index]
+}
+func (r *ReloadConfigResponse_MulticastResult) PeerCount() int {
+	return r.peerCount
+}
+func (r *ReloadConfigResponse_MulticastResult) SuccessCount() int {
+	return len(r.successPeers)
+}
+func (r *ReloadConfigResponse_MulticastResult) ErrorCount() int {
+	return len(r.errorPeers)
+}
+func (r *ReloadConfigResponse_MulticastResult) Errors(index int) error {
+	if index < 0 || index >= len(r.errors) {
+		return nil
+	}
+	return r.errors[index]
+}
+
+type RestartResponse_MulticastResult struct {
+	errors       []error
+	peerCount    int
+	successPeers []*gira.Peer
+	errorPeers   []*gira.Peer
+	responses    []*RestartResponse
+}
+
+func (r *RestartResponse_MulticastResult) Error() error {
+	if len(r.errors) <= 0 {
+		return nil
+	}
+	return r.errors[0]
+}
+func (r *RestartResponse_MulticastResult) Response(index int) *RestartResponse {
+	if index < 0 || index >= len(r.responses) {
+		return nil
+	}
+	return r.responses[index]
+}
+func (r *RestartResponse_MulticastResult) SuccessPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.successPeers) {
+		return nil
+	}
+	return r.successPeers[index]
+}
+func (r *RestartResponse_MulticastResult) ErrorPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.errorPeers) {
+		return nil
+	}
+	return r.errorPeers[index]
+}
+func (r *RestartResponse_MulticastResult) PeerCount() int {
+	return r.peerCount
+}
+func (r *RestartResponse_MulticastResult) SuccessCount() int {
+	return len(r.successPeers)
+}
+func (r *RestartResponse_MulticastResult) ErrorCount() int {
+	return len(r.errorPeers)
+}
+func (r *RestartResponse_MulticastResult) Errors(index int) error {
+	if index < 0 || index >= len(r.errors) {
+		return nil
+	}
+	return r.errors[index]
+}
+
+type ServiceStatusResponse_MulticastResult struct {
+	errors       []error
+	peerCount    int
+	successPeers []*gira.Peer
+	errorPeers   []*gira.Peer
+	responses    []*ServiceStatusResponse
+}
+
+func (r *ServiceStatusResponse_MulticastResult) Error() error {
+	if len(r.errors) <= 0 {
+		return nil
+	}
+	return r.errors[0]
+}
+func (r *ServiceStatusResponse_MulticastResult) Response(index int) *ServiceStatusResponse {
+	if index < 0 || index >= len(r.responses) {
+		return nil
+	}
+	return r.responses[index]
+}
+func (r *ServiceStatusResponse_MulticastResult) SuccessPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.successPeers) {
+		return nil
+	}
+	return r.successPeers[index]
+}
+func (r *ServiceStatusResponse_MulticastResult) ErrorPeer(index int) *gira.Peer {
+	if index < 0 || index >= len(r.errorPeers) {
+		return nil
+	}
+	return r.errorPeers[index]
+}
+func (r *ServiceStatusResponse_MulticastResult) PeerCount() int {
+	return r.peerCount
+}
+func (r *ServiceStatusResponse_MulticastResult) SuccessCount() int {
+	return len(r.successPeers)
+}
+func (r *ServiceStatusResponse_MulticastResult) ErrorCount() int {
+	return len(r.errorPeers)
+}
+func (r *ServiceStatusResponse_MulticastResult) Errors(index int) error {
+	if index < 0 || index >= len(r.errors) {
+		return nil
+	}
+	return r.errors[index]
+}
+
 const (
 	AdminServerName = "adminpb.Admin"
 )
@@ -189,16 +332,38 @@ type AdminClients interface {
 	ReloadResource1(ctx context.Context, address string, opts ...grpc.CallOption) (Admin_ReloadResource1Client, error)
 	ReloadResource2(ctx context.Context, address string, in *ReloadResourceRequest2, opts ...grpc.CallOption) (Admin_ReloadResource2Client, error)
 	ReloadResource3(ctx context.Context, address string, opts ...grpc.CallOption) (Admin_ReloadResource3Client, error)
+	ReloadConfig(ctx context.Context, address string, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	Restart(ctx context.Context, address string, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+	ServiceStatus(ctx context.Context, address string, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error)
 }
 
 type AdminClientsMulticast interface {
 	WhereRegex(regex string) AdminClientsMulticast
 	WherePrefix(prefix bool) AdminClientsMulticast
+	WhereZone(zone string) AdminClientsMulticast
+	WhereAllZone() AdminClientsMulticast
 	Local() AdminClientsMulticast
+	WithTimeout(timeout time.Duration) AdminClientsMulticast
+	WithRetry(retry int, backoff time.Duration) AdminClientsMulticast
+	WithHedge(delay time.Duration) AdminClientsMulticast
+	WithBreaker(enabled bool) AdminClientsMulticast
+	WithConcurrency(concurrency int) AdminClientsMulticast
+	WithQuorum(quorum int) AdminClientsMulticast
 	ReloadResource(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) (*ReloadResourceResponse_MulticastResult, error)
+	ReloadResourceGather(ctx context.Context, in *ReloadResourceRequest, f func(result *scatter.Result[*ReloadResourceResponse]), opts ...grpc.CallOption) error
+	ReloadResourceGatherChan(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) *scatter.Stream[*ReloadResourceResponse]
 	ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (*Admin_ReloadResource1Client_MulticastResult, error)
 	ReloadResource2(ctx context.Context, in *ReloadResourceRequest2, opts ...grpc.CallOption) (*Admin_ReloadResource2Client_MulticastResult, error)
 	ReloadResource3(ctx context.Context, opts ...grpc.CallOption) (*Admin_ReloadResource3Client_MulticastResult, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse_MulticastResult, error)
+	ReloadConfigGather(ctx context.Context, in *ReloadConfigRequest, f func(result *scatter.Result[*ReloadConfigResponse]), opts ...grpc.CallOption) error
+	ReloadConfigGatherChan(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) *scatter.Stream[*ReloadConfigResponse]
+	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse_MulticastResult, error)
+	RestartGather(ctx context.Context, in *RestartRequest, f func(result *scatter.Result[*RestartResponse]), opts ...grpc.CallOption) error
+	RestartGatherChan(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) *scatter.Stream[*RestartResponse]
+	ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse_MulticastResult, error)
+	ServiceStatusGather(ctx context.Context, in *ServiceStatusRequest, f func(result *scatter.Result[*ServiceStatusResponse]), opts ...grpc.CallOption) error
+	ServiceStatusGatherChan(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) *scatter.Stream[*ServiceStatusResponse]
 }
 
 type AdminClientsUnicast interface {
@@ -207,66 +372,42 @@ type AdminClientsUnicast interface {
 	WherePeerFullName(appFullName string) AdminClientsUnicast
 	WhereAddress(address string) AdminClientsUnicast
 	WhereUser(userId string) AdminClientsUnicast
+	WhereKey(key string) AdminClientsUnicast
+	WhereZone(zone string) AdminClientsUnicast
+	WhereAllZone() AdminClientsUnicast
 	Local() AdminClientsUnicast
+	WithTimeout(timeout time.Duration) AdminClientsUnicast
+	WithRetry(retry int, backoff time.Duration) AdminClientsUnicast
+	WithHedge(delay time.Duration) AdminClientsUnicast
+	WithBreaker(enabled bool) AdminClientsUnicast
 
 	ReloadResource(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) (*ReloadResourceResponse, error)
 	ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource1Client, error)
 	ReloadResource2(ctx context.Context, in *ReloadResourceRequest2, opts ...grpc.CallOption) (Admin_ReloadResource2Client, error)
 	ReloadResource3(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource3Client, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+	ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error)
 }
 
 type adminClients struct {
-	mu          sync.Mutex
-	clientPool  map[string]*sync.Pool
 	serviceName string
 }
 
 func NewAdminClients() AdminClients {
 	return &adminClients{
 		serviceName: AdminServerName,
-		clientPool:  make(map[string]*sync.Pool, 0),
 	}
 }
 
 var DefaultAdminClients = NewAdminClients()
 
 func (c *adminClients) getClient(address string) (AdminClient, error) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; !ok {
-		pool = &sync.Pool{
-			New: func() any {
-				conn, err := grpc.Dial(address, grpc.WithInsecure())
-				if err != nil {
-					return err
-				}
-				client := NewAdminClient(conn)
-				return client
-			},
-		}
-		c.clientPool[address] = pool
-		c.mu.Unlock()
-	} else {
-		c.mu.Unlock()
-	}
-	if v := pool.Get(); v == nil {
-		return nil, errors.ErrGrpcClientPoolNil
-	} else if err, ok := v.(error); ok {
+	conn, err := facade.GetGrpcConn(address)
+	if err != nil {
 		return nil, err
-	} else {
-		return v.(AdminClient), nil
-	}
-}
-
-func (c *adminClients) putClient(address string, client AdminClient) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; ok {
-		pool.Put(client)
 	}
-	c.mu.Unlock()
+	return NewAdminClient(conn), nil
 }
 
 func (c *adminClients) WithServiceName(serviceName string) AdminClients {
@@ -277,7 +418,8 @@ func (c *adminClients) WithServiceName(serviceName string) AdminClients {
 func (c *adminClients) Unicast() AdminClientsUnicast {
 	headers := make(map[string]string)
 	u := &adminClientsUnicast{
-		timeout: 5,
+		timeout: 5 * time.Second,
+		breaker: true,
 		headers: metadata.New(headers),
 		client:  c,
 	}
@@ -287,7 +429,8 @@ func (c *adminClients) Unicast() AdminClientsUnicast {
 func (c *adminClients) Multicast(count int) AdminClientsMulticast {
 	headers := make(map[string]string)
 	u := &adminClientsMulticast{
-		timeout:     5,
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       count,
 		headers:     metadata.New(headers),
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
@@ -298,6 +441,8 @@ func (c *adminClients) Multicast(count int) AdminClientsMulticast {
 
 func (c *adminClients) Broadcast() AdminClientsMulticast {
 	u := &adminClientsMulticast{
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       -1,
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
 		client:      c,
@@ -310,7 +455,6 @@ func (c *adminClients) ReloadResource(ctx context.Context, address string, in *R
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.ReloadResource(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -323,7 +467,6 @@ func (c *adminClients) ReloadResource1(ctx context.Context, address string, opts
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.ReloadResource1(ctx, opts...)
 	if err != nil {
 		return nil, err
@@ -336,7 +479,6 @@ func (c *adminClients) ReloadResource2(ctx context.Context, address string, in *
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.ReloadResource2(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -349,7 +491,6 @@ func (c *adminClients) ReloadResource3(ctx context.Context, address string, opts
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.ReloadResource3(ctx, opts...)
 	if err != nil {
 		return nil, err
@@ -357,13 +498,56 @@ func (c *adminClients) ReloadResource3(ctx context.Context, address string, opts
 	return out, nil
 }
 
+func (c *adminClients) ReloadConfig(ctx context.Context, address string, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	client, err := c.getClient(address)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.ReloadConfig(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClients) Restart(ctx context.Context, address string, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
+	client, err := c.getClient(address)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.Restart(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClients) ServiceStatus(ctx context.Context, address string, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error) {
+	client, err := c.getClient(address)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.ServiceStatus(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type adminClientsUnicast struct {
-	timeout      int64
+	timeout      time.Duration
+	retry        int
+	backoff      time.Duration
+	hedge        time.Duration
+	breaker      bool
 	peer         *gira.Peer
 	peerFullName string
 	serviceName  string
 	address      string
 	userId       string
+	key          string
+	zone         string
+	allZone      bool
 	local        bool
 	client       *adminClients
 	headers      metadata.MD
@@ -400,9 +584,73 @@ func (c *adminClientsUnicast) WhereUser(userId string) AdminClientsUnicast {
 	return c
 }
 
+// 根据key一致性hash选择服务, 服务名为Where设置的目录，默认为服务目录
+func (c *adminClientsUnicast) WhereKey(key string) AdminClientsUnicast {
+	c.key = key
+	return c
+}
+
+// 在指定的区查找服务, 默认只查找本区
+func (c *adminClientsUnicast) WhereZone(zone string) AdminClientsUnicast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *adminClientsUnicast) WhereAllZone() AdminClientsUnicast {
+	c.allZone = true
+	return c
+}
+
+// 调用超时, 包括重试的时间
+func (c *adminClientsUnicast) WithTimeout(timeout time.Duration) AdminClientsUnicast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *adminClientsUnicast) WithRetry(retry int, backoff time.Duration) AdminClientsUnicast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *adminClientsUnicast) WithHedge(delay time.Duration) AdminClientsUnicast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *adminClientsUnicast) WithBreaker(enabled bool) AdminClientsUnicast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *adminClientsUnicast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
+func (c *adminClientsUnicast) whereOpts(opts ...service_options.WhereOption) []service_options.WhereOption {
+	if len(c.zone) > 0 {
+		opts = append(opts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		opts = append(opts, service_options.WithWhereAllZoneOption())
+	}
+	return opts
+}
+
 func (c *adminClientsUnicast) ReloadResource(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) (*ReloadResourceResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -417,9 +665,12 @@ func (c *adminClientsUnicast) ReloadResource(ctx context.Context, in *ReloadReso
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -429,25 +680,47 @@ func (c *adminClientsUnicast) ReloadResource(ctx context.Context, in *ReloadReso
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -455,29 +728,31 @@ func (c *adminClientsUnicast) ReloadResource(ctx context.Context, in *ReloadReso
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.ReloadResource(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*ReloadResourceResponse, error) {
+			return client.ReloadResource(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *adminClientsUnicast) ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource1Client, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource1 not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		c.local = false
+		return c.WherePeerFullName(facade.GetAppFullName()).ReloadResource1(ctx, opts...)
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -487,25 +762,47 @@ func (c *adminClientsUnicast) ReloadResource1(ctx context.Context, opts ...grpc.
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -513,29 +810,32 @@ func (c *adminClientsUnicast) ReloadResource1(ctx context.Context, opts ...grpc.
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.ReloadResource1(ctx, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		// 流不设置超时, 只使用熔断
+		return policy.Invoke(ctx, policy.Policy{Breaker: c.breaker}, peerName, address, func(ctx context.Context) (Admin_ReloadResource1Client, error) {
+			return client.ReloadResource1(ctx, opts...)
+		})
 	}
 
 }
 func (c *adminClientsUnicast) ReloadResource2(ctx context.Context, in *ReloadResourceRequest2, opts ...grpc.CallOption) (Admin_ReloadResource2Client, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource2 not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		c.local = false
+		return c.WherePeerFullName(facade.GetAppFullName()).ReloadResource2(ctx, in, opts...)
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -545,25 +845,47 @@ func (c *adminClientsUnicast) ReloadResource2(ctx context.Context, in *ReloadRes
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -571,29 +893,32 @@ func (c *adminClientsUnicast) ReloadResource2(ctx context.Context, in *ReloadRes
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.ReloadResource2(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		// 流不设置超时, 只使用熔断
+		return policy.Invoke(ctx, policy.Policy{Breaker: c.breaker}, peerName, address, func(ctx context.Context) (Admin_ReloadResource2Client, error) {
+			return client.ReloadResource2(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *adminClientsUnicast) ReloadResource3(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource3Client, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource3 not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		c.local = false
+		return c.WherePeerFullName(facade.GetAppFullName()).ReloadResource3(ctx, opts...)
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -603,25 +928,47 @@ func (c *adminClientsUnicast) ReloadResource3(ctx context.Context, opts ...grpc.
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -629,36 +976,315 @@ func (c *adminClientsUnicast) ReloadResource3(ctx context.Context, opts ...grpc.
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.ReloadResource3(ctx, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		// 流不设置超时, 只使用熔断
+		return policy.Invoke(ctx, policy.Policy{Breaker: c.breaker}, peerName, address, func(ctx context.Context) (Admin_ReloadResource3Client, error) {
+			return client.ReloadResource3(ctx, opts...)
+		})
 	}
 
 }
+func (c *adminClientsUnicast) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	if c.local {
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+		defer cancelFunc()
+		if c.headers.Len() > 0 {
+			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+		}
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); !ok {
+			return nil, errors.ErrServerNotFound
+		} else {
+			return svr.ReloadConfig(cancelCtx, in)
+		}
 
-type adminClientsMulticast struct {
-	timeout     int64
-	count       int
-	serviceName string
-	regex       string
-	prefix      bool
-	local       bool
-	client      *adminClients
-	headers     metadata.MD
-}
-
-type Admin_ReloadResource1Client_MulticastResult struct {
-	errors       []error
-	peerCount    int
-	successPeers []*gira.Peer
+	} else {
+		var address string
+		var peerName string
+		if len(c.address) > 0 {
+			address = c.address
+			peerName = c.address
+		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
+			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+		} else if c.peer != nil && facade.IsEnableResolver() {
+			address = c.peer.Url
+			peerName = c.peer.FullName
+		} else if c.peer != nil {
+			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.serviceName) > 0 {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.userId) > 0 {
+			if peer, err := facade.WhereIsUser(c.userId); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+				peerName = peer.FullName
+			} else {
+				address = peer.Address
+				peerName = peer.FullName
+			}
+		}
+		if len(address) <= 0 {
+			return nil, errors.ErrPeerNotFound
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peerName, err)
+		}
+		if c.headers.Len() > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, c.headers)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*ReloadConfigResponse, error) {
+			return client.ReloadConfig(ctx, in, opts...)
+		})
+	}
+
+}
+func (c *adminClientsUnicast) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
+	if c.local {
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+		defer cancelFunc()
+		if c.headers.Len() > 0 {
+			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+		}
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); !ok {
+			return nil, errors.ErrServerNotFound
+		} else {
+			return svr.Restart(cancelCtx, in)
+		}
+
+	} else {
+		var address string
+		var peerName string
+		if len(c.address) > 0 {
+			address = c.address
+			peerName = c.address
+		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
+			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+		} else if c.peer != nil && facade.IsEnableResolver() {
+			address = c.peer.Url
+			peerName = c.peer.FullName
+		} else if c.peer != nil {
+			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.serviceName) > 0 {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.userId) > 0 {
+			if peer, err := facade.WhereIsUser(c.userId); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+				peerName = peer.FullName
+			} else {
+				address = peer.Address
+				peerName = peer.FullName
+			}
+		}
+		if len(address) <= 0 {
+			return nil, errors.ErrPeerNotFound
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peerName, err)
+		}
+		if c.headers.Len() > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, c.headers)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*RestartResponse, error) {
+			return client.Restart(ctx, in, opts...)
+		})
+	}
+
+}
+func (c *adminClientsUnicast) ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error) {
+	if c.local {
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+		defer cancelFunc()
+		if c.headers.Len() > 0 {
+			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+		}
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); !ok {
+			return nil, errors.ErrServerNotFound
+		} else {
+			return svr.ServiceStatus(cancelCtx, in)
+		}
+
+	} else {
+		var address string
+		var peerName string
+		if len(c.address) > 0 {
+			address = c.address
+			peerName = c.address
+		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
+			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+		} else if c.peer != nil && facade.IsEnableResolver() {
+			address = c.peer.Url
+			peerName = c.peer.FullName
+		} else if c.peer != nil {
+			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.serviceName) > 0 {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
+		} else if len(c.userId) > 0 {
+			if peer, err := facade.WhereIsUser(c.userId); err != nil {
+				return nil, err
+			} else if facade.IsEnableResolver() {
+				address = peer.Url
+				peerName = peer.FullName
+			} else {
+				address = peer.Address
+				peerName = peer.FullName
+			}
+		}
+		if len(address) <= 0 {
+			return nil, errors.ErrPeerNotFound
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peerName, err)
+		}
+		if c.headers.Len() > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, c.headers)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*ServiceStatusResponse, error) {
+			return client.ServiceStatus(ctx, in, opts...)
+		})
+	}
+
+}
+
+type adminClientsMulticast struct {
+	timeout     time.Duration
+	retry       int
+	backoff     time.Duration
+	hedge       time.Duration
+	breaker     bool
+	concurrency int
+	quorum      int
+	count       int
+	serviceName string
+	regex       string
+	prefix      bool
+	zone        string
+	allZone     bool
+	local       bool
+	client      *adminClients
+	headers     metadata.MD
+}
+
+type Admin_ReloadResource1Client_MulticastResult struct {
+	errors       []error
+	peerCount    int
+	successPeers []*gira.Peer
 	errorPeers   []*gira.Peer
 	responses    []Admin_ReloadResource1Client
 }
@@ -803,6 +1429,42 @@ func (c *adminClientsMulticast) Local() AdminClientsMulticast {
 	return c
 }
 
+// 调用超时, 包括重试的时间
+func (c *adminClientsMulticast) WithTimeout(timeout time.Duration) AdminClientsMulticast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *adminClientsMulticast) WithRetry(retry int, backoff time.Duration) AdminClientsMulticast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *adminClientsMulticast) WithHedge(delay time.Duration) AdminClientsMulticast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *adminClientsMulticast) WithBreaker(enabled bool) AdminClientsMulticast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *adminClientsMulticast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
 func (c *adminClientsMulticast) WhereRegex(regex string) AdminClientsMulticast {
 	c.regex = regex
 	return c
@@ -813,13 +1475,68 @@ func (c *adminClientsMulticast) WherePrefix(prefix bool) AdminClientsMulticast {
 	return c
 }
 
+// 在指定的区查找服务, 默认只查找本区
+func (c *adminClientsMulticast) WhereZone(zone string) AdminClientsMulticast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *adminClientsMulticast) WhereAllZone() AdminClientsMulticast {
+	c.allZone = true
+	return c
+}
+
+// 最多同时调用多少个节点, 只对Gather生效, 0表示不限制
+func (c *adminClientsMulticast) WithConcurrency(concurrency int) AdminClientsMulticast {
+	c.concurrency = concurrency
+	return c
+}
+
+// 成功多少个节点后返回, 只对Gather生效, 0表示等待全部节点
+func (c *adminClientsMulticast) WithQuorum(quorum int) AdminClientsMulticast {
+	c.quorum = quorum
+	return c
+}
+
+func (c *adminClientsMulticast) gatherOptions() scatter.Options {
+	return scatter.Options{
+		Concurrency: c.concurrency,
+		Quorum:      c.quorum,
+	}
+}
+
+// 查找要调用的节点, Gather不支持Local
+func (c *adminClientsMulticast) wherePeers() ([]*gira.Peer, error) {
+	var whereOpts []service_options.WhereOption
+	whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+	if c.count > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+	}
+	serviceName := c.serviceName
+	if len(c.regex) > 0 {
+		serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+		whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+	}
+	if c.prefix {
+		whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+	}
+	if len(c.zone) > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+	}
+	return facade.WhereIsServiceName(serviceName, whereOpts...)
+}
+
 func (c *adminClientsMulticast) ReloadResource(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) (*ReloadResourceResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(AdminServer); ok {
 			result := &ReloadResourceResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -849,6 +1566,12 @@ func (c *adminClientsMulticast) ReloadResource(ctx context.Context, in *ReloadRe
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -864,18 +1587,18 @@ func (c *adminClientsMulticast) ReloadResource(ctx context.Context, in *ReloadRe
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.ReloadResource(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ReloadResourceResponse, error) {
+				return client.ReloadResource(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -883,26 +1606,82 @@ func (c *adminClientsMulticast) ReloadResource(ctx context.Context, in *ReloadRe
 	}
 
 }
-func (c *adminClientsMulticast) ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (*Admin_ReloadResource1Client_MulticastResult, error) {
-	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource1 not implemented")
 
-	} else {
-		var peers []*gira.Peer
-		var whereOpts []service_options.WhereOption
-		// 多播
-		whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
-		if c.count > 0 {
-			whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
-		}
-		serviceName := c.serviceName
-		if len(c.regex) > 0 {
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *adminClientsMulticast) ReloadResourceGather(ctx context.Context, in *ReloadResourceRequest, f func(result *scatter.Result[*ReloadResourceResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.reloadResourceCall(in, opts...), f)
+}
+
+// 和ReloadResourceGather一样, 结果通过channel返回
+func (c *adminClientsMulticast) ReloadResourceGatherChan(ctx context.Context, in *ReloadResourceRequest, opts ...grpc.CallOption) *scatter.Stream[*ReloadResourceResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*ReloadResourceResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.reloadResourceCall(in, opts...))
+}
+
+func (c *adminClientsMulticast) reloadResourceCall(in *ReloadResourceRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*ReloadResourceResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*ReloadResourceResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ReloadResourceResponse, error) {
+			return client.ReloadResource(ctx, in, opts...)
+		})
+	}
+}
+
+func (c *adminClientsMulticast) ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (*Admin_ReloadResource1Client_MulticastResult, error) {
+	if c.local {
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		peer, err := facade.WhereIsPeer(facade.GetAppFullName())
+		if err != nil {
+			return nil, err
+		}
+		result := &Admin_ReloadResource1Client_MulticastResult{peerCount: 1}
+		if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).ReloadResource1(ctx, opts...); err != nil {
+			result.errors = append(result.errors, err)
+			result.errorPeers = append(result.errorPeers, peer)
+		} else {
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
+
+	} else {
+		var peers []*gira.Peer
+		var whereOpts []service_options.WhereOption
+		// 多播
+		whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+		if c.count > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+		}
+		serviceName := c.serviceName
+		if len(c.regex) > 0 {
 			serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
 			whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
 		}
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -926,7 +1705,6 @@ func (c *adminClientsMulticast) ReloadResource1(ctx context.Context, opts ...grp
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
 			result.responses = append(result.responses, out)
@@ -938,7 +1716,20 @@ func (c *adminClientsMulticast) ReloadResource1(ctx context.Context, opts ...grp
 }
 func (c *adminClientsMulticast) ReloadResource2(ctx context.Context, in *ReloadResourceRequest2, opts ...grpc.CallOption) (*Admin_ReloadResource2Client_MulticastResult, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource2 not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		peer, err := facade.WhereIsPeer(facade.GetAppFullName())
+		if err != nil {
+			return nil, err
+		}
+		result := &Admin_ReloadResource2Client_MulticastResult{peerCount: 1}
+		if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).ReloadResource2(ctx, in, opts...); err != nil {
+			result.errors = append(result.errors, err)
+			result.errorPeers = append(result.errorPeers, peer)
+		} else {
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
 
 	} else {
 		var peers []*gira.Peer
@@ -956,6 +1747,12 @@ func (c *adminClientsMulticast) ReloadResource2(ctx context.Context, in *ReloadR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -979,7 +1776,6 @@ func (c *adminClientsMulticast) ReloadResource2(ctx context.Context, in *ReloadR
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
 			result.responses = append(result.responses, out)
@@ -991,7 +1787,20 @@ func (c *adminClientsMulticast) ReloadResource2(ctx context.Context, in *ReloadR
 }
 func (c *adminClientsMulticast) ReloadResource3(ctx context.Context, opts ...grpc.CallOption) (*Admin_ReloadResource3Client_MulticastResult, error) {
 	if c.local {
-		return nil, status.Errorf(codes.Unimplemented, "method ReloadResource3 not implemented")
+		// 流不能在进程内直接调用, 通过本节点的地址建立
+		peer, err := facade.WhereIsPeer(facade.GetAppFullName())
+		if err != nil {
+			return nil, err
+		}
+		result := &Admin_ReloadResource3Client_MulticastResult{peerCount: 1}
+		if out, err := c.client.Unicast().WherePeer(peer).WithBreaker(c.breaker).ReloadResource3(ctx, opts...); err != nil {
+			result.errors = append(result.errors, err)
+			result.errorPeers = append(result.errorPeers, peer)
+		} else {
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
 
 	} else {
 		var peers []*gira.Peer
@@ -1009,6 +1818,12 @@ func (c *adminClientsMulticast) ReloadResource3(ctx context.Context, opts ...grp
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1032,7 +1847,6 @@ func (c *adminClientsMulticast) ReloadResource3(ctx context.Context, opts ...grp
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
 			result.responses = append(result.responses, out)
@@ -1042,3 +1856,341 @@ func (c *adminClientsMulticast) ReloadResource3(ctx context.Context, opts ...grp
 	}
 
 }
+func (c *adminClientsMulticast) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse_MulticastResult, error) {
+	if c.local {
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); ok {
+			result := &ReloadConfigResponse_MulticastResult{}
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+			defer cancelFunc()
+			if c.headers.Len() > 0 {
+				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+			}
+			if resp, err := svr.ReloadConfig(cancelCtx, in); err != nil {
+				return nil, err
+			} else {
+				result.responses = append(result.responses, resp)
+			}
+			return result, nil
+		} else {
+			return nil, errors.ErrServerNotFound
+		}
+	} else {
+		var peers []*gira.Peer
+		var whereOpts []service_options.WhereOption
+		// 多播
+		whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+		if c.count > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+		}
+		serviceName := c.serviceName
+		if len(c.regex) > 0 {
+			serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+			whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+		}
+		if c.prefix {
+			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
+		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
+		if err != nil {
+			return nil, err
+		}
+		result := &ReloadConfigResponse_MulticastResult{}
+		result.peerCount = len(peers)
+		for _, peer := range peers {
+			var address string
+			if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+			client, err := c.client.getClient(address)
+			if err != nil {
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ReloadConfigResponse, error) {
+				return client.ReloadConfig(ctx, in, opts...)
+			})
+			if err != nil {
+				result.errors = append(result.errors, err)
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
+	}
+
+}
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *adminClientsMulticast) ReloadConfigGather(ctx context.Context, in *ReloadConfigRequest, f func(result *scatter.Result[*ReloadConfigResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.reloadConfigCall(in, opts...), f)
+}
+
+// 和ReloadConfigGather一样, 结果通过channel返回
+func (c *adminClientsMulticast) ReloadConfigGatherChan(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) *scatter.Stream[*ReloadConfigResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*ReloadConfigResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.reloadConfigCall(in, opts...))
+}
+
+func (c *adminClientsMulticast) reloadConfigCall(in *ReloadConfigRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*ReloadConfigResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*ReloadConfigResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ReloadConfigResponse, error) {
+			return client.ReloadConfig(ctx, in, opts...)
+		})
+	}
+}
+
+func (c *adminClientsMulticast) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse_MulticastResult, error) {
+	if c.local {
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); ok {
+			result := &RestartResponse_MulticastResult{}
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+			defer cancelFunc()
+			if c.headers.Len() > 0 {
+				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+			}
+			if resp, err := svr.Restart(cancelCtx, in); err != nil {
+				return nil, err
+			} else {
+				result.responses = append(result.responses, resp)
+			}
+			return result, nil
+		} else {
+			return nil, errors.ErrServerNotFound
+		}
+	} else {
+		var peers []*gira.Peer
+		var whereOpts []service_options.WhereOption
+		// 多播
+		whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+		if c.count > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+		}
+		serviceName := c.serviceName
+		if len(c.regex) > 0 {
+			serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+			whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+		}
+		if c.prefix {
+			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
+		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
+		if err != nil {
+			return nil, err
+		}
+		result := &RestartResponse_MulticastResult{}
+		result.peerCount = len(peers)
+		for _, peer := range peers {
+			var address string
+			if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+			client, err := c.client.getClient(address)
+			if err != nil {
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*RestartResponse, error) {
+				return client.Restart(ctx, in, opts...)
+			})
+			if err != nil {
+				result.errors = append(result.errors, err)
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
+	}
+
+}
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *adminClientsMulticast) RestartGather(ctx context.Context, in *RestartRequest, f func(result *scatter.Result[*RestartResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.restartCall(in, opts...), f)
+}
+
+// 和RestartGather一样, 结果通过channel返回
+func (c *adminClientsMulticast) RestartGatherChan(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) *scatter.Stream[*RestartResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*RestartResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.restartCall(in, opts...))
+}
+
+func (c *adminClientsMulticast) restartCall(in *RestartRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*RestartResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*RestartResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*RestartResponse, error) {
+			return client.Restart(ctx, in, opts...)
+		})
+	}
+}
+
+func (c *adminClientsMulticast) ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse_MulticastResult, error) {
+	if c.local {
+		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
+			return nil, errors.ErrServerNotFound
+		} else if svr, ok := s.(AdminServer); ok {
+			result := &ServiceStatusResponse_MulticastResult{}
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
+			defer cancelFunc()
+			if c.headers.Len() > 0 {
+				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
+			}
+			if resp, err := svr.ServiceStatus(cancelCtx, in); err != nil {
+				return nil, err
+			} else {
+				result.responses = append(result.responses, resp)
+			}
+			return result, nil
+		} else {
+			return nil, errors.ErrServerNotFound
+		}
+	} else {
+		var peers []*gira.Peer
+		var whereOpts []service_options.WhereOption
+		// 多播
+		whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+		if c.count > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+		}
+		serviceName := c.serviceName
+		if len(c.regex) > 0 {
+			serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+			whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+		}
+		if c.prefix {
+			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
+		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
+		if err != nil {
+			return nil, err
+		}
+		result := &ServiceStatusResponse_MulticastResult{}
+		result.peerCount = len(peers)
+		for _, peer := range peers {
+			var address string
+			if facade.IsEnableResolver() {
+				address = peer.Url
+			} else {
+				address = peer.Address
+			}
+			client, err := c.client.getClient(address)
+			if err != nil {
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ServiceStatusResponse, error) {
+				return client.ServiceStatus(ctx, in, opts...)
+			})
+			if err != nil {
+				result.errors = append(result.errors, err)
+				result.errorPeers = append(result.errorPeers, peer)
+				continue
+			}
+			result.responses = append(result.responses, out)
+			result.successPeers = append(result.successPeers, peer)
+		}
+		return result, nil
+	}
+
+}
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *adminClientsMulticast) ServiceStatusGather(ctx context.Context, in *ServiceStatusRequest, f func(result *scatter.Result[*ServiceStatusResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.serviceStatusCall(in, opts...), f)
+}
+
+// 和ServiceStatusGather一样, 结果通过channel返回
+func (c *adminClientsMulticast) ServiceStatusGatherChan(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) *scatter.Stream[*ServiceStatusResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*ServiceStatusResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.serviceStatusCall(in, opts...))
+}
+
+func (c *adminClientsMulticast) serviceStatusCall(in *ServiceStatusRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*ServiceStatusResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*ServiceStatusResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*ServiceStatusResponse, error) {
+			return client.ServiceStatus(ctx, in, opts...)
+		})
+	}
+}
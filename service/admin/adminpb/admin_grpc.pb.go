@@ -23,6 +23,9 @@ const (
 	Admin_ReloadResource1_FullMethodName = "/adminpb.Admin/ReloadResource1"
 	Admin_ReloadResource2_FullMethodName = "/adminpb.Admin/ReloadResource2"
 	Admin_ReloadResource3_FullMethodName = "/adminpb.Admin/ReloadResource3"
+	Admin_ReloadConfig_FullMethodName    = "/adminpb.Admin/ReloadConfig"
+	Admin_Restart_FullMethodName         = "/adminpb.Admin/Restart"
+	Admin_ServiceStatus_FullMethodName   = "/adminpb.Admin/ServiceStatus"
 )
 
 // AdminClient is the client API for Admin service.
@@ -33,6 +36,9 @@ type AdminClient interface {
 	ReloadResource1(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource1Client, error)
 	ReloadResource2(ctx context.Context, in *ReloadResourceRequest2, opts ...grpc.CallOption) (Admin_ReloadResource2Client, error)
 	ReloadResource3(ctx context.Context, opts ...grpc.CallOption) (Admin_ReloadResource3Client, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error)
+	ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error)
 }
 
 type adminClient struct {
@@ -149,6 +155,33 @@ func (x *adminReloadResource3Client) Recv() (*ReloadResourceResponse2, error) {
 	return m, nil
 }
 
+func (c *adminClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, Admin_ReloadConfig_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Restart(ctx context.Context, in *RestartRequest, opts ...grpc.CallOption) (*RestartResponse, error) {
+	out := new(RestartResponse)
+	err := c.cc.Invoke(ctx, Admin_Restart_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ServiceStatus(ctx context.Context, in *ServiceStatusRequest, opts ...grpc.CallOption) (*ServiceStatusResponse, error) {
+	out := new(ServiceStatusResponse)
+	err := c.cc.Invoke(ctx, Admin_ServiceStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServer is the server API for Admin service.
 // All implementations must embed UnimplementedAdminServer
 // for forward compatibility
@@ -157,6 +190,9 @@ type AdminServer interface {
 	ReloadResource1(Admin_ReloadResource1Server) error
 	ReloadResource2(*ReloadResourceRequest2, Admin_ReloadResource2Server) error
 	ReloadResource3(Admin_ReloadResource3Server) error
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	Restart(context.Context, *RestartRequest) (*RestartResponse, error)
+	ServiceStatus(context.Context, *ServiceStatusRequest) (*ServiceStatusResponse, error)
 	mustEmbedUnimplementedAdminServer()
 }
 
@@ -176,6 +212,15 @@ func (UnimplementedAdminServer) ReloadResource2(*ReloadResourceRequest2, Admin_R
 func (UnimplementedAdminServer) ReloadResource3(Admin_ReloadResource3Server) error {
 	return status.Errorf(codes.Unimplemented, "method ReloadResource3 not implemented")
 }
+func (UnimplementedAdminServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedAdminServer) Restart(context.Context, *RestartRequest) (*RestartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restart not implemented")
+}
+func (UnimplementedAdminServer) ServiceStatus(context.Context, *ServiceStatusRequest) (*ServiceStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServiceStatus not implemented")
+}
 func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
 
 // UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
@@ -280,6 +325,60 @@ func (x *adminReloadResource3Server) Recv() (*ReloadResourceRequest2, error) {
 	return m, nil
 }
 
+func _Admin_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_Restart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Restart(ctx, req.(*RestartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ServiceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ServiceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ServiceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ServiceStatus(ctx, req.(*ServiceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -291,6 +390,18 @@ var Admin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReloadResource",
 			Handler:    _Admin_ReloadResource_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Admin_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _Admin_Restart_Handler,
+		},
+		{
+			MethodName: "ServiceStatus",
+			Handler:    _Admin_ServiceStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -11,9 +11,7 @@ import (
 	gira "github.com/Lyndon-Zhang/gira"
 	errors "github.com/Lyndon-Zhang/gira/errors"
 	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
 	metadata "google.golang.org/grpc/metadata"
-	status "google.golang.org/grpc/status"
 	sync "sync"
 )
 
@@ -49,6 +47,7 @@ type adminServerRouterMiddlewareContext struct {
 	err        error
 	method     string
 	c          chan struct{}
+	done       bool
 }
 
 func (m *adminServerRouterMiddlewareContext) Handler() AdminServer {
@@ -61,6 +60,7 @@ func (m *adminServerRouterMiddlewareContext) Next() {
 		}
 	}()
 	m.out, m.err = m.invoke()
+	m.done = true
 	if m.c != nil {
 		m.c <- struct{}{}
 	}
@@ -149,13 +149,250 @@ func (svr *adminServerRouter) ReloadResource(ctx context.Context, in *ReloadReso
 	}
 }
 func (svr *adminServerRouter) ReloadResource1(s Admin_ReloadResource1Server) error {
-	return status.Errorf(codes.Unimplemented, "method ReloadResource1 not implemented")
+	ctx := s.Context()
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		return errors.ErrServerRouterMetaNotFound
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.ReloadResource1(s)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_ReloadResource1_FullMethodName,
+				method:     "ReloadResource1",
+				ctx:        ctx,
+				in:         nil,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return nil, handler.ReloadResource1(s)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return err
+			}
+			if !r.done && r.err == nil {
+				return errors.ErrServerRouterHandlerNotImplement
+			}
+			return r.err
+		}
+	}
 }
 func (svr *adminServerRouter) ReloadResource2(in *ReloadResourceRequest2, s Admin_ReloadResource2Server) error {
-	return status.Errorf(codes.Unimplemented, "method ReloadResource2 not implemented")
+	ctx := s.Context()
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		return errors.ErrServerRouterMetaNotFound
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.ReloadResource2(in, s)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_ReloadResource2_FullMethodName,
+				method:     "ReloadResource2",
+				ctx:        ctx,
+				in:         in,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return nil, handler.ReloadResource2(in, s)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return err
+			}
+			if !r.done && r.err == nil {
+				return errors.ErrServerRouterHandlerNotImplement
+			}
+			return r.err
+		}
+	}
 }
 func (svr *adminServerRouter) ReloadResource3(s Admin_ReloadResource3Server) error {
-	return status.Errorf(codes.Unimplemented, "method ReloadResource3 not implemented")
+	ctx := s.Context()
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		return errors.ErrServerRouterMetaNotFound
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.ReloadResource3(s)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_ReloadResource3_FullMethodName,
+				method:     "ReloadResource3",
+				ctx:        ctx,
+				in:         nil,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return nil, handler.ReloadResource3(s)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return err
+			}
+			if !r.done && r.err == nil {
+				return errors.ErrServerRouterHandlerNotImplement
+			}
+			return r.err
+		}
+	}
+}
+func (svr *adminServerRouter) ReloadConfig(ctx context.Context, in *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		if kv, ok = metadata.FromOutgoingContext(ctx); !ok {
+			return nil, errors.ErrServerRouterMetaNotFound
+		}
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return nil, errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return nil, errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.ReloadConfig(ctx, in)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_ReloadConfig_FullMethodName,
+				method:     "ReloadConfig",
+				ctx:        ctx,
+				in:         in,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return handler.ReloadConfig(ctx, in)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return nil, err
+			}
+			if r.out == nil && r.err == nil {
+				return nil, errors.ErrServerRouterHandlerNotImplement
+			} else if r.out == nil && r.err != nil {
+				return nil, r.err
+			} else {
+				return r.out.(*ReloadConfigResponse), r.err
+			}
+		}
+	}
+}
+func (svr *adminServerRouter) Restart(ctx context.Context, in *RestartRequest) (*RestartResponse, error) {
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		if kv, ok = metadata.FromOutgoingContext(ctx); !ok {
+			return nil, errors.ErrServerRouterMetaNotFound
+		}
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return nil, errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return nil, errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.Restart(ctx, in)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_Restart_FullMethodName,
+				method:     "Restart",
+				ctx:        ctx,
+				in:         in,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return handler.Restart(ctx, in)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return nil, err
+			}
+			if r.out == nil && r.err == nil {
+				return nil, errors.ErrServerRouterHandlerNotImplement
+			} else if r.out == nil && r.err != nil {
+				return nil, r.err
+			} else {
+				return r.out.(*RestartResponse), r.err
+			}
+		}
+	}
+}
+func (svr *adminServerRouter) ServiceStatus(ctx context.Context, in *ServiceStatusRequest) (*ServiceStatusResponse, error) {
+	var kv metadata.MD
+	var ok bool
+	if kv, ok = metadata.FromIncomingContext(ctx); !ok {
+		if kv, ok = metadata.FromOutgoingContext(ctx); !ok {
+			return nil, errors.ErrServerRouterMetaNotFound
+		}
+	}
+	if keys, ok := kv[gira.GRPC_PATH_KEY]; !ok {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if len(keys) <= 0 {
+		return nil, errors.ErrServerRouterKeyNotFound
+	} else if v, ok := svr.handlers.Load(keys[0]); !ok {
+		return nil, errors.ErrServerRouterHandlerNotRegist
+	} else if handler, ok := v.(AdminServer); !ok {
+		return nil, errors.ErrServerRouterHandlerNotImplement
+	} else {
+		if middleware := svr.middleware; middleware == nil {
+			return handler.ServiceStatus(ctx, in)
+		} else {
+			r := &adminServerRouterMiddlewareContext{
+				fullMethod: Admin_ServiceStatus_FullMethodName,
+				method:     "ServiceStatus",
+				ctx:        ctx,
+				in:         in,
+				handler:    handler,
+				invoke: func() (resp interface{}, err error) {
+					return handler.ServiceStatus(ctx, in)
+				},
+			}
+			if err := middleware.AdminServerRouterMiddlewareInvoke(r); err != nil {
+				return nil, err
+			}
+			if r.out == nil && r.err == nil {
+				return nil, errors.ErrServerRouterHandlerNotImplement
+			} else if r.out == nil && r.err != nil {
+				return nil, r.err
+			} else {
+				return r.out.(*ServiceStatusResponse), r.err
+			}
+		}
+	}
 }
 
 func RegisterAdminServerAsRouter(s grpc.ServiceRegistrar, handler AdminServerRouterHandler) AdminServerRouter {
@@ -255,6 +492,60 @@ func (x *adminReloadResource3ServerRouter) Recv() (*ReloadResourceRequest2, erro
 	return m, nil
 }
 
+func _Admin_ReloadConfig_RouterHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Restart_RouterHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_Restart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Restart(ctx, req.(*RestartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ServiceStatus_RouterHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ServiceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ServiceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ServiceStatus(ctx, req.(*ServiceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Admin_ServiceRouterDesc is the grpc.ServiceDesc for Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -266,6 +557,18 @@ var Admin_ServiceRouterDesc = grpc.ServiceDesc{
 			MethodName: "ReloadResource",
 			Handler:    _Admin_ReloadResource_RouterHandler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Admin_ReloadConfig_RouterHandler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _Admin_Restart_RouterHandler,
+		},
+		{
+			MethodName: "ServiceStatus",
+			Handler:    _Admin_ServiceStatus_RouterHandler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
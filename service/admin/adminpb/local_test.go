@@ -0,0 +1,114 @@
+package adminpb
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type test_runtime struct {
+	gira.Runtime
+	peer     *gira.Peer
+	registry *test_registry
+	conns    *test_conn_manager
+}
+
+func (r *test_runtime) GetAppFullName() string                   { return r.peer.FullName }
+func (r *test_runtime) GetConfig() *gira.Config                  { return &gira.Config{} }
+func (r *test_runtime) GetRegistry() gira.Registry               { return r.registry }
+func (r *test_runtime) GetGrpcConnManager() gira.GrpcConnManager { return r.conns }
+
+type test_registry struct {
+	gira.Registry
+	peer *gira.Peer
+}
+
+func (r *test_registry) WhereIsPeer(appFullName string) (*gira.Peer, error) {
+	if appFullName != r.peer.FullName {
+		return nil, errors.ErrPeerNotFound
+	}
+	return r.peer, nil
+}
+
+type test_conn_manager struct {
+	gira.GrpcConnManager
+	conn *grpc.ClientConn
+}
+
+func (m *test_conn_manager) GetConn(address string) (*grpc.ClientConn, error) {
+	return m.conn, nil
+}
+
+type test_admin_server struct {
+	UnimplementedAdminServer
+}
+
+func (s *test_admin_server) ReloadResource2(in *ReloadResourceRequest2, stream Admin_ReloadResource2Server) error {
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(&ReloadResourceResponse2{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recvAll(t *testing.T, stream Admin_ReloadResource2Client) int {
+	count := 0
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			return count
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+}
+
+// 流通过本节点的地址建立
+func TestLocalStream(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	RegisterAdminServer(server, &test_admin_server{})
+	go server.Serve(ln)
+	defer server.Stop()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	peer := &gira.Peer{FullName: "admin_qq_dev_1", Address: ln.Addr().String()}
+	gira.OnApplicationCreate(&test_runtime{
+		peer:     peer,
+		registry: &test_registry{peer: peer},
+		conns:    &test_conn_manager{conn: conn},
+	})
+	defer gira.OnApplicationCreate(nil)
+
+	stream, err := DefaultAdminClients.Unicast().Local().ReloadResource2(context.Background(), &ReloadResourceRequest2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := recvAll(t, stream); count != 2 {
+		t.Fatalf("expected 2 responses, got %d", count)
+	}
+
+	result, err := DefaultAdminClients.Broadcast().Local().ReloadResource2(context.Background(), &ReloadResourceRequest2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PeerCount() != 1 || result.SuccessCount() != 1 || result.SuccessPeer(0) != peer {
+		t.Fatalf("unexpected result, success %d, error %v", result.SuccessCount(), result.Error())
+	}
+	if count := recvAll(t, result.Response(0)); count != 2 {
+		t.Fatalf("expected 2 responses, got %d", count)
+	}
+}
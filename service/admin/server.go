@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 
+	"github.com/Lyndon-Zhang/gira/errors"
 	"github.com/Lyndon-Zhang/gira/facade"
 	"github.com/Lyndon-Zhang/gira/options/service_options"
 	"github.com/Lyndon-Zhang/gira/service/admin/adminpb"
@@ -25,6 +26,48 @@ func (self *admin_server) ReloadResource(context.Context, *adminpb.ReloadResourc
 	return resp, nil
 }
 
+// 有需要重启的修改时, 只返回拒绝的路径
+func (self *admin_server) ReloadConfig(context.Context, *adminpb.ReloadConfigRequest) (*adminpb.ReloadConfigResponse, error) {
+	resp := &adminpb.ReloadConfigResponse{}
+	report, err := facade.ReloadConfig()
+	if err != nil && err != errors.ErrConfigRestartRequired {
+		return nil, err
+	}
+	resp.Changed = report.Changed
+	resp.Rejected = report.Rejected
+	return resp, nil
+}
+
+// 新进程就绪后返回, 之后当前进程等待会话结束后退出
+func (self *admin_server) Restart(context.Context, *adminpb.RestartRequest) (*adminpb.RestartResponse, error) {
+	resp := &adminpb.RestartResponse{}
+	if pid, err := facade.Restart(); err != nil {
+		return nil, err
+	} else {
+		resp.Pid = int32(pid)
+	}
+	return resp, nil
+}
+
+// 服务容器中全部服务的状态
+func (self *admin_server) ServiceStatus(context.Context, *adminpb.ServiceStatusRequest) (*adminpb.ServiceStatusResponse, error) {
+	resp := &adminpb.ServiceStatusResponse{}
+	states, err := facade.ServiceStates()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range states {
+		resp.Services = append(resp.Services, &adminpb.ServiceState{
+			Name:         v.Name,
+			State:        v.State,
+			RestartCount: int32(v.RestartCount),
+			LastError:    v.LastError,
+			StartTime:    v.StartTime,
+		})
+	}
+	return resp, nil
+}
+
 func NewService() *AdminService {
 	return &AdminService{
 		adminServer: &admin_server{},
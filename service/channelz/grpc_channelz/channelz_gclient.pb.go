@@ -33,11 +33,12 @@ import (
 	gira "github.com/Lyndon-Zhang/gira"
 	errors "github.com/Lyndon-Zhang/gira/errors"
 	facade "github.com/Lyndon-Zhang/gira/facade"
+	policy "github.com/Lyndon-Zhang/gira/grpc/policy"
+	scatter "github.com/Lyndon-Zhang/gira/grpc/scatter"
 	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
 	grpc "google.golang.org/grpc"
 	grpc_channelz_v1 "google.golang.org/grpc/channelz/grpc_channelz_v1"
 	metadata "google.golang.org/grpc/metadata"
-	sync "sync"
 	time "time"
 )
 
@@ -417,22 +418,44 @@ type ChannelzClients interface {
 type ChannelzClientsMulticast interface {
 	WhereRegex(regex string) ChannelzClientsMulticast
 	WherePrefix(prefix bool) ChannelzClientsMulticast
+	WhereZone(zone string) ChannelzClientsMulticast
+	WhereAllZone() ChannelzClientsMulticast
 	Local() ChannelzClientsMulticast
+	WithTimeout(timeout time.Duration) ChannelzClientsMulticast
+	WithRetry(retry int, backoff time.Duration) ChannelzClientsMulticast
+	WithHedge(delay time.Duration) ChannelzClientsMulticast
+	WithBreaker(enabled bool) ChannelzClientsMulticast
+	WithConcurrency(concurrency int) ChannelzClientsMulticast
+	WithQuorum(quorum int) ChannelzClientsMulticast
 	// Gets all root channels (i.e. channels the application has directly
 	// created). This does not include subchannels nor non-top level channels.
 	GetTopChannels(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) (*GetTopChannelsResponse_MulticastResult, error)
+	GetTopChannelsGather(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetTopChannelsResponse]), opts ...grpc.CallOption) error
+	GetTopChannelsGatherChan(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetTopChannelsResponse]
 	// Gets all servers that exist in the process.
 	GetServers(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) (*GetServersResponse_MulticastResult, error)
+	GetServersGather(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServersResponse]), opts ...grpc.CallOption) error
+	GetServersGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServersResponse]
 	// Returns a single Server, or else a NOT_FOUND code.
 	GetServer(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) (*GetServerResponse_MulticastResult, error)
+	GetServerGather(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServerResponse]), opts ...grpc.CallOption) error
+	GetServerGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServerResponse]
 	// Gets all server sockets that exist in the process.
 	GetServerSockets(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) (*GetServerSocketsResponse_MulticastResult, error)
+	GetServerSocketsGather(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServerSocketsResponse]), opts ...grpc.CallOption) error
+	GetServerSocketsGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServerSocketsResponse]
 	// Returns a single Channel, or else a NOT_FOUND code.
 	GetChannel(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) (*GetChannelResponse_MulticastResult, error)
+	GetChannelGather(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetChannelResponse]), opts ...grpc.CallOption) error
+	GetChannelGatherChan(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetChannelResponse]
 	// Returns a single Subchannel, or else a NOT_FOUND code.
 	GetSubchannel(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) (*GetSubchannelResponse_MulticastResult, error)
+	GetSubchannelGather(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetSubchannelResponse]), opts ...grpc.CallOption) error
+	GetSubchannelGatherChan(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetSubchannelResponse]
 	// Returns a single Socket or else a NOT_FOUND code.
 	GetSocket(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) (*GetSocketResponse_MulticastResult, error)
+	GetSocketGather(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetSocketResponse]), opts ...grpc.CallOption) error
+	GetSocketGatherChan(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetSocketResponse]
 }
 
 type ChannelzClientsUnicast interface {
@@ -441,7 +464,14 @@ type ChannelzClientsUnicast interface {
 	WherePeerFullName(appFullName string) ChannelzClientsUnicast
 	WhereAddress(address string) ChannelzClientsUnicast
 	WhereUser(userId string) ChannelzClientsUnicast
+	WhereKey(key string) ChannelzClientsUnicast
+	WhereZone(zone string) ChannelzClientsUnicast
+	WhereAllZone() ChannelzClientsUnicast
 	Local() ChannelzClientsUnicast
+	WithTimeout(timeout time.Duration) ChannelzClientsUnicast
+	WithRetry(retry int, backoff time.Duration) ChannelzClientsUnicast
+	WithHedge(delay time.Duration) ChannelzClientsUnicast
+	WithBreaker(enabled bool) ChannelzClientsUnicast
 
 	// Gets all root channels (i.e. channels the application has directly
 	// created). This does not include subchannels nor non-top level channels.
@@ -461,57 +491,23 @@ type ChannelzClientsUnicast interface {
 }
 
 type channelzClients struct {
-	mu          sync.Mutex
-	clientPool  map[string]*sync.Pool
 	serviceName string
 }
 
 func NewChannelzClients() ChannelzClients {
 	return &channelzClients{
 		serviceName: ChannelzServerName,
-		clientPool:  make(map[string]*sync.Pool, 0),
 	}
 }
 
 var DefaultChannelzClients = NewChannelzClients()
 
 func (c *channelzClients) getClient(address string) (grpc_channelz_v1.ChannelzClient, error) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; !ok {
-		pool = &sync.Pool{
-			New: func() any {
-				conn, err := grpc.Dial(address, grpc.WithInsecure())
-				if err != nil {
-					return err
-				}
-				client := grpc_channelz_v1.NewChannelzClient(conn)
-				return client
-			},
-		}
-		c.clientPool[address] = pool
-		c.mu.Unlock()
-	} else {
-		c.mu.Unlock()
-	}
-	if v := pool.Get(); v == nil {
-		return nil, errors.ErrGrpcClientPoolNil
-	} else if err, ok := v.(error); ok {
+	conn, err := facade.GetGrpcConn(address)
+	if err != nil {
 		return nil, err
-	} else {
-		return v.(grpc_channelz_v1.ChannelzClient), nil
-	}
-}
-
-func (c *channelzClients) putClient(address string, client grpc_channelz_v1.ChannelzClient) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; ok {
-		pool.Put(client)
 	}
-	c.mu.Unlock()
+	return grpc_channelz_v1.NewChannelzClient(conn), nil
 }
 
 func (c *channelzClients) WithServiceName(serviceName string) ChannelzClients {
@@ -522,7 +518,8 @@ func (c *channelzClients) WithServiceName(serviceName string) ChannelzClients {
 func (c *channelzClients) Unicast() ChannelzClientsUnicast {
 	headers := make(map[string]string)
 	u := &channelzClientsUnicast{
-		timeout: 5,
+		timeout: 5 * time.Second,
+		breaker: true,
 		headers: metadata.New(headers),
 		client:  c,
 	}
@@ -532,7 +529,8 @@ func (c *channelzClients) Unicast() ChannelzClientsUnicast {
 func (c *channelzClients) Multicast(count int) ChannelzClientsMulticast {
 	headers := make(map[string]string)
 	u := &channelzClientsMulticast{
-		timeout:     5,
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       count,
 		headers:     metadata.New(headers),
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
@@ -543,6 +541,8 @@ func (c *channelzClients) Multicast(count int) ChannelzClientsMulticast {
 
 func (c *channelzClients) Broadcast() ChannelzClientsMulticast {
 	u := &channelzClientsMulticast{
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       -1,
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
 		client:      c,
@@ -555,7 +555,6 @@ func (c *channelzClients) GetTopChannels(ctx context.Context, address string, in
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetTopChannels(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -568,7 +567,6 @@ func (c *channelzClients) GetServers(ctx context.Context, address string, in *gr
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetServers(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -581,7 +579,6 @@ func (c *channelzClients) GetServer(ctx context.Context, address string, in *grp
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetServer(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -594,7 +591,6 @@ func (c *channelzClients) GetServerSockets(ctx context.Context, address string,
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetServerSockets(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -607,7 +603,6 @@ func (c *channelzClients) GetChannel(ctx context.Context, address string, in *gr
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetChannel(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -620,7 +615,6 @@ func (c *channelzClients) GetSubchannel(ctx context.Context, address string, in
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetSubchannel(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -633,7 +627,6 @@ func (c *channelzClients) GetSocket(ctx context.Context, address string, in *grp
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.GetSocket(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -642,12 +635,19 @@ func (c *channelzClients) GetSocket(ctx context.Context, address string, in *grp
 }
 
 type channelzClientsUnicast struct {
-	timeout      int64
+	timeout      time.Duration
+	retry        int
+	backoff      time.Duration
+	hedge        time.Duration
+	breaker      bool
 	peer         *gira.Peer
 	peerFullName string
 	serviceName  string
 	address      string
 	userId       string
+	key          string
+	zone         string
+	allZone      bool
 	local        bool
 	client       *channelzClients
 	headers      metadata.MD
@@ -684,9 +684,73 @@ func (c *channelzClientsUnicast) WhereUser(userId string) ChannelzClientsUnicast
 	return c
 }
 
+// 根据key一致性hash选择服务, 服务名为Where设置的目录，默认为服务目录
+func (c *channelzClientsUnicast) WhereKey(key string) ChannelzClientsUnicast {
+	c.key = key
+	return c
+}
+
+// 在指定的区查找服务, 默认只查找本区
+func (c *channelzClientsUnicast) WhereZone(zone string) ChannelzClientsUnicast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *channelzClientsUnicast) WhereAllZone() ChannelzClientsUnicast {
+	c.allZone = true
+	return c
+}
+
+// 调用超时, 包括重试的时间
+func (c *channelzClientsUnicast) WithTimeout(timeout time.Duration) ChannelzClientsUnicast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *channelzClientsUnicast) WithRetry(retry int, backoff time.Duration) ChannelzClientsUnicast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *channelzClientsUnicast) WithHedge(delay time.Duration) ChannelzClientsUnicast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *channelzClientsUnicast) WithBreaker(enabled bool) ChannelzClientsUnicast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *channelzClientsUnicast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
+func (c *channelzClientsUnicast) whereOpts(opts ...service_options.WhereOption) []service_options.WhereOption {
+	if len(c.zone) > 0 {
+		opts = append(opts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		opts = append(opts, service_options.WithWhereAllZoneOption())
+	}
+	return opts
+}
+
 func (c *channelzClientsUnicast) GetTopChannels(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -701,9 +765,12 @@ func (c *channelzClientsUnicast) GetTopChannels(ctx context.Context, in *grpc_ch
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -713,25 +780,47 @@ func (c *channelzClientsUnicast) GetTopChannels(ctx context.Context, in *grpc_ch
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -739,23 +828,20 @@ func (c *channelzClientsUnicast) GetTopChannels(ctx context.Context, in *grpc_ch
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetTopChannels(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
+			return client.GetTopChannels(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetServers(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetServersResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -770,9 +856,12 @@ func (c *channelzClientsUnicast) GetServers(ctx context.Context, in *grpc_channe
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -782,25 +871,47 @@ func (c *channelzClientsUnicast) GetServers(ctx context.Context, in *grpc_channe
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -808,23 +919,20 @@ func (c *channelzClientsUnicast) GetServers(ctx context.Context, in *grpc_channe
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetServers(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServersResponse, error) {
+			return client.GetServers(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetServer(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetServerResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -839,9 +947,12 @@ func (c *channelzClientsUnicast) GetServer(ctx context.Context, in *grpc_channel
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -851,25 +962,47 @@ func (c *channelzClientsUnicast) GetServer(ctx context.Context, in *grpc_channel
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -877,23 +1010,20 @@ func (c *channelzClientsUnicast) GetServer(ctx context.Context, in *grpc_channel
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetServer(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerResponse, error) {
+			return client.GetServer(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetServerSockets(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -908,9 +1038,12 @@ func (c *channelzClientsUnicast) GetServerSockets(ctx context.Context, in *grpc_
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -920,25 +1053,47 @@ func (c *channelzClientsUnicast) GetServerSockets(ctx context.Context, in *grpc_
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -946,23 +1101,20 @@ func (c *channelzClientsUnicast) GetServerSockets(ctx context.Context, in *grpc_
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetServerSockets(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
+			return client.GetServerSockets(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetChannel(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetChannelResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -977,9 +1129,12 @@ func (c *channelzClientsUnicast) GetChannel(ctx context.Context, in *grpc_channe
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -989,25 +1144,47 @@ func (c *channelzClientsUnicast) GetChannel(ctx context.Context, in *grpc_channe
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1015,23 +1192,20 @@ func (c *channelzClientsUnicast) GetChannel(ctx context.Context, in *grpc_channe
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetChannel(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetChannelResponse, error) {
+			return client.GetChannel(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetSubchannel(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetSubchannelResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1046,9 +1220,12 @@ func (c *channelzClientsUnicast) GetSubchannel(ctx context.Context, in *grpc_cha
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1058,25 +1235,47 @@ func (c *channelzClientsUnicast) GetSubchannel(ctx context.Context, in *grpc_cha
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1084,23 +1283,20 @@ func (c *channelzClientsUnicast) GetSubchannel(ctx context.Context, in *grpc_cha
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetSubchannel(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSubchannelResponse, error) {
+			return client.GetSubchannel(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *channelzClientsUnicast) GetSocket(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) (*grpc_channelz_v1.GetSocketResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1115,9 +1311,12 @@ func (c *channelzClientsUnicast) GetSocket(ctx context.Context, in *grpc_channel
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -1127,25 +1326,47 @@ func (c *channelzClientsUnicast) GetSocket(ctx context.Context, in *grpc_channel
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -1153,27 +1374,32 @@ func (c *channelzClientsUnicast) GetSocket(ctx context.Context, in *grpc_channel
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.GetSocket(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(false), peerName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSocketResponse, error) {
+			return client.GetSocket(ctx, in, opts...)
+		})
 	}
 
 }
 
 type channelzClientsMulticast struct {
-	timeout     int64
+	timeout     time.Duration
+	retry       int
+	backoff     time.Duration
+	hedge       time.Duration
+	breaker     bool
+	concurrency int
+	quorum      int
 	count       int
 	serviceName string
 	regex       string
 	prefix      bool
+	zone        string
+	allZone     bool
 	local       bool
 	client      *channelzClients
 	headers     metadata.MD
@@ -1184,6 +1410,42 @@ func (c *channelzClientsMulticast) Local() ChannelzClientsMulticast {
 	return c
 }
 
+// 调用超时, 包括重试的时间
+func (c *channelzClientsMulticast) WithTimeout(timeout time.Duration) ChannelzClientsMulticast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *channelzClientsMulticast) WithRetry(retry int, backoff time.Duration) ChannelzClientsMulticast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *channelzClientsMulticast) WithHedge(delay time.Duration) ChannelzClientsMulticast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *channelzClientsMulticast) WithBreaker(enabled bool) ChannelzClientsMulticast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *channelzClientsMulticast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
 func (c *channelzClientsMulticast) WhereRegex(regex string) ChannelzClientsMulticast {
 	c.regex = regex
 	return c
@@ -1194,13 +1456,68 @@ func (c *channelzClientsMulticast) WherePrefix(prefix bool) ChannelzClientsMulti
 	return c
 }
 
+// 在指定的区查找服务, 默认只查找本区
+func (c *channelzClientsMulticast) WhereZone(zone string) ChannelzClientsMulticast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *channelzClientsMulticast) WhereAllZone() ChannelzClientsMulticast {
+	c.allZone = true
+	return c
+}
+
+// 最多同时调用多少个节点, 只对Gather生效, 0表示不限制
+func (c *channelzClientsMulticast) WithConcurrency(concurrency int) ChannelzClientsMulticast {
+	c.concurrency = concurrency
+	return c
+}
+
+// 成功多少个节点后返回, 只对Gather生效, 0表示等待全部节点
+func (c *channelzClientsMulticast) WithQuorum(quorum int) ChannelzClientsMulticast {
+	c.quorum = quorum
+	return c
+}
+
+func (c *channelzClientsMulticast) gatherOptions() scatter.Options {
+	return scatter.Options{
+		Concurrency: c.concurrency,
+		Quorum:      c.quorum,
+	}
+}
+
+// 查找要调用的节点, Gather不支持Local
+func (c *channelzClientsMulticast) wherePeers() ([]*gira.Peer, error) {
+	var whereOpts []service_options.WhereOption
+	whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+	if c.count > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+	}
+	serviceName := c.serviceName
+	if len(c.regex) > 0 {
+		serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+		whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+	}
+	if c.prefix {
+		whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+	}
+	if len(c.zone) > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+	}
+	return facade.WhereIsServiceName(serviceName, whereOpts...)
+}
+
 func (c *channelzClientsMulticast) GetTopChannels(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) (*GetTopChannelsResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetTopChannelsResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1230,6 +1547,12 @@ func (c *channelzClientsMulticast) GetTopChannels(ctx context.Context, in *grpc_
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1245,18 +1568,18 @@ func (c *channelzClientsMulticast) GetTopChannels(ctx context.Context, in *grpc_
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetTopChannels(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
+				return client.GetTopChannels(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1264,13 +1587,50 @@ func (c *channelzClientsMulticast) GetTopChannels(ctx context.Context, in *grpc_
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetTopChannelsGather(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetTopChannelsResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getTopChannelsCall(in, opts...), f)
+}
+
+// 和GetTopChannelsGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetTopChannelsGatherChan(ctx context.Context, in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetTopChannelsResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetTopChannelsResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getTopChannelsCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getTopChannelsCall(in *grpc_channelz_v1.GetTopChannelsRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetTopChannelsResponse, error) {
+			return client.GetTopChannels(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetServers(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) (*GetServersResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetServersResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1300,6 +1660,12 @@ func (c *channelzClientsMulticast) GetServers(ctx context.Context, in *grpc_chan
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1315,18 +1681,18 @@ func (c *channelzClientsMulticast) GetServers(ctx context.Context, in *grpc_chan
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetServers(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServersResponse, error) {
+				return client.GetServers(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1334,13 +1700,50 @@ func (c *channelzClientsMulticast) GetServers(ctx context.Context, in *grpc_chan
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetServersGather(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServersResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getServersCall(in, opts...), f)
+}
+
+// 和GetServersGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetServersGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServersResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetServersResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getServersCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getServersCall(in *grpc_channelz_v1.GetServersRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServersResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServersResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServersResponse, error) {
+			return client.GetServers(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetServer(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) (*GetServerResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetServerResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1370,6 +1773,12 @@ func (c *channelzClientsMulticast) GetServer(ctx context.Context, in *grpc_chann
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1385,18 +1794,18 @@ func (c *channelzClientsMulticast) GetServer(ctx context.Context, in *grpc_chann
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetServer(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerResponse, error) {
+				return client.GetServer(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1404,13 +1813,50 @@ func (c *channelzClientsMulticast) GetServer(ctx context.Context, in *grpc_chann
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetServerGather(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServerResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getServerCall(in, opts...), f)
+}
+
+// 和GetServerGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetServerGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServerResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetServerResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getServerCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getServerCall(in *grpc_channelz_v1.GetServerRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServerResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServerResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerResponse, error) {
+			return client.GetServer(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetServerSockets(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) (*GetServerSocketsResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetServerSocketsResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1440,6 +1886,12 @@ func (c *channelzClientsMulticast) GetServerSockets(ctx context.Context, in *grp
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1455,18 +1907,18 @@ func (c *channelzClientsMulticast) GetServerSockets(ctx context.Context, in *grp
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetServerSockets(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
+				return client.GetServerSockets(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1474,13 +1926,50 @@ func (c *channelzClientsMulticast) GetServerSockets(ctx context.Context, in *grp
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetServerSocketsGather(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetServerSocketsResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getServerSocketsCall(in, opts...), f)
+}
+
+// 和GetServerSocketsGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetServerSocketsGatherChan(ctx context.Context, in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetServerSocketsResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetServerSocketsResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getServerSocketsCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getServerSocketsCall(in *grpc_channelz_v1.GetServerSocketsRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetServerSocketsResponse, error) {
+			return client.GetServerSockets(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetChannel(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) (*GetChannelResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetChannelResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1510,6 +1999,12 @@ func (c *channelzClientsMulticast) GetChannel(ctx context.Context, in *grpc_chan
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1525,18 +2020,18 @@ func (c *channelzClientsMulticast) GetChannel(ctx context.Context, in *grpc_chan
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetChannel(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetChannelResponse, error) {
+				return client.GetChannel(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1544,13 +2039,50 @@ func (c *channelzClientsMulticast) GetChannel(ctx context.Context, in *grpc_chan
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetChannelGather(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetChannelResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getChannelCall(in, opts...), f)
+}
+
+// 和GetChannelGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetChannelGatherChan(ctx context.Context, in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetChannelResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetChannelResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getChannelCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getChannelCall(in *grpc_channelz_v1.GetChannelRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetChannelResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetChannelResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetChannelResponse, error) {
+			return client.GetChannel(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetSubchannel(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) (*GetSubchannelResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetSubchannelResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1580,6 +2112,12 @@ func (c *channelzClientsMulticast) GetSubchannel(ctx context.Context, in *grpc_c
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1595,18 +2133,18 @@ func (c *channelzClientsMulticast) GetSubchannel(ctx context.Context, in *grpc_c
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetSubchannel(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSubchannelResponse, error) {
+				return client.GetSubchannel(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1614,13 +2152,50 @@ func (c *channelzClientsMulticast) GetSubchannel(ctx context.Context, in *grpc_c
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetSubchannelGather(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetSubchannelResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getSubchannelCall(in, opts...), f)
+}
+
+// 和GetSubchannelGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetSubchannelGatherChan(ctx context.Context, in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetSubchannelResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetSubchannelResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getSubchannelCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getSubchannelCall(in *grpc_channelz_v1.GetSubchannelRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetSubchannelResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetSubchannelResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSubchannelResponse, error) {
+			return client.GetSubchannel(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *channelzClientsMulticast) GetSocket(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) (*GetSocketResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(grpc_channelz_v1.ChannelzServer); ok {
 			result := &GetSocketResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -1650,6 +2225,12 @@ func (c *channelzClientsMulticast) GetSocket(ctx context.Context, in *grpc_chann
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -1665,18 +2246,18 @@ func (c *channelzClientsMulticast) GetSocket(ctx context.Context, in *grpc_chann
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.GetSocket(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSocketResponse, error) {
+				return client.GetSocket(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -1684,3 +2265,39 @@ func (c *channelzClientsMulticast) GetSocket(ctx context.Context, in *grpc_chann
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *channelzClientsMulticast) GetSocketGather(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, f func(result *scatter.Result[*grpc_channelz_v1.GetSocketResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.getSocketCall(in, opts...), f)
+}
+
+// 和GetSocketGather一样, 结果通过channel返回
+func (c *channelzClientsMulticast) GetSocketGatherChan(ctx context.Context, in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) *scatter.Stream[*grpc_channelz_v1.GetSocketResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*grpc_channelz_v1.GetSocketResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.getSocketCall(in, opts...))
+}
+
+func (c *channelzClientsMulticast) getSocketCall(in *grpc_channelz_v1.GetSocketRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetSocketResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*grpc_channelz_v1.GetSocketResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(false), peer.FullName, address, func(ctx context.Context) (*grpc_channelz_v1.GetSocketResponse, error) {
+			return client.GetSocket(ctx, in, opts...)
+		})
+	}
+}
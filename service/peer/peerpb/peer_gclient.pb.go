@@ -12,10 +12,11 @@ import (
 	gira "github.com/Lyndon-Zhang/gira"
 	errors "github.com/Lyndon-Zhang/gira/errors"
 	facade "github.com/Lyndon-Zhang/gira/facade"
+	policy "github.com/Lyndon-Zhang/gira/grpc/policy"
+	scatter "github.com/Lyndon-Zhang/gira/grpc/scatter"
 	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
 	grpc "google.golang.org/grpc"
 	metadata "google.golang.org/grpc/metadata"
-	sync "sync"
 	time "time"
 )
 
@@ -142,9 +143,21 @@ type PeerClients interface {
 type PeerClientsMulticast interface {
 	WhereRegex(regex string) PeerClientsMulticast
 	WherePrefix(prefix bool) PeerClientsMulticast
+	WhereZone(zone string) PeerClientsMulticast
+	WhereAllZone() PeerClientsMulticast
 	Local() PeerClientsMulticast
+	WithTimeout(timeout time.Duration) PeerClientsMulticast
+	WithRetry(retry int, backoff time.Duration) PeerClientsMulticast
+	WithHedge(delay time.Duration) PeerClientsMulticast
+	WithBreaker(enabled bool) PeerClientsMulticast
+	WithConcurrency(concurrency int) PeerClientsMulticast
+	WithQuorum(quorum int) PeerClientsMulticast
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse_MulticastResult, error)
+	HealthCheckGather(ctx context.Context, in *HealthCheckRequest, f func(result *scatter.Result[*HealthCheckResponse]), opts ...grpc.CallOption) error
+	HealthCheckGatherChan(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) *scatter.Stream[*HealthCheckResponse]
 	MemStats(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) (*MemStatsResponse_MulticastResult, error)
+	MemStatsGather(ctx context.Context, in *MemStatsRequest, f func(result *scatter.Result[*MemStatsResponse]), opts ...grpc.CallOption) error
+	MemStatsGatherChan(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) *scatter.Stream[*MemStatsResponse]
 }
 
 type PeerClientsUnicast interface {
@@ -153,64 +166,37 @@ type PeerClientsUnicast interface {
 	WherePeerFullName(appFullName string) PeerClientsUnicast
 	WhereAddress(address string) PeerClientsUnicast
 	WhereUser(userId string) PeerClientsUnicast
+	WhereKey(key string) PeerClientsUnicast
+	WhereZone(zone string) PeerClientsUnicast
+	WhereAllZone() PeerClientsUnicast
 	Local() PeerClientsUnicast
+	WithTimeout(timeout time.Duration) PeerClientsUnicast
+	WithRetry(retry int, backoff time.Duration) PeerClientsUnicast
+	WithHedge(delay time.Duration) PeerClientsUnicast
+	WithBreaker(enabled bool) PeerClientsUnicast
 
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 	MemStats(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) (*MemStatsResponse, error)
 }
 
 type peerClients struct {
-	mu          sync.Mutex
-	clientPool  map[string]*sync.Pool
 	serviceName string
 }
 
 func NewPeerClients() PeerClients {
 	return &peerClients{
 		serviceName: PeerServerName,
-		clientPool:  make(map[string]*sync.Pool, 0),
 	}
 }
 
 var DefaultPeerClients = NewPeerClients()
 
 func (c *peerClients) getClient(address string) (PeerClient, error) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; !ok {
-		pool = &sync.Pool{
-			New: func() any {
-				conn, err := grpc.Dial(address, grpc.WithInsecure())
-				if err != nil {
-					return err
-				}
-				client := NewPeerClient(conn)
-				return client
-			},
-		}
-		c.clientPool[address] = pool
-		c.mu.Unlock()
-	} else {
-		c.mu.Unlock()
-	}
-	if v := pool.Get(); v == nil {
-		return nil, errors.ErrGrpcClientPoolNil
-	} else if err, ok := v.(error); ok {
+	conn, err := facade.GetGrpcConn(address)
+	if err != nil {
 		return nil, err
-	} else {
-		return v.(PeerClient), nil
 	}
-}
-
-func (c *peerClients) putClient(address string, client PeerClient) {
-	c.mu.Lock()
-	var pool *sync.Pool
-	var ok bool
-	if pool, ok = c.clientPool[address]; ok {
-		pool.Put(client)
-	}
-	c.mu.Unlock()
+	return NewPeerClient(conn), nil
 }
 
 func (c *peerClients) WithServiceName(serviceName string) PeerClients {
@@ -221,7 +207,8 @@ func (c *peerClients) WithServiceName(serviceName string) PeerClients {
 func (c *peerClients) Unicast() PeerClientsUnicast {
 	headers := make(map[string]string)
 	u := &peerClientsUnicast{
-		timeout: 5,
+		timeout: 5 * time.Second,
+		breaker: true,
 		headers: metadata.New(headers),
 		client:  c,
 	}
@@ -231,7 +218,8 @@ func (c *peerClients) Unicast() PeerClientsUnicast {
 func (c *peerClients) Multicast(count int) PeerClientsMulticast {
 	headers := make(map[string]string)
 	u := &peerClientsMulticast{
-		timeout:     5,
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       count,
 		headers:     metadata.New(headers),
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
@@ -242,6 +230,8 @@ func (c *peerClients) Multicast(count int) PeerClientsMulticast {
 
 func (c *peerClients) Broadcast() PeerClientsMulticast {
 	u := &peerClientsMulticast{
+		timeout:     5 * time.Second,
+		breaker:     true,
 		count:       -1,
 		serviceName: fmt.Sprintf("%s/", c.serviceName),
 		client:      c,
@@ -254,7 +244,6 @@ func (c *peerClients) HealthCheck(ctx context.Context, address string, in *Healt
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.HealthCheck(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -267,7 +256,6 @@ func (c *peerClients) MemStats(ctx context.Context, address string, in *MemStats
 	if err != nil {
 		return nil, err
 	}
-	defer c.putClient(address, client)
 	out, err := client.MemStats(ctx, in, opts...)
 	if err != nil {
 		return nil, err
@@ -276,12 +264,19 @@ func (c *peerClients) MemStats(ctx context.Context, address string, in *MemStats
 }
 
 type peerClientsUnicast struct {
-	timeout      int64
+	timeout      time.Duration
+	retry        int
+	backoff      time.Duration
+	hedge        time.Duration
+	breaker      bool
 	peer         *gira.Peer
 	peerFullName string
 	serviceName  string
 	address      string
 	userId       string
+	key          string
+	zone         string
+	allZone      bool
 	local        bool
 	client       *peerClients
 	headers      metadata.MD
@@ -318,9 +313,73 @@ func (c *peerClientsUnicast) WhereUser(userId string) PeerClientsUnicast {
 	return c
 }
 
+// 根据key一致性hash选择服务, 服务名为Where设置的目录，默认为服务目录
+func (c *peerClientsUnicast) WhereKey(key string) PeerClientsUnicast {
+	c.key = key
+	return c
+}
+
+// 在指定的区查找服务, 默认只查找本区
+func (c *peerClientsUnicast) WhereZone(zone string) PeerClientsUnicast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *peerClientsUnicast) WhereAllZone() PeerClientsUnicast {
+	c.allZone = true
+	return c
+}
+
+// 调用超时, 包括重试的时间
+func (c *peerClientsUnicast) WithTimeout(timeout time.Duration) PeerClientsUnicast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *peerClientsUnicast) WithRetry(retry int, backoff time.Duration) PeerClientsUnicast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *peerClientsUnicast) WithHedge(delay time.Duration) PeerClientsUnicast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *peerClientsUnicast) WithBreaker(enabled bool) PeerClientsUnicast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *peerClientsUnicast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
+func (c *peerClientsUnicast) whereOpts(opts ...service_options.WhereOption) []service_options.WhereOption {
+	if len(c.zone) > 0 {
+		opts = append(opts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		opts = append(opts, service_options.WithWhereAllZoneOption())
+	}
+	return opts
+}
+
 func (c *peerClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -335,9 +394,12 @@ func (c *peerClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -347,25 +409,47 @@ func (c *peerClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -373,23 +457,20 @@ func (c *peerClientsUnicast) HealthCheck(ctx context.Context, in *HealthCheckReq
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.HealthCheck(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(true), peerName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+			return client.HealthCheck(ctx, in, opts...)
+		})
 	}
 
 }
 func (c *peerClientsUnicast) MemStats(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) (*MemStatsResponse, error) {
 	if c.local {
-		cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+		cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 		defer cancelFunc()
 		if c.headers.Len() > 0 {
 			cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -404,9 +485,12 @@ func (c *peerClientsUnicast) MemStats(ctx context.Context, in *MemStatsRequest,
 
 	} else {
 		var address string
+		var peerName string
 		if len(c.address) > 0 {
 			address = c.address
+			peerName = c.address
 		} else if len(c.peerFullName) > 0 {
+			peerName = c.peerFullName
 			if peer, err := facade.WhereIsPeer(c.peerFullName); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
@@ -416,25 +500,47 @@ func (c *peerClientsUnicast) MemStats(ctx context.Context, in *MemStatsRequest,
 			}
 		} else if c.peer != nil && facade.IsEnableResolver() {
 			address = c.peer.Url
+			peerName = c.peer.FullName
 		} else if c.peer != nil {
 			address = c.peer.Address
+			peerName = c.peer.FullName
+		} else if len(c.key) > 0 {
+			serviceName := c.client.serviceName
+			if len(c.serviceName) > 0 {
+				serviceName = c.serviceName
+			}
+			if peers, err := facade.WhereIsServiceName(serviceName, c.whereOpts(service_options.WithWhereKeyOption(c.key))...); err != nil {
+				return nil, err
+			} else if len(peers) < 1 {
+				return nil, errors.ErrPeerNotFound
+			} else if facade.IsEnableResolver() {
+				address = peers[0].Url
+				peerName = peers[0].FullName
+			} else {
+				address = peers[0].Address
+				peerName = peers[0].FullName
+			}
 		} else if len(c.serviceName) > 0 {
-			if peers, err := facade.WhereIsServiceName(c.serviceName); err != nil {
+			if peers, err := facade.WhereIsServiceName(c.serviceName, c.whereOpts()...); err != nil {
 				return nil, err
 			} else if len(peers) < 1 {
 				return nil, errors.ErrPeerNotFound
 			} else if facade.IsEnableResolver() {
 				address = peers[0].Url
+				peerName = peers[0].FullName
 			} else {
 				address = peers[0].Address
+				peerName = peers[0].FullName
 			}
 		} else if len(c.userId) > 0 {
 			if peer, err := facade.WhereIsUser(c.userId); err != nil {
 				return nil, err
 			} else if facade.IsEnableResolver() {
 				address = peer.Url
+				peerName = peer.FullName
 			} else {
 				address = peer.Address
+				peerName = peer.FullName
 			}
 		}
 		if len(address) <= 0 {
@@ -442,27 +548,32 @@ func (c *peerClientsUnicast) MemStats(ctx context.Context, in *MemStatsRequest,
 		}
 		client, err := c.client.getClient(address)
 		if err != nil {
-			return nil, err
+			return nil, policy.Wrap(peerName, err)
 		}
-		defer c.client.putClient(address, client)
 		if c.headers.Len() > 0 {
 			ctx = metadata.NewOutgoingContext(ctx, c.headers)
 		}
-		out, err := client.MemStats(ctx, in, opts...)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
+		return policy.Invoke(ctx, c.callPolicy(true), peerName, address, func(ctx context.Context) (*MemStatsResponse, error) {
+			return client.MemStats(ctx, in, opts...)
+		})
 	}
 
 }
 
 type peerClientsMulticast struct {
-	timeout     int64
+	timeout     time.Duration
+	retry       int
+	backoff     time.Duration
+	hedge       time.Duration
+	breaker     bool
+	concurrency int
+	quorum      int
 	count       int
 	serviceName string
 	regex       string
 	prefix      bool
+	zone        string
+	allZone     bool
 	local       bool
 	client      *peerClients
 	headers     metadata.MD
@@ -473,6 +584,42 @@ func (c *peerClientsMulticast) Local() PeerClientsMulticast {
 	return c
 }
 
+// 调用超时, 包括重试的时间
+func (c *peerClientsMulticast) WithTimeout(timeout time.Duration) PeerClientsMulticast {
+	c.timeout = timeout
+	return c
+}
+
+// 失败后重试, 只对幂等的方法生效
+func (c *peerClientsMulticast) WithRetry(retry int, backoff time.Duration) PeerClientsMulticast {
+	c.retry = retry
+	c.backoff = backoff
+	return c
+}
+
+// 超过delay没有返回时发起对冲请求, 只对幂等的方法生效
+func (c *peerClientsMulticast) WithHedge(delay time.Duration) PeerClientsMulticast {
+	c.hedge = delay
+	return c
+}
+
+// 是否使用熔断, 默认开启
+func (c *peerClientsMulticast) WithBreaker(enabled bool) PeerClientsMulticast {
+	c.breaker = enabled
+	return c
+}
+
+func (c *peerClientsMulticast) callPolicy(idempotent bool) policy.Policy {
+	return policy.Policy{
+		Timeout:    c.timeout,
+		Retry:      c.retry,
+		Backoff:    c.backoff,
+		Hedge:      c.hedge,
+		Idempotent: idempotent,
+		Breaker:    c.breaker,
+	}
+}
+
 func (c *peerClientsMulticast) WhereRegex(regex string) PeerClientsMulticast {
 	c.regex = regex
 	return c
@@ -483,13 +630,68 @@ func (c *peerClientsMulticast) WherePrefix(prefix bool) PeerClientsMulticast {
 	return c
 }
 
+// 在指定的区查找服务, 默认只查找本区
+func (c *peerClientsMulticast) WhereZone(zone string) PeerClientsMulticast {
+	c.zone = zone
+	return c
+}
+
+// 在全部区查找服务, 本区优先
+func (c *peerClientsMulticast) WhereAllZone() PeerClientsMulticast {
+	c.allZone = true
+	return c
+}
+
+// 最多同时调用多少个节点, 只对Gather生效, 0表示不限制
+func (c *peerClientsMulticast) WithConcurrency(concurrency int) PeerClientsMulticast {
+	c.concurrency = concurrency
+	return c
+}
+
+// 成功多少个节点后返回, 只对Gather生效, 0表示等待全部节点
+func (c *peerClientsMulticast) WithQuorum(quorum int) PeerClientsMulticast {
+	c.quorum = quorum
+	return c
+}
+
+func (c *peerClientsMulticast) gatherOptions() scatter.Options {
+	return scatter.Options{
+		Concurrency: c.concurrency,
+		Quorum:      c.quorum,
+	}
+}
+
+// 查找要调用的节点, Gather不支持Local
+func (c *peerClientsMulticast) wherePeers() ([]*gira.Peer, error) {
+	var whereOpts []service_options.WhereOption
+	whereOpts = append(whereOpts, service_options.WithWhereCatalogOption())
+	if c.count > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereMaxCountOption(c.count))
+	}
+	serviceName := c.serviceName
+	if len(c.regex) > 0 {
+		serviceName = fmt.Sprintf("%s%s", c.serviceName, c.regex)
+		whereOpts = append(whereOpts, service_options.WithWhereRegexOption())
+	}
+	if c.prefix {
+		whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
+	}
+	if len(c.zone) > 0 {
+		whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+	}
+	if c.allZone {
+		whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+	}
+	return facade.WhereIsServiceName(serviceName, whereOpts...)
+}
+
 func (c *peerClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(PeerServer); ok {
 			result := &HealthCheckResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -519,6 +721,12 @@ func (c *peerClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -534,18 +742,18 @@ func (c *peerClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.HealthCheck(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(true), peer.FullName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+				return client.HealthCheck(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -553,13 +761,50 @@ func (c *peerClientsMulticast) HealthCheck(ctx context.Context, in *HealthCheckR
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *peerClientsMulticast) HealthCheckGather(ctx context.Context, in *HealthCheckRequest, f func(result *scatter.Result[*HealthCheckResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.healthCheckCall(in, opts...), f)
+}
+
+// 和HealthCheckGather一样, 结果通过channel返回
+func (c *peerClientsMulticast) HealthCheckGatherChan(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) *scatter.Stream[*HealthCheckResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*HealthCheckResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.healthCheckCall(in, opts...))
+}
+
+func (c *peerClientsMulticast) healthCheckCall(in *HealthCheckRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*HealthCheckResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*HealthCheckResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(true), peer.FullName, address, func(ctx context.Context) (*HealthCheckResponse, error) {
+			return client.HealthCheck(ctx, in, opts...)
+		})
+	}
+}
+
 func (c *peerClientsMulticast) MemStats(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) (*MemStatsResponse_MulticastResult, error) {
 	if c.local {
 		if s, ok := facade.WhereIsServer(c.client.serviceName); !ok {
 			return nil, errors.ErrServerNotFound
 		} else if svr, ok := s.(PeerServer); ok {
 			result := &MemStatsResponse_MulticastResult{}
-			cancelCtx, cancelFunc := context.WithTimeout(ctx, time.Second*time.Duration(c.timeout))
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, c.timeout)
 			defer cancelFunc()
 			if c.headers.Len() > 0 {
 				cancelCtx = metadata.NewOutgoingContext(cancelCtx, c.headers)
@@ -589,6 +834,12 @@ func (c *peerClientsMulticast) MemStats(ctx context.Context, in *MemStatsRequest
 		if c.prefix {
 			whereOpts = append(whereOpts, service_options.WithWherePrefixOption())
 		}
+		if len(c.zone) > 0 {
+			whereOpts = append(whereOpts, service_options.WithWhereZoneOption(c.zone))
+		}
+		if c.allZone {
+			whereOpts = append(whereOpts, service_options.WithWhereAllZoneOption())
+		}
 		peers, err := facade.WhereIsServiceName(serviceName, whereOpts...)
 		if err != nil {
 			return nil, err
@@ -604,18 +855,18 @@ func (c *peerClientsMulticast) MemStats(ctx context.Context, in *MemStatsRequest
 			}
 			client, err := c.client.getClient(address)
 			if err != nil {
-				result.errors = append(result.errors, err)
+				result.errors = append(result.errors, policy.Wrap(peer.FullName, err))
 				result.errorPeers = append(result.errorPeers, peer)
 				continue
 			}
-			out, err := client.MemStats(ctx, in, opts...)
+			out, err := policy.Invoke(ctx, c.callPolicy(true), peer.FullName, address, func(ctx context.Context) (*MemStatsResponse, error) {
+				return client.MemStats(ctx, in, opts...)
+			})
 			if err != nil {
 				result.errors = append(result.errors, err)
 				result.errorPeers = append(result.errorPeers, peer)
-				c.client.putClient(address, client)
 				continue
 			}
-			c.client.putClient(address, client)
 			result.responses = append(result.responses, out)
 			result.successPeers = append(result.successPeers, peer)
 		}
@@ -623,3 +874,39 @@ func (c *peerClientsMulticast) MemStats(ctx context.Context, in *MemStatsRequest
 	}
 
 }
+
+// 并发调用全部节点, 每个节点返回时调用f, 每个节点使用单独的超时
+func (c *peerClientsMulticast) MemStatsGather(ctx context.Context, in *MemStatsRequest, f func(result *scatter.Result[*MemStatsResponse]), opts ...grpc.CallOption) error {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return err
+	}
+	return scatter.Gather(ctx, peers, c.gatherOptions(), c.memStatsCall(in, opts...), f)
+}
+
+// 和MemStatsGather一样, 结果通过channel返回
+func (c *peerClientsMulticast) MemStatsGatherChan(ctx context.Context, in *MemStatsRequest, opts ...grpc.CallOption) *scatter.Stream[*MemStatsResponse] {
+	peers, err := c.wherePeers()
+	if err != nil {
+		return scatter.NewErrorStream[*MemStatsResponse](err)
+	}
+	return scatter.GatherChan(ctx, peers, c.gatherOptions(), c.memStatsCall(in, opts...))
+}
+
+func (c *peerClientsMulticast) memStatsCall(in *MemStatsRequest, opts ...grpc.CallOption) func(ctx context.Context, peer *gira.Peer) (*MemStatsResponse, error) {
+	return func(ctx context.Context, peer *gira.Peer) (*MemStatsResponse, error) {
+		var address string
+		if facade.IsEnableResolver() {
+			address = peer.Url
+		} else {
+			address = peer.Address
+		}
+		client, err := c.client.getClient(address)
+		if err != nil {
+			return nil, policy.Wrap(peer.FullName, err)
+		}
+		return policy.Invoke(ctx, c.callPolicy(true), peer.FullName, address, func(ctx context.Context) (*MemStatsResponse, error) {
+			return client.MemStats(ctx, in, opts...)
+		})
+	}
+}
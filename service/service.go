@@ -1,13 +1,27 @@
 package service
 
+///
+/// 服务容器
+///
+/// 每个服务在自己的协程中运行, 出错或者panic时按重启策略处理, 不影响其他服务
+///   - never: 不重启, 出错时状态变成failed
+///   - on-failure: 返回错误或者panic时重启
+///   - always: 正常返回也重启
+/// 重启时依次调用OnStop, OnStart, Serve, 两次重启之间按backoff等待
+///
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Lyndon-Zhang/gira"
 	"github.com/Lyndon-Zhang/gira/corelog"
 	"github.com/Lyndon-Zhang/gira/errors"
+	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -16,12 +30,26 @@ const (
 	service_status_stopped = 2
 )
 
+const (
+	default_restart_backoff     = 1 * time.Second
+	default_restart_max_backoff = 60 * time.Second
+	default_stop_timeout        = 10 * time.Second
+)
+
 type Service struct {
 	status     int32
 	name       string
 	handler    gira.Service
+	options    service_options.StartOptions
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+	done       chan struct{}
+
+	mu           sync.Mutex
+	state        string
+	restartCount int
+	lastError    string
+	startTime    int64
 }
 
 type ServiceContainer struct {
@@ -49,29 +77,39 @@ func (self *ServiceContainer) Serve() error {
 }
 
 // 启动服务
-func (self *ServiceContainer) StartService(name string, service gira.Service) error {
+func (self *ServiceContainer) StartService(name string, service gira.Service, opt ...service_options.StartOption) error {
 	corelog.Debugw("start service", "name", name)
 	s := &Service{
 		name:    name,
 		handler: service,
+		done:    make(chan struct{}),
+		options: service_options.StartOptions{
+			RestartPolicy: service_options.RESTART_NEVER,
+			Backoff:       default_restart_backoff,
+			MaxBackoff:    default_restart_max_backoff,
+			StopTimeout:   default_stop_timeout,
+		},
+	}
+	for _, v := range opt {
+		v.ConfigStartOption(&s.options)
 	}
 	if _, loaded := self.Services.LoadOrStore(service, s); loaded {
 		return errors.New("service already start", "name", name)
 	}
 	s.ctx, s.cancelFunc = context.WithCancel(self.ctx)
-	if err := service.OnStart(s.ctx); err != nil {
+	if err := s.start(); err != nil {
+		self.Services.Delete(service)
 		return err
 	}
 	s.status = service_status_started
 	self.errGroup.Go(func() error {
-		err := service.Serve()
-		service.OnStop()
-		return err
+		s.supervise()
+		return nil
 	})
 	return nil
 }
 
-// 停止服务
+// 停止服务, 等待OnStop返回
 func (self *ServiceContainer) StopService(service gira.Service) error {
 	if v, ok := self.Services.Load(service); !ok {
 		return errors.ErrServiceNotFound
@@ -82,11 +120,25 @@ func (self *ServiceContainer) StopService(service gira.Service) error {
 		} else {
 			corelog.Debugw("stop service", "name", s.name)
 			s.cancelFunc()
-			return nil
+			return s.wait()
 		}
 	}
 }
 
+// 全部服务的状态, 按名字排序
+func (self *ServiceContainer) ServiceStates() []*gira.ServiceState {
+	states := make([]*gira.ServiceState, 0)
+	self.Services.Range(func(key, value any) bool {
+		s := value.(*Service)
+		states = append(states, s.State())
+		return true
+	})
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
 // 停止服务并等待
 func (self *ServiceContainer) Stop() error {
 	self.Services.Range(func(key, value any) bool {
@@ -94,5 +146,154 @@ func (self *ServiceContainer) Stop() error {
 		s.cancelFunc()
 		return true
 	})
+	var err error
+	self.Services.Range(func(key, value any) bool {
+		s := value.(*Service)
+		if e := s.wait(); e != nil {
+			err = e
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
 	return self.errGroup.Wait()
 }
+
+func (s *Service) State() *gira.ServiceState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &gira.ServiceState{
+		Name:         s.name,
+		State:        s.state,
+		RestartCount: s.restartCount,
+		LastError:    s.lastError,
+		StartTime:    s.startTime,
+	}
+}
+
+func (s *Service) setState(state string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	if err != nil {
+		s.lastError = err.Error()
+	}
+	if state == gira.SERVICE_STATE_RUNNING {
+		s.startTime = time.Now().Unix()
+	}
+}
+
+// 等待服务的协程结束
+func (s *Service) wait() error {
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(s.options.StopTimeout):
+		corelog.Warnw("service stop timeout", "name", s.name, "timeout", s.options.StopTimeout)
+		return errors.ErrServiceStopTimeout
+	}
+}
+
+// 捕获panic, 打印堆栈后转成错误
+func (s *Service) catch(err *error) {
+	if e := recover(); e != nil {
+		corelog.Errorw("service panic", "name", s.name, "error", e, "stack", string(debug.Stack()))
+		*err = fmt.Errorf("%w: %v", errors.ErrServicePanic, e)
+	}
+}
+
+func (s *Service) start() (err error) {
+	defer s.catch(&err)
+	if err = s.handler.OnStart(s.ctx); err != nil {
+		return
+	}
+	s.setState(gira.SERVICE_STATE_RUNNING, nil)
+	return
+}
+
+// 运行Serve, 然后调用OnStop, 返回Serve的错误
+func (s *Service) serve() (err error) {
+	defer func() {
+		if e := s.stop(); e != nil {
+			corelog.Warnw("service on stop fail", "name", s.name, "error", e)
+		}
+	}()
+	defer s.catch(&err)
+	err = s.handler.Serve()
+	return
+}
+
+func (s *Service) stop() (err error) {
+	defer s.catch(&err)
+	return s.handler.OnStop()
+}
+
+func (s *Service) shouldRestart(err error) bool {
+	switch s.options.RestartPolicy {
+	case service_options.RESTART_ALWAYS:
+		return true
+	case service_options.RESTART_ON_FAILURE:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// 第一次的OnStart已经在StartService中调用
+func (s *Service) supervise() {
+	defer close(s.done)
+	backoff := s.options.Backoff
+	started := true
+	var err error
+	for {
+		var startTime time.Time
+		if started {
+			startTime = time.Now()
+			err = s.serve()
+		}
+		if s.ctx.Err() != nil {
+			s.setState(gira.SERVICE_STATE_STOPPED, err)
+			return
+		}
+		if err != nil {
+			corelog.Errorw("service fail", "name", s.name, "error", err)
+		}
+		if !s.shouldRestart(err) {
+			if err != nil {
+				s.setState(gira.SERVICE_STATE_FAILED, err)
+			} else {
+				s.setState(gira.SERVICE_STATE_STOPPED, nil)
+			}
+			return
+		}
+		s.mu.Lock()
+		restartCount := s.restartCount
+		s.mu.Unlock()
+		if s.options.MaxRestarts > 0 && restartCount >= s.options.MaxRestarts {
+			corelog.Errorw("service restart too many times", "name", s.name, "restart_count", restartCount)
+			s.setState(gira.SERVICE_STATE_FAILED, err)
+			return
+		}
+		// 运行了足够长的时间, 重新开始计算等待时间
+		if started && time.Since(startTime) > s.options.MaxBackoff {
+			backoff = s.options.Backoff
+		}
+		s.mu.Lock()
+		s.restartCount++
+		s.mu.Unlock()
+		s.setState(gira.SERVICE_STATE_RESTARTING, err)
+		corelog.Warnw("service restart", "name", s.name, "backoff", backoff, "restart_count", restartCount+1)
+		select {
+		case <-s.ctx.Done():
+			s.setState(gira.SERVICE_STATE_STOPPED, nil)
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > s.options.MaxBackoff {
+			backoff = s.options.MaxBackoff
+		}
+		err = s.start()
+		started = err == nil
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	"github.com/Lyndon-Zhang/gira/errors"
+	service_options "github.com/Lyndon-Zhang/gira/options/service_options"
+)
+
+// 前两次panic, 之后正常运行到停止
+type test_service struct {
+	ctx    context.Context
+	serves int32
+	stops  int32
+}
+
+func (s *test_service) OnStart(ctx context.Context) error {
+	s.ctx = ctx
+	return nil
+}
+
+func (s *test_service) Serve() error {
+	if atomic.AddInt32(&s.serves, 1) <= 2 {
+		panic("test panic")
+	}
+	<-s.ctx.Done()
+	return nil
+}
+
+func (s *test_service) OnStop() error {
+	atomic.AddInt32(&s.stops, 1)
+	return nil
+}
+
+func TestRestartOnFailure(t *testing.T) {
+	container := NewContainer(context.Background())
+	s := &test_service{}
+	other := &test_service{serves: 2}
+	if err := container.StartService("test", s, service_options.WithStartRestartOption(service_options.RESTART_ON_FAILURE, time.Millisecond, 10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.StartService("other", other); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&s.serves) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	states := container.ServiceStates()
+	if len(states) != 2 || states[0].Name != "other" || states[0].State != gira.SERVICE_STATE_RUNNING {
+		t.Fatalf("other service should keep running, got %+v", states[0])
+	}
+	if states[1].State != gira.SERVICE_STATE_RUNNING || states[1].RestartCount != 2 || len(states[1].LastError) <= 0 {
+		t.Fatalf("expected running after 2 restarts, got %+v", states[1])
+	}
+	if err := container.StopService(s); err != nil {
+		t.Fatal(err)
+	}
+	if v := atomic.LoadInt32(&s.stops); v != 3 {
+		t.Fatalf("expected 3 OnStop, got %d", v)
+	}
+	if state := container.ServiceStates()[1]; state.State != gira.SERVICE_STATE_STOPPED {
+		t.Fatalf("expected stopped, got %+v", state)
+	}
+	if err := container.StopService(s); err == nil {
+		t.Fatal("expected already stop")
+	}
+	if err := container.Stop(); err != nil && !errors.Is(err, errors.ErrServiceStopTimeout) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type real_clock struct {
+}
+
+func (c real_clock) Now() time.Time {
+	return time.Now()
+}
+
+// 系统时钟
+var RealClock Clock = real_clock{}
+
+// 虚拟时钟, 用于测试, 时间只在调用Advance时前进
+type VirtualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	wheels []*Wheel
+}
+
+func NewVirtualClock(now time.Time) *VirtualClock {
+	return &VirtualClock{
+		now: now,
+	}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// 推进时间, 同步驱动使用这个时钟的时间轮, 返回时到期的回调已经投递到收件箱
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	wheels := c.wheels
+	c.mu.Unlock()
+	for _, w := range wheels {
+		w.AdvanceTo(now)
+	}
+}
+
+func (c *VirtualClock) attach(w *Wheel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wheels = append(c.wheels, w)
+}
@@ -0,0 +1,288 @@
+package timer
+
+///
+/// actor的定时器
+///
+/// 回调投递到actor的收件箱, 和actor的其他请求在同一个协程中执行, 不需要加锁
+/// 持久化的定时器按key区分, 回调按kind注册, 状态可以和玩家数据一起保存, 加载后调用Restore恢复
+///
+import (
+	"container/list"
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/actor"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+// 定时器所属的actor
+type Owner interface {
+	Inbox() chan actor.Request
+}
+
+// 持久化定时器的回调
+type PersistentFunc func(state *TimerState)
+
+// 持久化定时器的状态
+type TimerState struct {
+	Key      string `bson:"key" json:"key"`
+	Kind     string `bson:"kind" json:"kind"`
+	ExpireAt int64  `bson:"expire_at" json:"expire_at"` // 到期时间, unix毫秒
+	Interval int64  `bson:"interval" json:"interval"`   // 周期, 毫秒, 0表示只执行一次
+	Data     []byte `bson:"data" json:"data"`
+}
+
+type Timer struct {
+	owner     *Timers
+	expire    int64 // 到期的tick
+	interval  int64 // 周期的tick数, 0表示只执行一次
+	f         func()
+	state     *TimerState
+	cancelled int32
+	slot      *list.List
+	elem      *list.Element
+}
+
+// 取消定时器, 已经投递到收件箱还没有执行的回调也不会执行
+func (t *Timer) Cancel() bool {
+	return t.owner.Cancel(t)
+}
+
+func (t *Timer) isCancelled() bool {
+	return atomic.LoadInt32(&t.cancelled) == 1
+}
+
+type timer_request struct {
+	timer *Timer
+}
+
+func (r *timer_request) Next() {
+	r.timer.owner.run(r.timer)
+}
+
+type Timers struct {
+	mu         sync.Mutex
+	wheel      *Wheel
+	owner      Owner
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	timers     map[*Timer]struct{}
+	persistent map[string]*Timer
+	handlers   map[string]PersistentFunc
+}
+
+func NewTimers(wheel *Wheel, owner Owner) *Timers {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	return &Timers{
+		wheel:      wheel,
+		owner:      owner,
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+		timers:     make(map[*Timer]struct{}),
+		persistent: make(map[string]*Timer),
+		handlers:   make(map[string]PersistentFunc),
+	}
+}
+
+// d之后执行一次f
+func (self *Timers) AfterFunc(d time.Duration, f func()) *Timer {
+	t := &Timer{owner: self, f: f}
+	self.add(t, d)
+	return t
+}
+
+// 每隔d执行一次f, 第一次在d之后
+func (self *Timers) Every(d time.Duration, f func()) *Timer {
+	t := &Timer{owner: self, f: f, interval: self.intervalTicks(d)}
+	self.add(t, d)
+	return t
+}
+
+func (self *Timers) Cancel(t *Timer) bool {
+	if !atomic.CompareAndSwapInt32(&t.cancelled, 0, 1) {
+		return false
+	}
+	self.wheel.remove(t)
+	self.mu.Lock()
+	delete(self.timers, t)
+	if t.state != nil && self.persistent[t.state.Key] == t {
+		delete(self.persistent, t.state.Key)
+	}
+	self.mu.Unlock()
+	return true
+}
+
+// 等待中的定时器数量
+func (self *Timers) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return len(self.timers)
+}
+
+// 取消全部定时器, actor退出时调用
+func (self *Timers) Stop() {
+	self.cancelFunc()
+	self.mu.Lock()
+	timers := make([]*Timer, 0, len(self.timers))
+	for t := range self.timers {
+		timers = append(timers, t)
+	}
+	self.mu.Unlock()
+	for _, t := range timers {
+		self.Cancel(t)
+	}
+}
+
+// 注册持久化定时器的回调
+func (self *Timers) Handle(kind string, f PersistentFunc) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.handlers[kind] = f
+}
+
+// 添加持久化的定时器, 相同key的定时器会被替换, interval为0时只执行一次
+func (self *Timers) Schedule(key string, kind string, d time.Duration, interval time.Duration, data []byte) error {
+	self.mu.Lock()
+	_, ok := self.handlers[kind]
+	old := self.persistent[key]
+	self.mu.Unlock()
+	if !ok {
+		return errors.ErrTimerHandlerNotFound
+	}
+	if old != nil {
+		self.Cancel(old)
+	}
+	t := &Timer{
+		owner: self,
+		state: &TimerState{
+			Key:      key,
+			Kind:     kind,
+			Interval: interval.Milliseconds(),
+			Data:     data,
+		},
+	}
+	if interval > 0 {
+		t.interval = self.intervalTicks(interval)
+	}
+	self.mu.Lock()
+	self.persistent[key] = t
+	self.mu.Unlock()
+	self.add(t, d)
+	return nil
+}
+
+// 删除持久化的定时器
+func (self *Timers) Remove(key string) bool {
+	self.mu.Lock()
+	t, ok := self.persistent[key]
+	self.mu.Unlock()
+	if !ok {
+		return false
+	}
+	return self.Cancel(t)
+}
+
+// 持久化定时器的状态, 按key排序
+func (self *Timers) States() []*TimerState {
+	self.mu.Lock()
+	timers := make([]*Timer, 0, len(self.persistent))
+	for _, t := range self.persistent {
+		timers = append(timers, t)
+	}
+	self.mu.Unlock()
+	states := make([]*TimerState, 0, len(timers))
+	for _, t := range timers {
+		state := *t.state
+		state.ExpireAt = self.wheel.expireAt(t).UnixMilli()
+		states = append(states, &state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Key < states[j].Key
+	})
+	return states
+}
+
+// 恢复持久化的定时器, 离线期间到期的马上执行一次
+func (self *Timers) Restore(states []*TimerState) error {
+	now := self.wheel.Now()
+	for _, state := range states {
+		d := time.UnixMilli(state.ExpireAt).Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		if err := self.Schedule(state.Key, state.Kind, d, time.Duration(state.Interval)*time.Millisecond, state.Data); err != nil {
+			log.Warnw("timer restore fail", "key", state.Key, "kind", state.Kind, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *Timers) intervalTicks(d time.Duration) int64 {
+	n := int64(d / self.wheel.tick)
+	if n <= 0 {
+		n = 1
+	}
+	return n
+}
+
+func (self *Timers) add(t *Timer, d time.Duration) {
+	self.mu.Lock()
+	self.timers[t] = struct{}{}
+	self.mu.Unlock()
+	self.wheel.add(t, d)
+}
+
+// 在时间轮的协程中调用, 收件箱满时在新的协程中等待, 不阻塞时间轮
+func (self *Timers) deliver(t *Timer) {
+	r := &timer_request{timer: t}
+	select {
+	case self.owner.Inbox() <- r:
+	default:
+		go func() {
+			select {
+			case self.owner.Inbox() <- r:
+			case <-self.ctx.Done():
+			}
+		}()
+	}
+}
+
+// 在actor的协程中调用
+func (self *Timers) run(t *Timer) {
+	if t.isCancelled() {
+		return
+	}
+	if t.interval == 0 {
+		atomic.StoreInt32(&t.cancelled, 1)
+		self.mu.Lock()
+		delete(self.timers, t)
+		if t.state != nil && self.persistent[t.state.Key] == t {
+			delete(self.persistent, t.state.Key)
+		}
+		self.mu.Unlock()
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			log.Errorw("timer panic", "error", e, "stack", string(debug.Stack()))
+		}
+	}()
+	if t.state == nil {
+		t.f()
+		return
+	}
+	self.mu.Lock()
+	f, ok := self.handlers[t.state.Kind]
+	self.mu.Unlock()
+	if !ok {
+		log.Errorw("timer handler not found", "key", t.state.Key, "kind", t.state.Kind)
+		return
+	}
+	state := *t.state
+	f(&state)
+}
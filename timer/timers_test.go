@@ -0,0 +1,135 @@
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/actor"
+)
+
+// 执行收件箱中全部的请求
+func drain(a *actor.Actor) {
+	for {
+		select {
+		case r := <-a.Inbox():
+			r.Next()
+		default:
+			return
+		}
+	}
+}
+
+func TestAfterFuncAndEvery(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(1700000000, 0))
+	wheel := NewWheel(10*time.Millisecond, clock)
+	a := actor.NewActor(1024)
+	timers := NewTimers(wheel, a)
+	fired := make(map[time.Duration]time.Time)
+	// 分布在不同的层
+	for _, d := range []time.Duration{30 * time.Millisecond, 700 * time.Millisecond, 50 * time.Second, 2 * time.Hour} {
+		d := d
+		timers.AfterFunc(d, func() {
+			fired[d] = clock.Now()
+		})
+	}
+	cancelled := timers.AfterFunc(time.Second, func() {
+		t.Fatal("cancelled timer fired")
+	})
+	var every int
+	timers.Every(time.Minute, func() {
+		every++
+	})
+	if !cancelled.Cancel() || cancelled.Cancel() {
+		t.Fatal("cancel should succeed only once")
+	}
+	start := clock.Now()
+	for i := 0; i < 3*60*60; i++ {
+		clock.Advance(time.Second)
+		drain(a)
+	}
+	for _, d := range []time.Duration{30 * time.Millisecond, 700 * time.Millisecond, 50 * time.Second, 2 * time.Hour} {
+		at, ok := fired[d]
+		if !ok {
+			t.Fatalf("timer %v not fired", d)
+		}
+		if elapsed := at.Sub(start); elapsed < d || elapsed >= d+time.Second {
+			t.Fatalf("timer %v fired at %v", d, elapsed)
+		}
+	}
+	if every != 180 {
+		t.Fatalf("expected 180 every, got %d", every)
+	}
+	if timers.Len() != 1 {
+		t.Fatalf("expected 1 timer left, got %d", timers.Len())
+	}
+	timers.Stop()
+	if timers.Len() != 0 {
+		t.Fatalf("expected no timer after stop, got %d", timers.Len())
+	}
+}
+
+func TestPersistent(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(1700000000, 0))
+	wheel := NewWheel(100*time.Millisecond, clock)
+	a := actor.NewActor(16)
+	timers := NewTimers(wheel, a)
+	keys := make([]string, 0)
+	handler := func(state *TimerState) {
+		keys = append(keys, state.Key+":"+string(state.Data))
+	}
+	timers.Handle("buff", handler)
+	if err := timers.Schedule("buff_1", "buff", time.Hour, 0, []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := timers.Schedule("build_1", "build", time.Hour, 0, nil); err == nil {
+		t.Fatal("expected handler not found")
+	}
+	clock.Advance(10 * time.Minute)
+	states := timers.States()
+	if len(states) != 1 || states[0].ExpireAt != clock.Now().Add(50*time.Minute).UnixMilli() {
+		t.Fatalf("unexpected states %+v", states)
+	}
+	timers.Stop()
+
+	// 下线2小时后重新上线
+	clock.Advance(2 * time.Hour)
+	drain(a)
+	timers = NewTimers(wheel, a)
+	timers.Handle("buff", handler)
+	if err := timers.Restore(states); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(100 * time.Millisecond)
+	drain(a)
+	if len(keys) != 1 || keys[0] != "buff_1:a" {
+		t.Fatalf("expected restored timer fired once, got %v", keys)
+	}
+	if len(timers.States()) != 0 {
+		t.Fatal("expected fired timer removed")
+	}
+}
+
+// 超过最上层范围的定时器在同一个槽中重新分配时不能死循环
+func TestOversizedDelay(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(1700000000, 0))
+	wheel := NewWheel(time.Millisecond, clock)
+	a := actor.NewActor(16)
+	timers := NewTimers(wheel, a)
+	d := time.Duration(1<<31+2<<24) * time.Millisecond
+	timers.AfterFunc(d, func() {})
+	timers.AfterFunc(d, func() {})
+	done := make(chan struct{})
+	go func() {
+		clock.Advance(time.Duration(2<<24+10) * time.Millisecond)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("wheel hung while cascading")
+	}
+	if timers.Len() != 2 {
+		t.Fatalf("expected 2 timers, got %d", timers.Len())
+	}
+	timers.Stop()
+}
@@ -0,0 +1,176 @@
+package timer
+
+///
+/// 分层时间轮
+///
+/// 每层64个槽, 第0层每个槽是1个tick, 第n层每个槽是64^n个tick, 共5层
+/// 第0层走完一圈时, 把上一层当前槽的定时器重新放到下层, 依次类推
+/// 超过最上层范围的定时器也放在最上层, 轮到时重新计算位置
+/// 定时器到期后把回调投递到所属actor的收件箱, 在actor的协程中执行
+///
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	wheel_bits   = 6
+	wheel_size   = 1 << wheel_bits
+	wheel_mask   = wheel_size - 1
+	wheel_levels = 5
+)
+
+type Wheel struct {
+	mu      sync.Mutex
+	tick    time.Duration
+	clock   Clock
+	start   time.Time
+	current int64 // 已经走过的tick
+	slots   [wheel_levels][wheel_size]*list.List
+}
+
+func NewWheel(tick time.Duration, clock Clock) *Wheel {
+	w := &Wheel{
+		tick:  tick,
+		clock: clock,
+		start: clock.Now(),
+	}
+	for level := 0; level < wheel_levels; level++ {
+		for i := 0; i < wheel_size; i++ {
+			w.slots[level][i] = list.New()
+		}
+	}
+	if c, ok := clock.(*VirtualClock); ok {
+		c.attach(w)
+	}
+	return w
+}
+
+// 使用系统时钟时启动驱动的协程, ctx取消后停止, 虚拟时钟由Advance驱动
+func (w *Wheel) Start(ctx context.Context) {
+	if _, ok := w.clock.(*VirtualClock); ok {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(w.tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.AdvanceTo(w.clock.Now())
+			}
+		}
+	}()
+}
+
+func (w *Wheel) Now() time.Time {
+	return w.clock.Now()
+}
+
+// 走到now对应的tick, 依次处理到期的定时器
+func (w *Wheel) AdvanceTo(now time.Time) {
+	target := int64(now.Sub(w.start) / w.tick)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.current < target {
+		w.step()
+	}
+}
+
+// 向上取整, 到期时间不会提前
+func (w *Wheel) ticks(at time.Time) int64 {
+	d := at.Sub(w.start)
+	n := int64(d / w.tick)
+	if d%w.tick > 0 {
+		n++
+	}
+	return n
+}
+
+func (w *Wheel) timeOf(tick int64) time.Time {
+	return w.start.Add(time.Duration(tick) * w.tick)
+}
+
+func (w *Wheel) add(t *Timer, d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t.expire = w.ticks(w.clock.Now().Add(d))
+	if t.expire <= w.current {
+		t.expire = w.current + 1
+	}
+	w.place(t)
+}
+
+func (w *Wheel) remove(t *Timer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t.slot != nil {
+		t.slot.Remove(t.elem)
+		t.slot = nil
+		t.elem = nil
+	}
+}
+
+func (w *Wheel) expireAt(t *Timer) time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.timeOf(t.expire)
+}
+
+func (w *Wheel) place(t *Timer) {
+	delta := t.expire - w.current
+	level := 0
+	for level < wheel_levels-1 && delta >= 1<<(wheel_bits*(level+1)) {
+		level++
+	}
+	t.slot = w.slots[level][(t.expire>>(wheel_bits*level))&wheel_mask]
+	t.elem = t.slot.PushBack(t)
+}
+
+func (w *Wheel) step() {
+	w.current++
+	c := w.current
+	// 下层走完一圈, 上层的当前槽重新分配
+	for level := 1; level < wheel_levels; level++ {
+		if c&(1<<(wheel_bits*level)-1) != 0 {
+			break
+		}
+		// 先换上新的槽再遍历, 超过最上层范围的定时器可能重新放回同一个槽
+		slot := w.detach(level, (c>>(wheel_bits*level))&wheel_mask)
+		for e := slot.Front(); e != nil; e = slot.Front() {
+			t := slot.Remove(e).(*Timer)
+			w.place(t)
+		}
+	}
+	slot := w.detach(0, c&wheel_mask)
+	for e := slot.Front(); e != nil; e = slot.Front() {
+		t := slot.Remove(e).(*Timer)
+		t.slot = nil
+		t.elem = nil
+		w.fire(t)
+	}
+}
+
+func (w *Wheel) detach(level int, index int64) *list.List {
+	slot := w.slots[level][index]
+	w.slots[level][index] = list.New()
+	return slot
+}
+
+func (w *Wheel) fire(t *Timer) {
+	if t.isCancelled() {
+		return
+	}
+	if t.interval > 0 {
+		t.expire += t.interval
+		if t.expire <= w.current {
+			t.expire = w.current + 1
+		}
+		w.place(t)
+	}
+	t.owner.deliver(t)
+}
@@ -0,0 +1,305 @@
+package trace
+
+///
+/// span导出
+///   - file: 每个span一行json
+///   - otlp: otlp/http json格式, 发送到collector, 例如 http://127.0.0.1:4318/v1/traces
+///
+/// span先放到队列中, 由Serve批量导出, 队列满时丢弃
+///
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira"
+	log "github.com/Lyndon-Zhang/gira/corelog"
+)
+
+const (
+	EXPORTER_FILE = "file"
+	EXPORTER_OTLP = "otlp"
+)
+
+const (
+	DEFAULT_BATCH_SIZE     = 256
+	DEFAULT_QUEUE_SIZE     = 4096
+	DEFAULT_FLUSH_INTERVAL = 5 // 秒
+)
+
+type Exporter interface {
+	Export(spans []*Span) error
+	Close() error
+}
+
+type processor struct {
+	config   gira.TraceConfig
+	exporter Exporter
+	queue    chan *Span
+	dropped  int64
+}
+
+var defaultProcessor atomic.Value
+
+// 配置追踪, 在启动时调用
+func Config(config gira.TraceConfig, serviceName string) error {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DEFAULT_BATCH_SIZE
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DEFAULT_QUEUE_SIZE
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DEFAULT_FLUSH_INTERVAL
+	}
+	var exporter Exporter
+	switch config.Exporter {
+	case EXPORTER_FILE:
+		if e, err := NewFileExporter(config.File); err != nil {
+			return err
+		} else {
+			exporter = e
+		}
+	case EXPORTER_OTLP:
+		exporter = NewOtlpExporter(config.Endpoint, serviceName)
+	case "":
+	default:
+		return fmt.Errorf("invalid trace exporter %s", config.Exporter)
+	}
+	setSampleRatio(config.SampleRatio)
+	if exporter != nil {
+		defaultProcessor.Store(&processor{
+			config:   config,
+			exporter: exporter,
+			queue:    make(chan *Span, config.QueueSize),
+		})
+	}
+	return nil
+}
+
+// 批量导出span, 直到ctx结束
+func Serve(ctx context.Context) error {
+	p, ok := defaultProcessor.Load().(*processor)
+	if !ok {
+		return nil
+	}
+	ticker := time.NewTicker(time.Duration(p.config.FlushInterval) * time.Second)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, p.config.BatchSize)
+	flush := func() {
+		if len(batch) <= 0 {
+			return
+		}
+		if err := p.exporter.Export(batch); err != nil {
+			log.Warnw("trace export fail", "count", len(batch), "error", err)
+		}
+		batch = make([]*Span, 0, p.config.BatchSize)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			// 导出剩下的span
+		drain:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					break drain
+				}
+			}
+			flush()
+			return p.exporter.Close()
+		case span := <-p.queue:
+			if batch = append(batch, span); len(batch) >= p.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			if dropped := atomic.SwapInt64(&p.dropped, 0); dropped > 0 {
+				log.Warnw("trace queue full, span dropped", "count", dropped)
+			}
+			flush()
+		}
+	}
+}
+
+func export(span *Span) {
+	p, ok := defaultProcessor.Load().(*processor)
+	if !ok {
+		return
+	}
+	select {
+	case p.queue <- span:
+	default:
+		atomic.AddInt64(&p.dropped, 1)
+	}
+}
+
+type file_span struct {
+	TraceId      string            `json:"trace_id"`
+	SpanId       string            `json:"span_id"`
+	ParentSpanId string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	Kind         int               `json:"kind"`
+	StartTime    int64             `json:"start_time"` // unix nano
+	Duration     int64             `json:"duration"`   // 纳秒
+	Error        string            `json:"error,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+type FileExporter struct {
+	f *os.File
+}
+
+func NewFileExporter(name string) (*FileExporter, error) {
+	if len(name) <= 0 {
+		return nil, fmt.Errorf("trace file is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{f: f}, nil
+}
+
+func (self *FileExporter) Export(spans []*Span) error {
+	buf := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buf)
+	for _, span := range spans {
+		if err := encoder.Encode(&file_span{
+			TraceId:      span.TraceId,
+			SpanId:       span.SpanId,
+			ParentSpanId: span.ParentSpanId,
+			Name:         span.Name,
+			Kind:         span.Kind,
+			StartTime:    span.StartTime.UnixNano(),
+			Duration:     int64(span.EndTime.Sub(span.StartTime)),
+			Error:        span.Error,
+			Attributes:   span.Attributes(),
+		}); err != nil {
+			return err
+		}
+	}
+	_, err := self.f.Write(buf.Bytes())
+	return err
+}
+
+func (self *FileExporter) Close() error {
+	return self.f.Close()
+}
+
+// otlp/http json格式
+type otlp_value struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlp_attribute struct {
+	Key   string     `json:"key"`
+	Value otlp_value `json:"value"`
+}
+
+type otlp_status struct {
+	Code    int    `json:"code,omitempty"` // 2表示错误
+	Message string `json:"message,omitempty"`
+}
+
+type otlp_span struct {
+	TraceId           string           `json:"traceId"`
+	SpanId            string           `json:"spanId"`
+	ParentSpanId      string           `json:"parentSpanId,omitempty"`
+	Name              string           `json:"name"`
+	Kind              int              `json:"kind"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string           `json:"endTimeUnixNano"`
+	Attributes        []otlp_attribute `json:"attributes,omitempty"`
+	Status            otlp_status      `json:"status"`
+}
+
+type otlp_scope_spans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlp_span `json:"spans"`
+}
+
+type otlp_resource_spans struct {
+	Resource struct {
+		Attributes []otlp_attribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlp_scope_spans `json:"scopeSpans"`
+}
+
+type otlp_request struct {
+	ResourceSpans []otlp_resource_spans `json:"resourceSpans"`
+}
+
+type OtlpExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+func NewOtlpExporter(endpoint string, serviceName string) *OtlpExporter {
+	return &OtlpExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (self *OtlpExporter) Export(spans []*Span) error {
+	scope := otlp_scope_spans{
+		Spans: make([]otlp_span, 0, len(spans)),
+	}
+	scope.Scope.Name = "gira"
+	for _, span := range spans {
+		s := otlp_span{
+			TraceId:           span.TraceId,
+			SpanId:            span.SpanId,
+			ParentSpanId:      span.ParentSpanId,
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		}
+		for k, v := range span.Attributes() {
+			s.Attributes = append(s.Attributes, otlp_attribute{Key: k, Value: otlp_value{StringValue: v}})
+		}
+		if len(span.Error) > 0 {
+			s.Status.Code = 2
+			s.Status.Message = span.Error
+		}
+		scope.Spans = append(scope.Spans, s)
+	}
+	resource := otlp_resource_spans{
+		ScopeSpans: []otlp_scope_spans{scope},
+	}
+	resource.Resource.Attributes = []otlp_attribute{{Key: "service.name", Value: otlp_value{StringValue: self.serviceName}}}
+	data, err := json.Marshal(&otlp_request{ResourceSpans: []otlp_resource_spans{resource}})
+	if err != nil {
+		return err
+	}
+	resp, err := self.client.Post(self.endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("otlp export fail, status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (self *OtlpExporter) Close() error {
+	return nil
+}
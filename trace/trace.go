@@ -0,0 +1,211 @@
+package trace
+
+///
+/// 请求追踪
+///
+/// 网关收到客户端请求时生成trace id, 经过hallpb的消息字段和grpc的metadata传递到后面的节点
+/// 每一跳生成一个span, span结束后交给exporter导出到文件或者otlp collector
+///
+/// 采样只在trace的起点决定, 后面的节点沿用起点的结果
+/// 没有采样的span也会生成id, 方便在日志中串联请求, 只是不导出
+///
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	mrand "math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Lyndon-Zhang/gira/corelog"
+)
+
+// grpc metadata中的键
+const (
+	TRACE_ID_KEY = "gira-trace-id"
+	SPAN_ID_KEY  = "gira-span-id"
+	SAMPLED_KEY  = "gira-sampled"
+)
+
+const (
+	SPAN_KIND_INTERNAL = 1
+	SPAN_KIND_SERVER   = 2
+	SPAN_KIND_CLIENT   = 3
+)
+
+type Span struct {
+	TraceId      string
+	SpanId       string
+	ParentSpanId string
+	Sampled      bool
+	Name         string
+	Kind         int
+	StartTime    time.Time
+	EndTime      time.Time
+	Error        string
+	mu           sync.Mutex
+	attributes   map[string]string
+	ended        bool
+}
+
+type span_key struct{}
+
+// 上游传过来的span, 只有id
+type remote_span struct {
+	traceId string
+	spanId  string
+	sampled bool
+}
+
+type remote_key struct{}
+
+// 采样比例, 没有配置时不导出, 保存float64的位
+var sampleRatio uint64
+
+func setSampleRatio(ratio float64) {
+	atomic.StoreUint64(&sampleRatio, math.Float64bits(ratio))
+}
+
+func init() {
+	log.SetContextFields(Fields)
+}
+
+// 开始一个span, 父span来自ctx, 没有时开始一个新的trace
+func StartSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	span := &Span{
+		SpanId:    newSpanId(),
+		Name:      name,
+		Kind:      kind,
+		StartTime: time.Now(),
+	}
+	if parent := FromContext(ctx); parent != nil {
+		span.TraceId = parent.TraceId
+		span.ParentSpanId = parent.SpanId
+		span.Sampled = parent.Sampled
+	} else if remote, ok := ctx.Value(remote_key{}).(*remote_span); ok {
+		span.TraceId = remote.traceId
+		span.ParentSpanId = remote.spanId
+		span.Sampled = remote.sampled
+	} else {
+		span.TraceId = newTraceId()
+		span.Sampled = sample()
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// 返回ctx中的span
+func FromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	if span, ok := ctx.Value(span_key{}).(*Span); ok {
+		return span
+	}
+	return nil
+}
+
+// 将span保存到ctx中
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, span_key{}, span)
+}
+
+// 将上游传过来的id保存到ctx中, 之后的StartSpan会成为它的子span
+func ContextWithRemote(ctx context.Context, traceId string, spanId string, sampled bool) context.Context {
+	if len(traceId) <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, remote_key{}, &remote_span{
+		traceId: traceId,
+		spanId:  spanId,
+		sampled: sampled,
+	})
+}
+
+// 日志字段, 注册到corelog中
+func Fields(ctx context.Context) []interface{} {
+	if span := FromContext(ctx); span != nil {
+		return []interface{}{"trace_id", span.TraceId, "span_id", span.SpanId}
+	}
+	return nil
+}
+
+func (span *Span) SetAttribute(key string, value string) {
+	span.mu.Lock()
+	if span.attributes == nil {
+		span.attributes = make(map[string]string)
+	}
+	span.attributes[key] = value
+	span.mu.Unlock()
+}
+
+func (span *Span) Attributes() map[string]string {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	attributes := make(map[string]string, len(span.attributes))
+	for k, v := range span.attributes {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+func (span *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	span.mu.Lock()
+	span.Error = err.Error()
+	span.mu.Unlock()
+}
+
+// 结束span, 采样的span交给exporter, 多次调用只生效一次
+func (span *Span) End() {
+	span.mu.Lock()
+	if span.ended {
+		span.mu.Unlock()
+		return
+	}
+	span.ended = true
+	span.EndTime = time.Now()
+	span.mu.Unlock()
+	if span.Sampled {
+		export(span)
+	}
+}
+
+func sample() bool {
+	ratio := math.Float64frombits(atomic.LoadUint64(&sampleRatio))
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return mrand.Float64() < ratio
+}
+
+// 16字节, otlp格式
+func newTraceId() string {
+	var b [16]byte
+	randBytes(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// 8字节, otlp格式
+func newSpanId() string {
+	var b [8]byte
+	randBytes(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func randBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		for i := 0; i < len(b); i += 8 {
+			var v [8]byte
+			binary.LittleEndian.PutUint64(v[:], mrand.Uint64())
+			copy(b[i:], v[:])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package trace
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStartSpan(t *testing.T) {
+	ctx := ContextWithRemote(context.Background(), "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331", true)
+	ctx, span := StartSpan(ctx, "hall/request", SPAN_KIND_SERVER)
+	if span.TraceId != "0af7651916cd43dd8448eb211c80319c" || span.ParentSpanId != "b7ad6b7169203331" || !span.Sampled {
+		t.Fatalf("remote parent not used %+v", span)
+	}
+	_, child := StartSpan(ctx, "grpc", SPAN_KIND_CLIENT)
+	if child.TraceId != span.TraceId || child.ParentSpanId != span.SpanId {
+		t.Fatalf("child span should inherit trace %+v", child)
+	}
+	fields := Fields(ctx)
+	if len(fields) != 4 || fields[1] != span.TraceId || fields[3] != span.SpanId {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
+
+func TestSample(t *testing.T) {
+	defer setSampleRatio(0)
+	setSampleRatio(0)
+	if _, span := StartSpan(context.Background(), "gateway/request", SPAN_KIND_SERVER); span.Sampled || len(span.TraceId) != 32 || len(span.SpanId) != 16 {
+		t.Fatalf("unexpected span %+v", span)
+	}
+	setSampleRatio(1)
+	if _, span := StartSpan(context.Background(), "gateway/request", SPAN_KIND_SERVER); !span.Sampled {
+		t.Fatalf("span should be sampled")
+	}
+}
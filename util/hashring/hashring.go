@@ -0,0 +1,66 @@
+package hashring
+
+/// 一致性hash环
+/// 每个成员映射到环上的多个虚拟节点, 成员增减时只影响相邻区间的key
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// 默认虚拟节点数量
+const DEFAULT_REPLICAS int = 160
+
+type Ring struct {
+	replicas int
+	hashes   []uint32
+	members  map[uint32]string
+}
+
+// 创建hash环, replicas <= 0 时使用默认值
+func New(replicas int, members ...string) *Ring {
+	if replicas <= 0 {
+		replicas = DEFAULT_REPLICAS
+	}
+	ring := &Ring{
+		replicas: replicas,
+		hashes:   make([]uint32, 0, replicas*len(members)),
+		members:  make(map[uint32]string, replicas*len(members)),
+	}
+	for _, member := range members {
+		for i := 0; i < replicas; i++ {
+			hash := crc32.ChecksumIEEE([]byte(member + "#" + strconv.Itoa(i)))
+			// hash冲突时保留先加入的成员
+			if _, ok := ring.members[hash]; ok {
+				continue
+			}
+			ring.members[hash] = member
+			ring.hashes = append(ring.hashes, hash)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool {
+		return ring.hashes[i] < ring.hashes[j]
+	})
+	return ring
+}
+
+// 是否为空
+func (ring *Ring) Empty() bool {
+	return len(ring.hashes) <= 0
+}
+
+// 根据key查找成员
+func (ring *Ring) Get(key string) (string, bool) {
+	if len(ring.hashes) <= 0 {
+		return "", false
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	index := sort.Search(len(ring.hashes), func(i int) bool {
+		return ring.hashes[i] >= hash
+	})
+	if index == len(ring.hashes) {
+		index = 0
+	}
+	return ring.members[ring.hashes[index]], true
+}
@@ -0,0 +1,47 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingGet(t *testing.T) {
+	ring := New(0)
+	if _, ok := ring.Get("1001"); ok {
+		t.Fatalf("empty ring should not match")
+	}
+	ring = New(0, "hall/1", "hall/2", "hall/3")
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		member, ok := ring.Get(fmt.Sprintf("room_%d", i))
+		if !ok {
+			t.Fatalf("key not match")
+		}
+		counts[member]++
+	}
+	for member, count := range counts {
+		if count < 500 {
+			t.Fatalf("member %s too few keys %d", member, count)
+		}
+	}
+}
+
+func TestRingStable(t *testing.T) {
+	ring1 := New(0, "hall/1", "hall/2", "hall/3")
+	ring2 := New(0, "hall/1", "hall/2", "hall/3", "hall/4")
+	moved := 0
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("room_%d", i)
+		member1, _ := ring1.Get(key)
+		member2, _ := ring2.Get(key)
+		if member1 != member2 {
+			if member2 != "hall/4" {
+				t.Fatalf("key %s moved from %s to %s", key, member1, member2)
+			}
+			moved++
+		}
+	}
+	if moved <= 0 || moved > 1500 {
+		t.Fatalf("unexpected moved keys %d", moved)
+	}
+}
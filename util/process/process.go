@@ -0,0 +1,85 @@
+package process
+
+///
+/// 进程管理, pid文件和后台启动的参数, 不依赖项目目录
+///
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+// 先写临时文件再改名, 读的时候不会读到一半
+func WritePidFile(filePath string, pid int) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	tmpFilePath := fmt.Sprintf("%s.%d", filePath, os.Getpid())
+	if err := os.WriteFile(tmpFilePath, []byte(strconv.Itoa(pid)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, filePath)
+}
+
+func ReadPidFile(filePath string) (int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// 文件中的pid和参数一致才删除, 零停机重启时新进程已经写入了自己的pid
+func RemovePidFile(filePath string, pid int) error {
+	if v, err := ReadPidFile(filePath); err != nil {
+		return err
+	} else if v != pid {
+		return nil
+	}
+	return os.Remove(filePath)
+}
+
+// 进程是否存在
+func IsAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// 发送SIGTERM, 每隔interval检查一次, 进程退出并且stopped返回true时返回
+func Terminate(pid int, timeout time.Duration, interval time.Duration, stopped func() bool) error {
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if !IsAlive(pid) && (stopped == nil || stopped()) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.ErrProcessStopTimeout
+		}
+		time.Sleep(interval)
+	}
+}
+
+// 去掉后台启动的参数 -d, --daemon, --daemon=true
+func DaemonArgs(args []string) []string {
+	result := make([]string, 0, len(args))
+	for _, v := range args {
+		name := strings.SplitN(strings.TrimLeft(v, "-"), "=", 2)[0]
+		if strings.HasPrefix(v, "-") && (name == "d" || name == "daemon") {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
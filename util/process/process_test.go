@@ -0,0 +1,103 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Lyndon-Zhang/gira/errors"
+)
+
+func TestDaemonArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"short", []string{"start", "-d"}, []string{"start"}},
+		{"long", []string{"start", "--daemon"}, []string{"start"}},
+		{"long with value", []string{"start", "--daemon=true"}, []string{"start"}},
+		{"single dash long", []string{"-daemon", "start"}, []string{"start"}},
+		{"keep other flags", []string{"--id", "1", "start", "-d", "--debug", "--dir=/tmp"}, []string{"--id", "1", "start", "--debug", "--dir=/tmp"}},
+		{"keep flag with d prefix", []string{"start", "--dry-run", "-dd"}, []string{"start", "--dry-run", "-dd"}},
+		{"keep positional", []string{"start", "d", "daemon"}, []string{"start", "d", "daemon"}},
+		{"empty", nil, []string{}},
+	}
+	for _, tt := range tests {
+		if v := DaemonArgs(tt.args); !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, v)
+		}
+	}
+}
+
+func TestPidFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "run", "hall_1.pid")
+	if _, err := ReadPidFile(filePath); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+	if err := WritePidFile(filePath, 100); err != nil {
+		t.Fatal(err)
+	}
+	if pid, err := ReadPidFile(filePath); err != nil || pid != 100 {
+		t.Fatalf("expected 100, got %d %v", pid, err)
+	}
+	// 零停机重启, 新进程写入了自己的pid, 旧进程退出时不能删除
+	if err := WritePidFile(filePath, 200); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemovePidFile(filePath, 100); err != nil {
+		t.Fatal(err)
+	}
+	if pid, err := ReadPidFile(filePath); err != nil || pid != 200 {
+		t.Fatalf("expected 200 kept, got %d %v", pid, err)
+	}
+	if err := RemovePidFile(filePath, 200); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Fatalf("expected removed, got %v", err)
+	}
+	// 没有留下临时文件
+	if entries, err := os.ReadDir(filepath.Dir(filePath)); err != nil || len(entries) != 0 {
+		t.Fatalf("unexpected files %v %v", entries, err)
+	}
+}
+
+func TestIsAlive(t *testing.T) {
+	if !IsAlive(os.Getpid()) {
+		t.Fatal("expected self alive")
+	}
+	if IsAlive(0) || IsAlive(-1) {
+		t.Fatal("expected invalid pid not alive")
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	start := func() int {
+		cmd := exec.Command("sleep", "10")
+		if err := cmd.Start(); err != nil {
+			t.Skip(err)
+		}
+		// 回收子进程, 否则退出后还是僵尸进程
+		go cmd.Wait()
+		return cmd.Process.Pid
+	}
+	// 进程退出了, 但是还没有注销
+	pid := start()
+	if err := Terminate(pid, 100*time.Millisecond, 10*time.Millisecond, func() bool { return false }); err != errors.ErrProcessStopTimeout {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if IsAlive(pid) {
+		t.Fatal("expected process exited")
+	}
+	pid = start()
+	if err := Terminate(pid, time.Second, 10*time.Millisecond, func() bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if IsAlive(pid) {
+		t.Fatal("expected process exited")
+	}
+}